@@ -1,6 +1,10 @@
 package engine
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type Suit int
 
@@ -82,15 +86,81 @@ func (c Card) Domain() string {
 	return SuitDomains[c.Suit]
 }
 
+// MarshalText encodes the card as its display string, e.g. "Q♠".
+func (c Card) MarshalText() ([]byte, error) { return []byte(c.String()), nil }
+
+func (c *Card) UnmarshalText(text []byte) error {
+	parsed, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+var suitLetters = map[string]Suit{
+	"♣": Clubs, "c": Clubs,
+	"♦": Diamonds, "d": Diamonds,
+	"♠": Spades, "s": Spades,
+	"♥": Hearts, "h": Hearts,
+}
+
+// ParseCard reads a card written as rank then suit, e.g. "Q♠", "10h", "TD",
+// or "Joker". Suits may be symbols or their initial letter.
+func ParseCard(s string) (Card, error) {
+	clean := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	if clean == "joker" {
+		return Card{Rank: RankJoker}, nil
+	}
+	for sym, suit := range suitLetters {
+		rankStr, ok := strings.CutSuffix(clean, sym)
+		if !ok || rankStr == "" {
+			continue
+		}
+		if rank, ok := parseRank(rankStr); ok {
+			return Card{Rank: rank, Suit: suit}, nil
+		}
+	}
+	return Card{}, fmt.Errorf("invalid card: %q", s)
+}
+
+func parseRank(s string) (Rank, bool) {
+	switch s {
+	case "t":
+		return RankTen, true
+	case "j":
+		return RankJack, true
+	case "q":
+		return RankQueen, true
+	case "k":
+		return RankKing, true
+	case "a":
+		return RankAce, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 2 || n > 10 {
+		return 0, false
+	}
+	return Rank(n), true
+}
+
 type DrawResult struct {
 	Card       Card
 	JokerDrawn bool
 	JokerEvent *RandomEventResult
+	Dice       *CardDice // set when the card was rolled rather than drawn
+}
+
+// CardSource supplies cards to a Deck in place of its shuffled stack, e.g.
+// dice rolls under the "use only dice" rule.
+type CardSource interface {
+	DrawCard() DrawResult
 }
 
 type Deck struct {
-	cards []Card
-	rng   *Randomizer
+	cards  []Card
+	rng    *Randomizer
+	source CardSource
 }
 
 func NewDeck(rng *Randomizer) *Deck {
@@ -99,6 +169,11 @@ func NewDeck(rng *Randomizer) *Deck {
 	return d
 }
 
+// RestoreDeck rebuilds a deck with the given cards remaining, in draw order.
+func RestoreDeck(rng *Randomizer, cards []Card) *Deck {
+	return &Deck{rng: rng, cards: append([]Card(nil), cards...)}
+}
+
 func (d *Deck) Shuffle() {
 	d.cards = make([]Card, 0, 54)
 	suits := []Suit{Clubs, Diamonds, Spades, Hearts}
@@ -114,15 +189,33 @@ func (d *Deck) Shuffle() {
 	}
 }
 
+// SetSource routes Draw through src. A nil src restores the shuffled deck.
+func (d *Deck) SetSource(src CardSource) { d.source = src }
+
 func (d *Deck) Draw(eventFn func() *RandomEventResult) DrawResult {
+	var drawn DrawResult
+	if d.source != nil {
+		d.rng.recordSourced(func() TraceStep {
+			drawn = d.source.DrawCard()
+			return TraceStep{Kind: StepCard, Card: drawn.Card}
+		})
+		return d.resolve(drawn, eventFn)
+	}
 	if len(d.cards) == 0 {
 		d.Shuffle()
+		d.rng.record(TraceStep{Kind: StepShuffle, Label: "deck empty"})
 	}
-	card := d.cards[0]
+	drawn.Card = d.cards[0]
 	d.cards = d.cards[1:]
+	d.rng.record(TraceStep{Kind: StepCard, Card: drawn.Card})
+	return d.resolve(drawn, eventFn)
+}
 
-	if card.IsJoker() {
+// resolve applies the Joker rule: shuffle, add a random event, draw again.
+func (d *Deck) resolve(drawn DrawResult, eventFn func() *RandomEventResult) DrawResult {
+	if drawn.Card.IsJoker() {
 		d.Shuffle()
+		d.rng.record(TraceStep{Kind: StepShuffle, Label: "Joker"})
 		var evt *RandomEventResult
 		if eventFn != nil {
 			evt = eventFn()
@@ -132,7 +225,10 @@ func (d *Deck) Draw(eventFn func() *RandomEventResult) DrawResult {
 		result.JokerEvent = evt
 		return result
 	}
-	return DrawResult{Card: card}
+	return drawn
 }
 
 func (d *Deck) Remaining() int { return len(d.cards) }
+
+// Cards returns a copy of the remaining cards in draw order.
+func (d *Deck) Cards() []Card { return append([]Card(nil), d.cards...) }
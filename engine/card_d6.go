@@ -0,0 +1,55 @@
+package engine
+
+// CardRoll records a d6 produced by a card draw under the "use only cards"
+// rule: the rank divided by 2, rounded down, with Aces discarded.
+type CardRoll struct {
+	Card      Card
+	Value     int
+	Discarded []Card // Aces set aside before Card was drawn
+	Joker     bool   // a Joker reshuffled the deck before Card was drawn
+	// Event is the Random Event the Joker called for.
+	Event *RandomEventResult
+}
+
+// CardD6 is a D6Source that draws from the engine deck instead of rolling.
+type CardD6 struct {
+	deck  *Deck
+	rolls []CardRoll
+}
+
+func NewCardD6(deck *Deck) *CardD6 {
+	return &CardD6{deck: deck}
+}
+
+func (c *CardD6) RollD6() int {
+	var roll CardRoll
+	for {
+		draw := c.deck.Draw(c.jokerEvent)
+		roll.Joker = roll.Joker || draw.JokerDrawn
+		if draw.JokerEvent != nil {
+			roll.Event = draw.JokerEvent
+		}
+		if draw.Card.Rank == RankAce {
+			roll.Discarded = append(roll.Discarded, draw.Card)
+			continue
+		}
+		roll.Card = draw.Card
+		roll.Value = int(draw.Card.Rank) / 2
+		c.rolls = append(c.rolls, roll)
+		return roll.Value
+	}
+}
+
+// jokerEvent rolls the Random Event a Joker drawn for a d6 calls for, as
+// it would for any other draw.
+func (c *CardD6) jokerEvent() *RandomEventResult {
+	evt := RandomEvent(c.deck, c.deck.rng)
+	return &evt
+}
+
+// TakeRolls returns the card rolls made since the last call and clears them.
+func (c *CardD6) TakeRolls() []CardRoll {
+	rolls := c.rolls
+	c.rolls = nil
+	return rolls
+}
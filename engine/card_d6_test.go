@@ -0,0 +1,62 @@
+package engine
+
+import "testing"
+
+func TestCardD6ValuesFromRank(t *testing.T) {
+	rng := NewSeededRandomizer(80, 0)
+	src := NewCardD6(NewDeck(rng))
+	rng.SetD6Source(src)
+	for range 200 {
+		v := rng.RollD6()
+		if v < 1 || v > 6 {
+			t.Fatalf("card d6 returned %d", v)
+		}
+	}
+	rolls := src.TakeRolls()
+	if len(rolls) != 200 {
+		t.Fatalf("recorded %d rolls, want 200", len(rolls))
+	}
+	for _, r := range rolls {
+		if r.Card.Rank == RankAce || r.Card.IsJoker() {
+			t.Errorf("roll used %s", r.Card)
+		}
+		if r.Value != int(r.Card.Rank)/2 {
+			t.Errorf("%s gave %d, want %d", r.Card, r.Value, int(r.Card.Rank)/2)
+		}
+		for _, d := range r.Discarded {
+			if d.Rank != RankAce {
+				t.Errorf("discarded non-Ace %s", d)
+			}
+		}
+	}
+	if len(src.TakeRolls()) != 0 {
+		t.Error("TakeRolls should clear the record")
+	}
+}
+
+func TestCardD6DrivesGenerators(t *testing.T) {
+	rng := NewSeededRandomizer(81, 0)
+	deck := NewDeck(rng)
+	src := NewCardD6(deck)
+	rng.SetD6Source(src)
+	before := deck.Remaining()
+	r := OracleHow(rng)
+	rolls := src.TakeRolls()
+	if len(rolls) != 1 || rolls[0].Value != r.Roll {
+		t.Fatalf("oracle roll %d not taken from card %+v", r.Roll, rolls)
+	}
+	if deck.Remaining() >= before && !rolls[0].Joker {
+		t.Error("card d6 should consume deck cards")
+	}
+}
+
+func TestCardD6JokerCallsRandomEvent(t *testing.T) {
+	rng := NewSeededRandomizer(82, 0)
+	src := NewCardD6(RestoreDeck(rng, []Card{{Rank: RankJoker}}))
+	rng.SetD6Source(src)
+	rng.RollD6()
+	rolls := src.TakeRolls()
+	if len(rolls) != 1 || !rolls[0].Joker || rolls[0].Event == nil {
+		t.Fatalf("rolls = %+v, want a Joker with its Random Event", rolls)
+	}
+}
@@ -78,3 +78,29 @@ func TestRankString(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCard(t *testing.T) {
+	tests := map[string]Card{
+		"Q♠":    {Rank: RankQueen, Suit: Spades},
+		"10h":   {Rank: RankTen, Suit: Hearts},
+		"TD":    {Rank: RankTen, Suit: Diamonds},
+		"2c":    {Rank: RankTwo, Suit: Clubs},
+		"a ♦":   {Rank: RankAce, Suit: Diamonds},
+		"Joker": {Rank: RankJoker},
+	}
+	for input, want := range tests {
+		got, err := ParseCard(input)
+		if err != nil {
+			t.Errorf("ParseCard(%q) unexpected error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseCard(%q) = %s, want %s", input, got, want)
+		}
+	}
+	for _, input := range []string{"", "1s", "11h", "Qx", "♠"} {
+		if _, err := ParseCard(input); err == nil {
+			t.Errorf("ParseCard(%q) should error", input)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// What a failed check does with its Failure Move.
+const (
+	CheckFailureRoll  = "roll"  // roll it into the check's entry
+	CheckFailureOffer = "offer" // leave it to the player
+)
+
+// CheckBand is one labeled outcome of a check: totals from Min up to the
+// next band's Min. The lowest band also takes every total below it.
+// Failure marks the bands that call for a Failure Move.
+type CheckBand struct {
+	Min     int    `json:"min"`
+	Label   string `json:"label"`
+	Failure bool   `json:"failure,omitempty"`
+}
+
+// DefaultCheckBands are the PbtA bands: 6- misses, 7-9 is a weak hit
+// (success at a cost) and 10+ a strong hit.
+func DefaultCheckBands() []CheckBand {
+	return []CheckBand{
+		{Label: "Miss", Failure: true},
+		{Min: 7, Label: "Weak Hit"},
+		{Min: 10, Label: "Strong Hit"},
+	}
+}
+
+// TargetBands are the bands for a check against a target: Success at n or
+// more, Failure below.
+func TargetBands(n int) []CheckBand {
+	return []CheckBand{{Label: "Failure", Failure: true}, {Min: n, Label: "Success"}}
+}
+
+// bandFor returns the band a total falls in. bands must be in order of Min.
+func bandFor(bands []CheckBand, total int) CheckBand {
+	band := bands[0]
+	for _, b := range bands[1:] {
+		if total >= b.Min {
+			band = b
+		}
+	}
+	return band
+}
+
+// CheckResult is a dice roll read against a target or the outcome bands.
+// A failed check holds the Failure Move rolled for it, unless the config
+// only offers one. Challenge holds the dice the total had to beat, for a
+// system that rolls them.
+type CheckResult struct {
+	Roll        DiceRollResult
+	Target      int
+	HasTarget   bool
+	Challenge   *DiceRollResult
+	Outcome     CheckBand
+	FailureMove *FailureMoveResult
+	Trace       Trace
+}
+
+// Check rolls expr and reads the total against target, if hasTarget is
+// set, or else the configured bands. A system with challenge dice rolls
+// them too, and the bands read how many the total beats. On a failure it
+// rolls a Failure Move as part of the same result when the config says to.
+func Check(rng *Randomizer, expr DiceExpression, target int, hasTarget bool, cfg *SessionConfig) CheckResult {
+	rng.beginTrace("Check")
+	bands := cfg.Bands()
+	if hasTarget {
+		bands = TargetBands(target)
+	}
+	res := CheckResult{Roll: RollDice(rng, expr), Target: target, HasTarget: hasTarget}
+	score := res.Roll.Total
+	if p, ok := cfg.Preset(); ok && p.Challenge != "" && !hasTarget {
+		dice, _ := ParseDice(p.Challenge)
+		challenge := RollDice(rng, dice)
+		res.Challenge = &challenge
+		score = challenge.beatenBy(res.Roll.Total)
+	}
+	res.Outcome = bandFor(bands, score)
+	if res.Outcome.Failure && cfg.RollsFailureMove() {
+		move := FailureMove(rng)
+		res.FailureMove = &move
+	}
+	res.Trace = rng.endTrace()
+	return res
+}
+
+// beatenBy counts the dice that total is higher than.
+func (r DiceRollResult) beatenBy(total int) int {
+	n := 0
+	for _, t := range r.Terms {
+		for _, v := range t.Rolls {
+			if total > v {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// SplitTarget splits "EXPR vs N" into the expression and its target; EXPR
+// may be empty. Without a "vs", hasTarget is false and expr is the whole
+// text.
+func SplitTarget(text string) (expr string, target int, hasTarget bool, err error) {
+	// Padded, so that a leading "vs N" is found too; "vs" starts at i.
+	i := strings.Index(" "+strings.ToLower(text), " vs ")
+	if i < 0 {
+		return text, 0, false, nil
+	}
+	after := strings.TrimSpace(text[i+len("vs "):])
+	n, err := strconv.Atoi(after)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("vs needs a number, not %q", after)
+	}
+	return strings.TrimSpace(text[:i]), n, true, nil
+}
+
+// defaultCheck is what /check rolls with no system chosen: the PbtA roll
+// the default bands read.
+const defaultCheck = "2d6"
+
+// check rolls /check [EXPR] [vs N]. The expression may be left out, or be
+// just a modifier such as +2, to roll the system's check with that bonus.
+func check(s *Session, args []string) (any, error) {
+	text, target, hasTarget, err := SplitTarget(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+	p, ok := s.Config.Preset()
+	if !ok {
+		p = Preset{Check: defaultCheck}
+	}
+	if text == "" {
+		text = p.Check
+	} else if n, ok := parseBonus(text); ok {
+		if text, err = p.WithBonus(n); err != nil {
+			return nil, err
+		}
+	}
+	expr, err := ParseDiceWith(text, s.Stats())
+	if err != nil {
+		return nil, err
+	}
+	return Check(s.Rng, expr, target, hasTarget, s.Config), nil
+}
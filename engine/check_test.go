@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCheckAgainstTarget(t *testing.T) {
+	rng := NewSeededRandomizer(17, 0)
+	pass, _ := ParseDice("2d6+20")
+	r := Check(rng, pass, 10, true, nil)
+	if r.Outcome.Label != "Success" || r.FailureMove != nil {
+		t.Errorf("2d6+20 vs 10 = %+v", r)
+	}
+	fail, _ := ParseDice("2d6")
+	r = Check(rng, fail, 13, true, nil)
+	if r.Outcome.Label != "Failure" || r.FailureMove == nil || r.FailureMove.Result == "" {
+		t.Errorf("2d6 vs 13 should fail with a Failure Move: %+v", r)
+	}
+	if len(r.Trace) != 5 || r.Trace[0].Label != "Dice" || r.Trace[3].Label != "Failure Move" {
+		t.Errorf("trace = %s, want the dice and the Failure Move", r.Trace)
+	}
+}
+
+func TestCheckBands(t *testing.T) {
+	rng := NewSeededRandomizer(7, 0)
+	expr, _ := ParseDice("2d6")
+	for range 200 {
+		r := Check(rng, expr, 0, false, nil)
+		want := "Strong Hit"
+		switch {
+		case r.Roll.Total <= 6:
+			want = "Miss"
+		case r.Roll.Total <= 9:
+			want = "Weak Hit"
+		}
+		if r.Outcome.Label != want {
+			t.Fatalf("%d: %q, want %q", r.Roll.Total, r.Outcome.Label, want)
+		}
+		if (want == "Miss") != (r.FailureMove != nil) {
+			t.Fatalf("%d: failure move = %v", r.Roll.Total, r.FailureMove)
+		}
+	}
+}
+
+func TestCheckOffersFailureMove(t *testing.T) {
+	cfg := &SessionConfig{
+		CheckBands:       []CheckBand{{Label: "Botch", Failure: true}, {Min: 3, Label: "Made it"}},
+		CheckFailureMove: CheckFailureOffer,
+	}
+	expr, _ := ParseDice("1d2")
+	r := Check(NewSeededRandomizer(1, 0), expr, 0, false, cfg)
+	if r.Outcome.Label != "Botch" || r.FailureMove != nil {
+		t.Errorf("1d2 = %+v, want a Botch with no Failure Move", r)
+	}
+}
+
+func TestPresetBonus(t *testing.T) {
+	tests := []struct {
+		system string
+		bonus  int
+		want   string
+		err    bool
+	}{
+		{"pbta", 2, "2d6+2", false},
+		{"pbta", -1, "2d6-1", false},
+		{"year-zero", 1, "4d6>=6", false},
+		{"year-zero", -2, "1d6>=6", false},
+		{"year-zero", -3, "", true},
+		{"fitd", 1, "3d6kh1", false},
+		{"fitd", -2, "2d6kl1", false},
+	}
+	for _, tt := range tests {
+		p, _ := FindPreset(tt.system)
+		got, err := p.WithBonus(tt.bonus)
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("%s %+d = %q, %v, want %q", tt.system, tt.bonus, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckBonusAddsDice(t *testing.T) {
+	rng := NewSeededRandomizer(3, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Config: &SessionConfig{System: "year-zero"}}
+	res, err := check(s, []string{"+1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := res.(CheckResult); r.Roll.Expression.Raw != "4d6>=6" {
+		t.Errorf("/check +1 rolled %q, want 4d6>=6", r.Roll.Expression.Raw)
+	}
+}
+
+func TestSplitTarget(t *testing.T) {
+	expr, n, ok, err := SplitTarget("2d6+2 VS 10")
+	if err != nil || expr != "2d6+2" || n != 10 || !ok {
+		t.Errorf("got %q %d %v %v", expr, n, ok, err)
+	}
+	if expr, _, ok, _ := SplitTarget("4dF"); expr != "4dF" || ok {
+		t.Errorf("no target: %q %v", expr, ok)
+	}
+	if _, _, _, err := SplitTarget("2d6 vs ten"); err == nil {
+		t.Error("a non-numeric target should be rejected")
+	}
+}
+
+func TestLoadSessionConfigCheckBands(t *testing.T) {
+	dir := t.TempDir()
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+
+	os.WriteFile(".opserc", []byte(`{"check_bands": [{"label": "Fail", "failure": true}, {"min": 8, "label": "Pass"}], "check_failure_move": "offer"}`), 0644)
+	cfg, err := LoadSessionConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := cfg.Bands(); len(b) != 2 || b[1].Min != 8 || cfg.RollsFailureMove() {
+		t.Errorf("bands = %+v, rolls failure move = %v", b, cfg.RollsFailureMove())
+	}
+	os.WriteFile(".opserc", []byte(`{"check_bands": [{"min": 10, "label": "High"}, {"min": 7, "label": "Low"}]}`), 0644)
+	if _, err := LoadSessionConfig(); err == nil {
+		t.Error("bands out of order should be rejected")
+	}
+	os.WriteFile(".opserc", []byte(`{"check_failure_move": "sometimes"}`), 0644)
+	if _, err := LoadSessionConfig(); err == nil {
+		t.Error("an unknown check_failure_move should be rejected")
+	}
+}
@@ -4,6 +4,7 @@ func FlipCoins(rng *Randomizer, count int) CoinFlipResult {
 	if count < 1 {
 		count = 1
 	}
+	rng.beginTrace("Coin Flip")
 	flips := make([]bool, count)
 	heads, tails := 0, 0
 	for i := range flips {
@@ -14,5 +15,5 @@ func FlipCoins(rng *Randomizer, count int) CoinFlipResult {
 			tails++
 		}
 	}
-	return CoinFlipResult{Flips: flips, Heads: heads, Tails: tails}
+	return CoinFlipResult{Flips: flips, Heads: heads, Tails: tails, Trace: rng.endTrace()}
 }
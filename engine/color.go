@@ -17,5 +17,6 @@ var colors = []string{
 }
 
 func RandomColor(rng *Randomizer) ColorResult {
-	return ColorResult{Color: colors[rng.Intn(len(colors))]}
+	rng.beginTrace("Color")
+	return ColorResult{Color: colors[rng.RollDN(len(colors))-1], Trace: rng.endTrace()}
 }
@@ -2,19 +2,157 @@ package engine
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const configFileName = ".opserc"
 
+// Randomness modes for the OPSE tables. See the optional rules in
+// opse_rules.txt.
+const (
+	RandomnessStandard = "standard" // roll dice and draw cards as normal
+	RandomnessCards    = "cards"    // use only cards: d6 = rank/2, Aces discarded
+	RandomnessDice     = "dice"     // use only dice: d12 rank, d4 suit, coin for Q/K
+	RandomnessPhysical = "physical" // the player rolls real dice and draws real cards
+)
+
 type SessionConfig struct {
-	PortraitsEnabled bool `json:"portraits_enabled"`
+	PortraitsEnabled bool   `json:"portraits_enabled"`
+	Randomness       string `json:"randomness"`
+	TraceInJournal   bool   `json:"trace_in_journal"` // append each result's trace to its entry
+
+	// Likelihoods replaces the yes/no oracle's ladder, most likely first.
+	Likelihoods []Likelihood `json:"likelihoods,omitempty"`
+
+	// TableOverrides replaces the rows of built-in tables with house rules,
+	// keyed by table, e.g. "pacing-moves".
+	TableOverrides map[string][]string `json:"table_overrides,omitempty"`
+
+	// RulesFile is an OPSE rules text, such as opse_rules.txt, to read the
+	// built-in tables from in place of the compiled ones.
+	RulesFile string `json:"rules_file,omitempty"`
+
+	// System is the game system preset, e.g. "pbta", used unless the
+	// journal pins its own.
+	System string `json:"system,omitempty"`
+
+	// CheckBands replaces the outcome bands of /check without a target,
+	// lowest first, when no system is chosen.
+	CheckBands []CheckBand `json:"check_bands,omitempty"`
+
+	// CheckFailureMove is what a failed check does with its Failure Move:
+	// "roll" (the default) or "offer".
+	CheckFailureMove string `json:"check_failure_move,omitempty"`
+
+	// FactionClock is the number of segments on a new faction's clock,
+	// when /faction add doesn't give one. Zero means DefaultFactionClock.
+	FactionClock int `json:"faction_clock,omitempty"`
+}
+
+// Ladder returns the configured likelihood ladder, or the default one.
+func (c *SessionConfig) Ladder() []Likelihood {
+	if c == nil || len(c.Likelihoods) == 0 {
+		return DefaultLikelihoods()
+	}
+	return c.Likelihoods
+}
+
+// Preset returns the chosen game system, if any.
+func (c *SessionConfig) Preset() (Preset, bool) {
+	if c == nil || c.System == "" {
+		return Preset{}, false
+	}
+	p, err := FindPreset(c.System)
+	return p, err == nil
+}
+
+// Bands returns the chosen system's check bands, the configured ones, or
+// the default PbtA ones.
+func (c *SessionConfig) Bands() []CheckBand {
+	if p, ok := c.Preset(); ok {
+		return p.Bands
+	}
+	if c == nil || len(c.CheckBands) == 0 {
+		return DefaultCheckBands()
+	}
+	return c.CheckBands
+}
+
+// RollsFailureMove reports whether a failed check rolls its Failure Move.
+func (c *SessionConfig) RollsFailureMove() bool {
+	return c == nil || c.CheckFailureMove != CheckFailureOffer
+}
+
+// validate rejects a ladder the oracle commands can't be built from, check
+// bands out of order, an unknown system and a negative faction clock. Each
+// field it rejects is put back to its default, so the rest still apply;
+// the errors are joined.
+func (c *SessionConfig) validate() error {
+	var errs []error
+	if err := validateLikelihoods(c.Likelihoods); err != nil {
+		errs = append(errs, err)
+		c.Likelihoods = nil
+	}
+	if err := validateCheckBands(c.CheckBands); err != nil {
+		errs = append(errs, err)
+		c.CheckBands = nil
+	}
+	if c.System != "" {
+		if _, err := FindPreset(c.System); err != nil {
+			errs = append(errs, fmt.Errorf("system: %w", err))
+			c.System = ""
+		}
+	}
+	switch c.CheckFailureMove {
+	case "", CheckFailureRoll, CheckFailureOffer:
+	default:
+		errs = append(errs, fmt.Errorf("check_failure_move: %q is not roll or offer", c.CheckFailureMove))
+		c.CheckFailureMove = ""
+	}
+	if c.FactionClock < 0 {
+		errs = append(errs, fmt.Errorf("faction_clock: %d is not a number of segments", c.FactionClock))
+		c.FactionClock = 0
+	}
+	return errors.Join(errs...)
+}
+
+func validateLikelihoods(likelihoods []Likelihood) error {
+	seen := map[string]bool{}
+	for _, l := range likelihoods {
+		if strings.TrimSpace(l.Name) == "" {
+			return fmt.Errorf("likelihoods: a likelihood has no name")
+		}
+		if strings.EqualFold(l.Name, "how") {
+			return fmt.Errorf("likelihoods: %q is reserved for the How oracle", l.Name)
+		}
+		if seen[l.Slug()] {
+			return fmt.Errorf("likelihoods: %q appears twice", l.Name)
+		}
+		seen[l.Slug()] = true
+	}
+	return nil
+}
+
+func validateCheckBands(bands []CheckBand) error {
+	for i, b := range bands {
+		if strings.TrimSpace(b.Label) == "" {
+			return fmt.Errorf("check_bands: a band has no label")
+		}
+		if i > 0 && b.Min <= bands[i-1].Min {
+			return fmt.Errorf("check_bands: %q must start above %q", b.Label, bands[i-1].Label)
+		}
+	}
+	return nil
 }
 
 func DefaultSessionConfig() *SessionConfig {
 	return &SessionConfig{
 		PortraitsEnabled: true,
+		Randomness:       RandomnessStandard,
 	}
 }
 
@@ -25,7 +163,9 @@ func globalConfigPath() string {
 }
 
 // LoadSessionConfig tries .opserc in CWD first, then ~/.config/opse/.opserc.
-// Returns defaults if neither exists.
+// Returns defaults if neither exists. A file that can't be read or parsed
+// gives the defaults and the error; one with invalid fields gives the
+// fields that validated, defaults for the rest, and what was wrong.
 func LoadSessionConfig() (*SessionConfig, error) {
 	for _, path := range []string{configFileName, globalConfigPath()} {
 		data, err := os.ReadFile(path)
@@ -33,10 +173,17 @@ func LoadSessionConfig() (*SessionConfig, error) {
 			continue
 		}
 		if err != nil {
-			return nil, err
+			return DefaultSessionConfig(), err
 		}
 		cfg := DefaultSessionConfig()
-		return cfg, json.Unmarshal(data, cfg)
+		if err := json.Unmarshal(data, cfg); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return DefaultSessionConfig(), err
+			}
+			return cfg, errors.Join(err, cfg.validate())
+		}
+		return cfg, cfg.validate()
 	}
 	return DefaultSessionConfig(), nil
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -12,6 +13,9 @@ func TestDefaultSessionConfig(t *testing.T) {
 	if !cfg.PortraitsEnabled {
 		t.Error("expected portraits_enabled true by default")
 	}
+	if cfg.Randomness != RandomnessStandard {
+		t.Errorf("expected standard randomness by default, got %q", cfg.Randomness)
+	}
 }
 
 func TestLoadSessionConfigMissing(t *testing.T) {
@@ -83,3 +87,63 @@ func TestLoadSessionConfigCWDOverridesGlobal(t *testing.T) {
 		t.Error("expected CWD portraits_enabled false to override global")
 	}
 }
+
+func TestLoadSessionConfigLadder(t *testing.T) {
+	dir := t.TempDir()
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+
+	if got := DefaultSessionConfig().Ladder(); len(got) != 7 {
+		t.Errorf("default ladder has %d rungs, want 7", len(got))
+	}
+	os.WriteFile(".opserc", []byte(`{"likelihoods": [{"name": "Sure", "threshold": 2}, {"name": "Doubtful", "threshold": 5}]}`), 0644)
+	cfg, err := LoadSessionConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := cfg.Ladder(); len(l) != 2 || l[1].Threshold != 5 {
+		t.Errorf("ladder = %+v", l)
+	}
+	os.WriteFile(".opserc", []byte(`{"likelihoods": [{"name": "Sure"}, {"name": "sure"}]}`), 0644)
+	if _, err := LoadSessionConfig(); err == nil {
+		t.Error("duplicate likelihoods should be rejected")
+	}
+}
+
+func TestLoadSessionConfigFactionClock(t *testing.T) {
+	dir := t.TempDir()
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+
+	os.WriteFile(".opserc", []byte(`{"faction_clock": 8}`), 0644)
+	cfg, err := LoadSessionConfig()
+	if err != nil || cfg.FactionClock != 8 {
+		t.Errorf("faction_clock = %d, %v, want 8", cfg.FactionClock, err)
+	}
+	os.WriteFile(".opserc", []byte(`{"faction_clock": -1}`), 0644)
+	if _, err := LoadSessionConfig(); err == nil {
+		t.Error("a negative faction_clock should be rejected")
+	}
+}
+
+func TestLoadSessionConfigKeepsValidFields(t *testing.T) {
+	dir := t.TempDir()
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+
+	os.WriteFile(".opserc", []byte(`{"system": "pbta", "faction_clock": -1, "check_failure_move": "sometimes", "trace_in_journal": true}`), 0644)
+	cfg, err := LoadSessionConfig()
+	if err == nil || !strings.Contains(err.Error(), "faction_clock") || !strings.Contains(err.Error(), "check_failure_move") {
+		t.Errorf("err = %v, want both bad fields named", err)
+	}
+	if cfg.System != "pbta" || !cfg.TraceInJournal || cfg.FactionClock != 0 || cfg.CheckFailureMove != "" {
+		t.Errorf("cfg = %+v, want the valid fields kept and the bad ones reset", cfg)
+	}
+	os.WriteFile(".opserc", []byte(`{"faction_clock": "six", "trace_in_journal": true}`), 0644)
+	if cfg, err := LoadSessionConfig(); err == nil || !cfg.TraceInJournal {
+		t.Errorf("cfg = %+v, %v, want the mistyped field reported and the rest kept", cfg, err)
+	}
+}
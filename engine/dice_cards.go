@@ -0,0 +1,46 @@
+package engine
+
+// CardDice records the rolls that made a card under the "use only dice"
+// rule: a d12 for the rank, a coin flip on a 12 to pick Q or K, and a d4
+// for the suit.
+type CardDice struct {
+	RankRoll int
+	Coin     string // "Heads" (Q) or "Tails" (K); empty unless RankRoll is 12
+	SuitRoll int
+}
+
+// d12 results 1-11; 12 is settled by the coin flip.
+var diceRanks = [12]Rank{
+	0, RankAce, RankTwo, RankThree, RankFour, RankFive, RankSix,
+	RankSeven, RankEight, RankNine, RankTen, RankJack,
+}
+
+// d4 results 1-4, in Suit order.
+var diceSuits = [5]Suit{0, Clubs, Diamonds, Spades, Hearts}
+
+// DiceCards is a CardSource that rolls cards instead of drawing them. It
+// never produces a Joker.
+type DiceCards struct {
+	rng *Randomizer
+}
+
+func NewDiceCards(rng *Randomizer) *DiceCards {
+	return &DiceCards{rng: rng}
+}
+
+func (c *DiceCards) DrawCard() DrawResult {
+	dice := CardDice{RankRoll: c.rng.RollD12(), SuitRoll: c.rng.RollD4()}
+	var rank Rank
+	if dice.RankRoll == 12 {
+		rank = RankKing
+		dice.Coin = "Tails"
+		if c.rng.CoinFlip() {
+			rank = RankQueen
+			dice.Coin = "Heads"
+		}
+	} else {
+		rank = diceRanks[dice.RankRoll]
+	}
+	card := Card{Rank: rank, Suit: diceSuits[dice.SuitRoll]}
+	return DrawResult{Card: card, Dice: &dice}
+}
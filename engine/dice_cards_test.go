@@ -0,0 +1,52 @@
+package engine
+
+import "testing"
+
+func TestDiceCardsMatchRolls(t *testing.T) {
+	rng := NewSeededRandomizer(90, 0)
+	deck := NewDeck(rng)
+	deck.SetSource(NewDiceCards(rng))
+	before := deck.Remaining()
+	seen := make(map[Rank]bool)
+	for range 2000 {
+		r := deck.Draw(nil)
+		if r.Dice == nil {
+			t.Fatal("dice-only draw should record its dice")
+		}
+		if r.Card.IsJoker() || r.JokerDrawn {
+			t.Fatal("dice-only draw produced a Joker")
+		}
+		if r.Card.Suit != diceSuits[r.Dice.SuitRoll] {
+			t.Errorf("d4 %d gave suit %v", r.Dice.SuitRoll, r.Card.Suit)
+		}
+		switch {
+		case r.Dice.RankRoll == 12 && r.Dice.Coin == "Heads":
+			if r.Card.Rank != RankQueen {
+				t.Errorf("12 + Heads gave %s", r.Card)
+			}
+		case r.Dice.RankRoll == 12:
+			if r.Card.Rank != RankKing || r.Dice.Coin != "Tails" {
+				t.Errorf("12 + %q gave %s", r.Dice.Coin, r.Card)
+			}
+		case r.Dice.Coin != "":
+			t.Errorf("coin flipped on a %d", r.Dice.RankRoll)
+		}
+		seen[r.Card.Rank] = true
+	}
+	if len(seen) != 13 {
+		t.Errorf("saw %d ranks, want 13", len(seen))
+	}
+	if deck.Remaining() != before {
+		t.Error("dice-only draws should not consume the deck")
+	}
+}
+
+func TestDiceCardsDriveFocus(t *testing.T) {
+	rng := NewSeededRandomizer(91, 0)
+	deck := NewDeck(rng)
+	deck.SetSource(NewDiceCards(rng))
+	r := ActionFocus(deck)
+	if r.Draw.Dice == nil || r.Entry != actionFocusTable[r.Draw.Card.Rank] {
+		t.Errorf("focus draw not rolled: %+v", r)
+	}
+}
@@ -0,0 +1,246 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxDiceRerolls stops a die exploding or being rerolled forever.
+const maxDiceRerolls = 100
+
+// DiceCompare is a compare point such as >=7: it matches rolls Op N. The
+// zero value is unset.
+type DiceCompare struct {
+	Op string // "=", ">", ">=", "<" or "<="
+	N  int
+}
+
+// Match reports whether v meets the compare point.
+func (c DiceCompare) Match(v int) bool {
+	switch c.Op {
+	case "=":
+		return v == c.N
+	case ">":
+		return v > c.N
+	case ">=":
+		return v >= c.N
+	case "<":
+		return v < c.N
+	case "<=":
+		return v <= c.N
+	}
+	return false
+}
+
+func (c DiceCompare) String() string {
+	switch c.Op {
+	case "":
+		return ""
+	case "=":
+		return strconv.Itoa(c.N)
+	}
+	return c.Op + strconv.Itoa(c.N)
+}
+
+// IsPool reports whether the term counts successes rather than summing.
+func (t DiceTerm) IsPool() bool { return t.Success.Op != "" }
+
+// faces is the lowest and highest a single die of the term can roll.
+func (t DiceTerm) faces() (int, int) {
+	if t.Fudge {
+		return -1, 1
+	}
+	return 1, t.Sides
+}
+
+// matchesEvery reports whether c matches every face of the term's dice.
+func (t DiceTerm) matchesEvery(c DiceCompare) bool {
+	lo, hi := t.faces()
+	for v := lo; v <= hi; v++ {
+		if !c.Match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// modifiers parses the modifiers after a term's sides, in any order:
+// explode (!, !! or !p, then an optional compare point), reroll (r once or
+// rr until, then a compare point), keep or drop, a success target such as
+// >=7, and f with a compare point for failures.
+func (p *diceParser) modifiers(t *DiceTerm) error {
+	for {
+		var err error
+		rest := p.s[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, "!"):
+			if t.Explode != "" {
+				return fmt.Errorf("explodes twice")
+			}
+			t.Explode = "!"
+			if strings.HasPrefix(rest, "!!") || strings.HasPrefix(rest, "!p") {
+				t.Explode = rest[:2]
+			}
+			p.pos += len(t.Explode)
+			t.ExplodeOn, err = p.compare(false)
+		case strings.HasPrefix(rest, "r"):
+			if t.Reroll != "" {
+				return fmt.Errorf("rerolls twice")
+			}
+			t.Reroll = "r"
+			if strings.HasPrefix(rest, "rr") {
+				t.Reroll = "rr"
+			}
+			p.pos += len(t.Reroll)
+			if t.RerollOn, err = p.compare(true); err == nil && t.RerollOn.Op == "" {
+				err = fmt.Errorf("%s needs a target such as %s1 or %s<3", t.Reroll, t.Reroll, t.Reroll)
+			}
+		case strings.HasPrefix(rest, "k") || strings.HasPrefix(rest, "dh") || strings.HasPrefix(rest, "dl"):
+			if t.KeepMode != "" {
+				return fmt.Errorf("keeps or drops twice")
+			}
+			err = p.keep(t)
+		case strings.HasPrefix(rest, ">") || strings.HasPrefix(rest, "<") || strings.HasPrefix(rest, "="):
+			if t.Success.Op != "" {
+				return fmt.Errorf("has two success targets")
+			}
+			t.Success, err = p.compare(true)
+		case strings.HasPrefix(rest, "f"):
+			if t.Failure.Op != "" {
+				return fmt.Errorf("has two failure targets")
+			}
+			p.pos++
+			if t.Failure, err = p.compare(true); err == nil && t.Failure.Op == "" {
+				err = fmt.Errorf("f needs a target such as f1 or f<=2")
+			}
+		default:
+			return t.validate()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// keep parses khN, klN, dhN, dlN or a bare kN, meaning kh.
+func (p *diceParser) keep(t *DiceTerm) error {
+	for _, mode := range []string{"kh", "kl", "dh", "dl", "k"} {
+		if !strings.HasPrefix(p.s[p.pos:], mode) {
+			continue
+		}
+		p.pos += len(mode)
+		if mode == "k" {
+			mode = "kh"
+		}
+		t.KeepMode = mode
+		t.KeepN = 1
+		if isDigit(p.peek()) {
+			t.KeepN, _ = p.number()
+			if t.KeepN < 1 {
+				return fmt.Errorf("%s needs at least 1 die", mode)
+			}
+		}
+		if mode[0] == 'k' {
+			t.KeepN = min(t.KeepN, t.Count)
+		} else if t.KeepN >= t.Count {
+			return fmt.Errorf("can't drop %d of %d dice", t.KeepN, t.Count)
+		}
+		return nil
+	}
+	return fmt.Errorf("unexpected %q", p.s[p.pos:])
+}
+
+// compare parses a compare point such as >=7 or, if bare is set, a plain
+// number meaning =N. It returns the zero DiceCompare if there is none.
+func (p *diceParser) compare(bare bool) (DiceCompare, error) {
+	var c DiceCompare
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(p.s[p.pos:], op) {
+			c.Op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if c.Op == "" {
+		if !bare || !isDigit(p.peek()) {
+			return c, nil
+		}
+		c.Op = "="
+	}
+	sign := 1
+	if p.peek() == '-' {
+		sign = -1
+		p.pos++
+	}
+	if !isDigit(p.peek()) {
+		return c, fmt.Errorf("%s needs a number", c.Op)
+	}
+	n, ok := p.number()
+	if !ok {
+		return c, fmt.Errorf("number too large")
+	}
+	c.N = sign * n
+	return c, nil
+}
+
+func (t DiceTerm) validate() error {
+	switch {
+	case t.Explode != "" && t.Fudge:
+		return fmt.Errorf("Fudge dice can't explode")
+	case t.Explode != "" && t.ExplodeOn.Op != "" && t.matchesEvery(t.ExplodeOn):
+		return fmt.Errorf("every roll would explode")
+	case t.Reroll != "" && t.matchesEvery(t.RerollOn):
+		return fmt.Errorf("every roll would be rerolled")
+	case t.Failure.Op != "" && !t.IsPool():
+		return fmt.Errorf("failures need a success target such as >=7")
+	}
+	return nil
+}
+
+// DieMark is how a die counts toward its term.
+type DieMark int
+
+const (
+	DieKept    DieMark = iota // counts toward the total
+	DieDropped                // dropped by keep/drop, or rerolled
+	DieSuccess                // a pool die that met the success target
+	DieFailure                // a pool die that met the failure target
+	DieMiss                   // a pool die that met neither
+)
+
+func (r DiceTermResult) mark(i int) DieMark {
+	switch {
+	case !r.Kept[i]:
+		return DieDropped
+	case r.Success == nil:
+		return DieKept
+	case r.Success[i]:
+		return DieSuccess
+	case r.Failure[i]:
+		return DieFailure
+	}
+	return DieMiss
+}
+
+// Outcome states the result: the total, or for a pool the successes and
+// any failures counted, e.g. "3 successes, 1 failure".
+func (r DiceRollResult) Outcome() string {
+	if !r.Pool {
+		return strconv.Itoa(r.Total)
+	}
+	s := plural(r.Total, "success", "successes")
+	for _, t := range r.Expression.Terms() {
+		if t.Failure.Op != "" {
+			return s + ", " + plural(r.Failures, "failure", "failures")
+		}
+	}
+	return s
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return strconv.Itoa(n) + " " + many
+}
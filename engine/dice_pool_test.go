@@ -0,0 +1,136 @@
+package engine
+
+import "testing"
+
+func TestParseDicePool(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"6d10>=7", "6d10>=7"},
+		{"6d10>=7f1", "6d10>=7f1"},
+		{"10d10>=8!", "10d10!>=8"},
+		{"5d10!>=9>=8", "5d10!>=9>=8"},
+		{"4d6r1", "4d6r1"},
+		{"4d6rr<2", "4d6rr<2"},
+		{"3d6!!", "3d6!!"},
+		{"2d6!p", "2d6!p"},
+		{"4dF>=1", "4dF>=1"},
+	}
+	for _, tt := range tests {
+		expr, err := ParseDice(tt.input)
+		if err != nil {
+			t.Errorf("ParseDice(%q): %v", tt.input, err)
+			continue
+		}
+		if got := expr.String(); got != tt.want {
+			t.Errorf("%q: parsed as %q, want %q", tt.input, got, tt.want)
+		}
+	}
+	for _, input := range []string{"6d10f1", "d6!>=1", "d6rr<=6", "d6r", "d6>=", "d6!!!", "d6>=4>=5"} {
+		if _, err := ParseDice(input); err == nil {
+			t.Errorf("ParseDice(%q) should error", input)
+		}
+	}
+}
+
+func TestRollDicePool(t *testing.T) {
+	rng := NewSeededRandomizer(210, 0)
+	expr, _ := ParseDice("8d10>=7f1")
+	for range 50 {
+		r := RollDice(rng, expr)
+		tr := r.Terms[0]
+		successes, failures := 0, 0
+		for _, v := range tr.Rolls {
+			if v >= 7 {
+				successes++
+			}
+			if v == 1 {
+				failures++
+			}
+		}
+		if !r.Pool || r.Total != successes || r.Failures != failures || tr.Successes != successes {
+			t.Fatalf("rolls %v: total %d, failures %d", tr.Rolls, r.Total, r.Failures)
+		}
+	}
+}
+
+func TestRollDiceReroll(t *testing.T) {
+	rng := NewSeededRandomizer(211, 0)
+	until, _ := ParseDice("6d6rr<3")
+	once, _ := ParseDice("6d6r1")
+	for range 50 {
+		tr := RollDice(rng, until).Terms[0]
+		live := 0
+		for i, v := range tr.Rolls {
+			if tr.Kept[i] {
+				live++
+				if v < 3 {
+					t.Fatalf("rolls %v kept %v: a die under 3 was kept", tr.Rolls, tr.Kept)
+				}
+			} else if v >= 3 {
+				t.Fatalf("rolls %v kept %v: a die of 3+ was rerolled", tr.Rolls, tr.Kept)
+			}
+		}
+		if live != 6 {
+			t.Fatalf("rolls %v kept %v: want 6 dice", tr.Rolls, tr.Kept)
+		}
+
+		tr = RollDice(rng, once).Terms[0]
+		for i := range tr.Rolls {
+			if !tr.Kept[i] && (tr.Rolls[i] != 1 || !tr.Kept[i+1]) {
+				t.Fatalf("rolls %v kept %v: r1 should reroll a 1 once", tr.Rolls, tr.Kept)
+			}
+		}
+	}
+}
+
+func TestRollDiceExplodeModes(t *testing.T) {
+	rng := NewSeededRandomizer(212, 0)
+	threshold, _ := ParseDice("20d10!>=9")
+	compound, _ := ParseDice("20d6!!")
+	penetrate, _ := ParseDice("20d6!p")
+	for range 20 {
+		tr := RollDice(rng, threshold).Terms[0]
+		exploded := 0
+		for _, v := range tr.Rolls {
+			if v >= 9 {
+				exploded++
+			}
+		}
+		if len(tr.Rolls) != 20+exploded {
+			t.Fatalf("rolls %v: each 9+ should add a die", tr.Rolls)
+		}
+
+		tr = RollDice(rng, compound).Terms[0]
+		if len(tr.Rolls) != 20 {
+			t.Fatalf("compounding rolls %v: want 20 dice", tr.Rolls)
+		}
+		for _, v := range tr.Rolls {
+			if v%6 == 0 {
+				t.Fatalf("compounding rolls %v: a die can't end on a multiple of 6", tr.Rolls)
+			}
+		}
+
+		tr = RollDice(rng, penetrate).Terms[0]
+		for _, v := range tr.Rolls {
+			if v < 0 || v > 6 {
+				t.Fatalf("penetrating rolls %v: extra dice should be 0-5", tr.Rolls)
+			}
+		}
+	}
+}
+
+func TestDiceOutcome(t *testing.T) {
+	botches, _ := ParseDice("5d10>=7f1")
+	tests := []struct {
+		r    DiceRollResult
+		want string
+	}{
+		{DiceRollResult{Total: 7}, "7"},
+		{DiceRollResult{Pool: true, Total: 1}, "1 success"},
+		{DiceRollResult{Expression: botches, Pool: true, Total: 3, Failures: 1}, "3 successes, 1 failure"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Outcome(); got != tt.want {
+			t.Errorf("Outcome() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -2,107 +2,562 @@ package engine
 
 import (
 	"fmt"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
-var diceRegex = regexp.MustCompile(
-	`(?i)^(\d*)d(\d+)(!)?(?:(kh|kl)(\d+))?([+-]\d+)?$`,
-)
-
+// ParseDice parses a dice expression: dice terms and whole numbers joined
+// by + - * / and grouped with parentheses, e.g. "2d6+1d4+3", "(1d8+2)*2" or
+// "d20+d20". A term is NdS, with N defaulting to 1, S a number of sides, %
+// for d100 or F for Fudge dice, then optionally ! to explode and one of
+// khN, klN, dhN or dlN to keep or drop the highest or lowest N dice (N
+// defaults to 1; a bare k is kh). Division rounds down.
 func ParseDice(input string) (DiceExpression, error) {
-	clean := strings.ReplaceAll(strings.TrimSpace(input), " ", "")
-	m := diceRegex.FindStringSubmatch(clean)
-	if m == nil {
-		return DiceExpression{}, fmt.Errorf("invalid dice expression: %q", input)
+	return ParseDiceWith(input, nil)
+}
+
+// StatResolver looks up a stat on a character sheet for a reference such
+// as @str; character is empty for the current character.
+type StatResolver func(character, stat string) (int, error)
+
+// ParseDiceWith parses a dice expression whose stat references, such as
+// @str, @elara.str or @"Sir Beans".wits, are looked up with stats.
+func ParseDiceWith(input string, stats StatResolver) (DiceExpression, error) {
+	p := &diceParser{s: cleanDice(input), stats: stats}
+	root, err := p.sum()
+	if err == nil && p.pos < len(p.s) {
+		err = fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+	if err != nil {
+		return DiceExpression{}, fmt.Errorf("invalid dice expression %q: %w", input, err)
+	}
+	return DiceExpression{Root: root, Raw: input}, nil
+}
+
+type diceParser struct {
+	s     string
+	pos   int
+	stats StatResolver
+}
+
+// cleanDice lowercases the expression and drops its spaces, except inside
+// the quoted names of stat references.
+func cleanDice(input string) string {
+	var b strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case unicode.IsSpace(r):
+			continue
+		default:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (p *diceParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+// sum parses terms joined by + and -.
+func (p *diceParser) sum() (*DiceNode, error) {
+	left, err := p.product()
+	for err == nil && (p.peek() == '+' || p.peek() == '-') {
+		op := p.peek()
+		p.pos++
+		var right *DiceNode
+		if right, err = p.product(); err == nil {
+			left = &DiceNode{Op: op, Left: left, Right: right}
+		}
+	}
+	return left, err
+}
+
+// product parses factors joined by * and /.
+func (p *diceParser) product() (*DiceNode, error) {
+	left, err := p.factor()
+	for err == nil && (p.peek() == '*' || p.peek() == '/') {
+		op := p.peek()
+		p.pos++
+		var right *DiceNode
+		if right, err = p.factor(); err != nil {
+			break
+		}
+		if lo, hi := right.Span(); op == '/' && lo <= 0 && hi >= 0 {
+			return nil, fmt.Errorf("division by %s can be zero", right)
+		}
+		left = &DiceNode{Op: op, Left: left, Right: right}
+	}
+	return left, err
+}
+
+// factor parses a negation, a parenthesised expression, a dice term, a
+// stat reference or a number.
+func (p *diceParser) factor() (*DiceNode, error) {
+	switch c := p.peek(); {
+	case c == '-':
+		p.pos++
+		n, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return &DiceNode{Op: 'n', Left: n}, nil
+	case c == '(':
+		p.pos++
+		n, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return n, nil
+	case c == 'd' || isDigit(c):
+		n, ok := p.number()
+		if p.peek() != 'd' {
+			if !ok {
+				return nil, fmt.Errorf("number too large")
+			}
+			return &DiceNode{Value: n}, nil
+		}
+		p.pos++
+		if c == 'd' {
+			n = 1
+		}
+		return p.dice(n, ok)
+	case c == '@':
+		return p.ref()
+	case c == 0:
+		return nil, fmt.Errorf("expression ends early")
+	default:
+		return nil, fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+}
+
+// dice parses the rest of a term after its "Nd".
+func (p *diceParser) dice(count int, ok bool) (*DiceNode, error) {
+	if !ok || count < 1 || count > 999 {
+		return nil, fmt.Errorf("dice count must be 1-999")
+	}
+	t := &DiceTerm{Count: count}
+	switch p.peek() {
+	case '%':
+		p.pos++
+		t.Sides = 100
+	case 'f':
+		p.pos++
+		t.Fudge = true
+	default:
+		start := p.pos
+		sides, _ := p.number()
+		if p.pos == start {
+			return nil, fmt.Errorf("die needs a number of sides")
+		}
+		if sides < 2 || sides > 100 {
+			return nil, fmt.Errorf("die sides must be 2-100, got %d", sides)
+		}
+		t.Sides = sides
 	}
+	if err := p.modifiers(t); err != nil {
+		return nil, err
+	}
+	return &DiceNode{Dice: t}, nil
+}
 
-	count := 1
-	if m[1] != "" {
-		count, _ = strconv.Atoi(m[1])
+// ref parses a stat reference: @stat for the current character, or
+// @name.stat or @"Full Name".stat for another.
+func (p *diceParser) ref() (*DiceNode, error) {
+	start := p.pos
+	p.pos++
+	var char, stat string
+	if p.peek() == '"' {
+		end := strings.IndexByte(p.s[p.pos+1:], '"')
+		if end < 0 {
+			return nil, fmt.Errorf("missing closing quote")
+		}
+		char = p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		if p.peek() != '.' {
+			return nil, fmt.Errorf("%s needs a stat, e.g. %s.str", p.s[start:p.pos], p.s[start:p.pos])
+		}
+		p.pos++
+		stat = p.word()
+	} else if stat = p.word(); p.peek() == '.' {
+		p.pos++
+		char, stat = stat, p.word()
+	}
+	ref := p.s[start:p.pos]
+	if stat == "" {
+		return nil, fmt.Errorf("%s needs a stat name", ref)
 	}
-	sides, _ := strconv.Atoi(m[2])
-	explode := m[3] == "!"
-	keepMode := strings.ToLower(m[4])
-	keepN := 0
-	if m[5] != "" {
-		keepN, _ = strconv.Atoi(m[5])
+	if p.stats == nil {
+		return nil, fmt.Errorf("%s: no character sheet to read it from", ref)
 	}
-	modifier := 0
-	if m[6] != "" {
-		modifier, _ = strconv.Atoi(m[6])
+	v, err := p.stats(char, stat)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
 	}
+	return &DiceNode{Value: v, Ref: ref}, nil
+}
 
-	if count < 1 || count > 999 {
-		return DiceExpression{}, fmt.Errorf("dice count must be 1-999, got %d", count)
+// word reads a run of letters, digits and underscores.
+func (p *diceParser) word() string {
+	start := p.pos
+	for c := p.peek(); c == '_' || isDigit(c) || (c >= 'a' && c <= 'z'); c = p.peek() {
+		p.pos++
 	}
-	if sides < 2 || sides > 100 {
-		return DiceExpression{}, fmt.Errorf("die sides must be 2-100, got %d", sides)
+	return p.s[start:p.pos]
+}
+
+// number reads a run of digits, reporting false if it overflows. No
+// digits reads as 0.
+func (p *diceParser) number() (int, bool) {
+	start := p.pos
+	for isDigit(p.peek()) {
+		p.pos++
 	}
-	if keepN > count {
-		keepN = count
+	if p.pos == start {
+		return 0, true
 	}
-	if keepMode != "" && keepN == 0 {
-		keepN = 1
+	n, err := strconv.Atoi(p.s[start:p.pos])
+	return n, err == nil && n <= 1_000_000
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// kept is how many of n dice count toward the term's total.
+func (t DiceTerm) kept(n int) int {
+	switch t.KeepMode {
+	case "kh", "kl":
+		return min(t.KeepN, n)
+	case "dh", "dl":
+		return max(n-t.KeepN, 0)
 	}
+	return n
+}
 
-	return DiceExpression{
-		Count: count, Sides: sides, Explode: explode,
-		KeepMode: keepMode, KeepN: keepN, Modifier: modifier,
-		Raw: input,
-	}, nil
+// Span is the lowest and highest total the term can roll. Exploding dice
+// are counted without their extra rolls.
+func (t DiceTerm) Span() (int, int) {
+	n := t.kept(t.Count)
+	switch {
+	case t.IsPool():
+		return 0, n
+	case t.Fudge:
+		return -n, n
+	}
+	return n, n * t.Sides
 }
 
+func (t DiceTerm) String() string {
+	var b strings.Builder
+	if t.Count > 1 {
+		b.WriteString(strconv.Itoa(t.Count))
+	}
+	switch {
+	case t.Fudge:
+		b.WriteString("dF")
+	default:
+		fmt.Fprintf(&b, "d%d", t.Sides)
+	}
+	if t.Explode != "" {
+		b.WriteString(t.Explode + t.ExplodeOn.String())
+	}
+	if t.Reroll != "" {
+		b.WriteString(t.Reroll + t.RerollOn.String())
+	}
+	if t.KeepMode != "" {
+		fmt.Fprintf(&b, "%s%d", t.KeepMode, t.KeepN)
+	}
+	b.WriteString(t.Success.String())
+	if t.Failure.Op != "" {
+		b.WriteString("f" + t.Failure.String())
+	}
+	return b.String()
+}
+
+// Span is the lowest and highest total the node can roll.
+func (n *DiceNode) Span() (int, int) {
+	switch {
+	case n.Dice != nil:
+		return n.Dice.Span()
+	case n.Op == 0:
+		return n.Value, n.Value
+	case n.Op == 'n':
+		lo, hi := n.Left.Span()
+		return -hi, -lo
+	}
+	llo, lhi := n.Left.Span()
+	rlo, rhi := n.Right.Span()
+	switch n.Op {
+	case '+':
+		return llo + rlo, lhi + rhi
+	case '-':
+		return llo - rhi, lhi - rlo
+	}
+	corners := []int{
+		applyOp(n.Op, llo, rlo), applyOp(n.Op, llo, rhi),
+		applyOp(n.Op, lhi, rlo), applyOp(n.Op, lhi, rhi),
+	}
+	sort.Ints(corners)
+	return corners[0], corners[3]
+}
+
+func applyOp(op byte, a, b int) int {
+	switch op {
+	case '+':
+		return a + b
+	case '-':
+		return a - b
+	case '*':
+		return a * b
+	}
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q-- // round down, not toward zero
+	}
+	return q
+}
+
+// precedence orders operators for printing; leaves bind tightest.
+func (n *DiceNode) precedence() int {
+	switch n.Op {
+	case '+', '-':
+		return 1
+	case '*', '/':
+		return 2
+	case 'n':
+		return 3
+	}
+	return 4
+}
+
+// String prints the node in normal form, e.g. "(d8+@str)*2".
+func (n *DiceNode) String() string {
+	return n.format(func(t DiceTerm, _ int) string { return t.String() },
+		func(n *DiceNode) string { return n.Ref }, "+", "-", "*", "/")
+}
+
+// format prints the node with each dice term given by term, which is
+// passed the term's index in the expression, each stat reference given by
+// ref, and the operators spelled as given.
+func (n *DiceNode) format(term func(DiceTerm, int) string, ref func(*DiceNode) string, ops ...string) string {
+	i := 0
+	var walk func(n *DiceNode) string
+	wrap := func(child *DiceNode, parent int, tight bool) string {
+		s := walk(child)
+		if p := child.precedence(); p < parent || (tight && p == parent) {
+			return "(" + s + ")"
+		}
+		return s
+	}
+	walk = func(n *DiceNode) string {
+		switch {
+		case n.Dice != nil:
+			i++
+			return term(*n.Dice, i-1)
+		case n.Op == 0 && n.Ref != "":
+			return ref(n)
+		case n.Op == 0:
+			return strconv.Itoa(n.Value)
+		case n.Op == 'n':
+			return "-" + wrap(n.Left, n.precedence(), false)
+		}
+		op := ops[strings.IndexByte("+-*/", n.Op)]
+		left := wrap(n.Left, n.precedence(), false)
+		right := wrap(n.Right, n.precedence(), n.Op == '-' || n.Op == '/')
+		return left + op + right
+	}
+	return walk(n)
+}
+
+// Terms lists the expression's dice terms in order.
+func (e DiceExpression) Terms() []DiceTerm {
+	var terms []DiceTerm
+	if e.Root != nil {
+		e.Root.format(func(t DiceTerm, _ int) string {
+			terms = append(terms, t)
+			return ""
+		}, func(*DiceNode) string { return "" }, "", "", "", "")
+	}
+	return terms
+}
+
+// Span is the lowest and highest total the expression can roll.
+func (e DiceExpression) Span() (int, int) { return e.Root.Span() }
+
+func (e DiceExpression) String() string { return e.Root.String() }
+
+// RollDice rolls every term of the expression and works out its total.
 func RollDice(rng *Randomizer, expr DiceExpression) DiceRollResult {
-	rolls := make([]int, 0, expr.Count)
-	for range expr.Count {
-		val := rng.RollDN(expr.Sides)
-		if expr.Explode {
-			total := val
-			depth := 0
-			for val == expr.Sides && depth < 100 {
-				val = rng.RollDN(expr.Sides)
-				total += val
-				depth++
-			}
-			rolls = append(rolls, total)
-		} else {
-			rolls = append(rolls, val)
+	rng.beginTrace("Dice")
+	res := DiceRollResult{Expression: expr}
+	for _, t := range expr.Terms() {
+		tr := rollTerm(rng, t)
+		res.Terms = append(res.Terms, tr)
+		res.Pool = res.Pool || t.IsPool()
+		res.Failures += tr.Failures
+	}
+	i := 0
+	var eval func(n *DiceNode) int
+	eval = func(n *DiceNode) int {
+		switch {
+		case n.Dice != nil:
+			i++
+			return res.Terms[i-1].Total
+		case n.Op == 0:
+			return n.Value
+		case n.Op == 'n':
+			return -eval(n.Left)
 		}
+		l := eval(n.Left)
+		return applyOp(n.Op, l, eval(n.Right))
 	}
+	res.Total = eval(expr.Root)
+	res.Trace = rng.endTrace()
+	return res
+}
 
-	kept := make([]bool, len(rolls))
-	if expr.KeepMode == "" {
-		for i := range kept {
-			kept[i] = true
+func rollTerm(rng *Randomizer, t DiceTerm) DiceTermResult {
+	roll := func() int {
+		if t.Fudge {
+			return rng.RollDF()
 		}
-	} else {
-		type iv struct{ i, v int }
-		indexed := make([]iv, len(rolls))
-		for i, v := range rolls {
-			indexed[i] = iv{i, v}
+		return rng.RollDN(t.Sides)
+	}
+	explodes := func(v int) bool {
+		if t.ExplodeOn.Op == "" {
+			return v == t.Sides
 		}
-		if expr.KeepMode == "kh" {
-			sort.Slice(indexed, func(a, b int) bool { return indexed[a].v > indexed[b].v })
-		} else {
-			sort.Slice(indexed, func(a, b int) bool { return indexed[a].v < indexed[b].v })
+		return t.ExplodeOn.Match(v)
+	}
+	var rolls []int
+	var live []bool
+	add := func(v int, ok bool) {
+		rolls = append(rolls, v)
+		live = append(live, ok)
+	}
+	for range t.Count {
+		v := roll()
+		for n := 0; t.Reroll != "" && t.RerollOn.Match(v) && n < maxDiceRerolls; n++ {
+			add(v, false)
+			v = roll()
+			if t.Reroll == "r" {
+				break
+			}
 		}
-		for i := 0; i < expr.KeepN && i < len(indexed); i++ {
-			kept[indexed[i].i] = true
+		switch t.Explode {
+		case "!!":
+			total := v
+			for n := 0; explodes(v) && n < maxDiceRerolls; n++ {
+				v = roll()
+				total += v
+			}
+			add(total, true)
+		case "!", "!p":
+			add(v, true)
+			for n := 0; explodes(v) && n < maxDiceRerolls; n++ {
+				v = roll()
+				if t.Explode == "!p" {
+					add(v-1, true)
+				} else {
+					add(v, true)
+				}
+			}
+		default:
+			add(v, true)
 		}
 	}
 
-	subtotal := 0
+	var indexed []int
+	for i, ok := range live {
+		if ok {
+			indexed = append(indexed, i)
+		}
+	}
+	// Highest first for kh and dl, which keep the top dice.
+	highFirst := t.KeepMode == "kh" || t.KeepMode == "dl"
+	sort.SliceStable(indexed, func(a, b int) bool {
+		if highFirst {
+			return rolls[indexed[a]] > rolls[indexed[b]]
+		}
+		return rolls[indexed[a]] < rolls[indexed[b]]
+	})
+	kept := make([]bool, len(rolls))
+	for _, i := range indexed[:t.kept(len(indexed))] {
+		kept[i] = true
+	}
+
+	res := DiceTermResult{Term: t, Rolls: rolls, Kept: kept}
+	if t.IsPool() {
+		res.Success = make([]bool, len(rolls))
+		res.Failure = make([]bool, len(rolls))
+	}
 	for i, v := range rolls {
-		if kept[i] {
-			subtotal += v
+		switch {
+		case !kept[i]:
+		case t.IsPool():
+			if t.Success.Match(v) {
+				res.Success[i] = true
+				res.Successes++
+			}
+			if t.Failure.Op != "" && t.Failure.Match(v) {
+				res.Failure[i] = true
+				res.Failures++
+			}
+		default:
+			res.Total += v
 		}
 	}
+	if t.IsPool() {
+		res.Total = res.Successes
+	}
+	return res
+}
 
-	return DiceRollResult{
-		Expression: expr, Rolls: rolls, Kept: kept,
-		Subtotal: subtotal, Total: subtotal + expr.Modifier,
+// FudgeFace shows a Fudge die as +, - or 0.
+func FudgeFace(v int) string {
+	switch {
+	case v > 0:
+		return "+"
+	case v < 0:
+		return "-"
 	}
+	return "0"
+}
+
+// Breakdown writes out the expression with each term replaced by its dice,
+// each given by die: "[6] [4] [3] ~~2~~ + 5". When the expression has more
+// than one dice term, each term's dice follow its notation, e.g.
+// "2d6 [3] [4] + 1d4 [2] + 3". Operators print as + - × ÷.
+func (r DiceRollResult) Breakdown(die func(face string, mark DieMark) string) string {
+	labelled := len(r.Terms) > 1
+	return r.Expression.Root.format(func(t DiceTerm, i int) string {
+		tr := r.Terms[i]
+		parts := make([]string, 0, len(tr.Rolls)+1)
+		if labelled {
+			parts = append(parts, t.String())
+		}
+		for j, v := range tr.Rolls {
+			face := strconv.Itoa(v)
+			if t.Fudge {
+				face = FudgeFace(v)
+			}
+			parts = append(parts, die(face, tr.mark(j)))
+		}
+		return strings.Join(parts, " ")
+	}, func(n *DiceNode) string {
+		return fmt.Sprintf("%s (%d)", n.Ref, n.Value)
+	}, " + ", " - ", " × ", " ÷ ")
 }
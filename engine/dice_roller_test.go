@@ -1,23 +1,35 @@
 package engine
 
-import "testing"
+import (
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestParseDice_Valid(t *testing.T) {
 	tests := []struct {
-		input        string
-		count, sides int
-		mod          int
-		keep         string
-		keepN        int
+		input  string
+		want   string // normal form
+		lo, hi int
 	}{
-		{"d20", 1, 20, 0, "", 0},
-		{"2d6", 2, 6, 0, "", 0},
-		{"4d6kh3", 4, 6, 0, "kh", 3},
-		{"2d8+5", 2, 8, 5, "", 0},
-		{"d100-10", 1, 100, -10, "", 0},
-		{"3d8+5", 3, 8, 5, "", 0},
-		{"2d6!", 2, 6, 0, "", 0},
-		{"2d20kl1", 2, 20, 0, "kl", 1},
+		{"d20", "d20", 1, 20},
+		{"2d6", "2d6", 2, 12},
+		{"4d6kh3", "4d6kh3", 3, 18},
+		{"4d6k3", "4d6kh3", 3, 18},
+		{"2d8+5", "2d8+5", 7, 21},
+		{"d100-10", "d100-10", -9, 90},
+		{"2d6!", "2d6!", 2, 12},
+		{"2d20kl1", "2d20kl1", 1, 20},
+		{"4d6dl1", "4d6dl1", 3, 18},
+		{"3d6dh", "3d6dh1", 2, 12},
+		{"d%", "d100", 1, 100},
+		{"4dF", "4dF", -4, 4},
+		{"2d6 + 1d4 + 3", "2d6+d4+3", 6, 19},
+		{"(1d8+2)*2", "(d8+2)*2", 6, 20},
+		{"d20+d20", "d20+d20", 2, 40},
+		{"10-(d6-d4)", "10-(d6-d4)", 5, 13},
+		{"-d6*2", "-d6*2", -12, -2},
+		{"2D6/2", "2d6/2", 1, 6},
 	}
 	for _, tt := range tests {
 		expr, err := ParseDice(tt.input)
@@ -25,26 +37,20 @@ func TestParseDice_Valid(t *testing.T) {
 			t.Errorf("ParseDice(%q) unexpected error: %v", tt.input, err)
 			continue
 		}
-		if expr.Count != tt.count {
-			t.Errorf("%q: count %d, want %d", tt.input, expr.Count, tt.count)
+		if got := expr.String(); got != tt.want {
+			t.Errorf("%q: parsed as %q, want %q", tt.input, got, tt.want)
 		}
-		if expr.Sides != tt.sides {
-			t.Errorf("%q: sides %d, want %d", tt.input, expr.Sides, tt.sides)
-		}
-		if expr.Modifier != tt.mod {
-			t.Errorf("%q: mod %d, want %d", tt.input, expr.Modifier, tt.mod)
-		}
-		if expr.KeepMode != tt.keep {
-			t.Errorf("%q: keep %q, want %q", tt.input, expr.KeepMode, tt.keep)
-		}
-		if expr.KeepN != tt.keepN {
-			t.Errorf("%q: keepN %d, want %d", tt.input, expr.KeepN, tt.keepN)
+		if lo, hi := expr.Span(); lo != tt.lo || hi != tt.hi {
+			t.Errorf("%q: span %d-%d, want %d-%d", tt.input, lo, hi, tt.lo, tt.hi)
 		}
 	}
 }
 
 func TestParseDice_Invalid(t *testing.T) {
-	invalid := []string{"garbage", "0d6", "1d1", "1000d6", "d0", ""}
+	invalid := []string{
+		"garbage", "0d6", "1d1", "1000d6", "d0", "",
+		"2d6+", "(d6", "d6)", "4d6dl4", "3dF!", "d6/(d4-1)", "2d6d6",
+	}
 	for _, input := range invalid {
 		_, err := ParseDice(input)
 		if err == nil {
@@ -57,10 +63,10 @@ func TestRollDice_CorrectCount(t *testing.T) {
 	rng := NewSeededRandomizer(200, 0)
 	expr, _ := ParseDice("5d6")
 	r := RollDice(rng, expr)
-	if len(r.Rolls) != 5 {
-		t.Errorf("expected 5 rolls, got %d", len(r.Rolls))
+	if len(r.Terms) != 1 || len(r.Terms[0].Rolls) != 5 {
+		t.Fatalf("expected 5 rolls, got %+v", r.Terms)
 	}
-	for _, v := range r.Rolls {
+	for _, v := range r.Terms[0].Rolls {
 		if v < 1 || v > 6 {
 			t.Errorf("roll %d out of range", v)
 		}
@@ -70,7 +76,7 @@ func TestRollDice_CorrectCount(t *testing.T) {
 func TestRollDice_KeepHighest(t *testing.T) {
 	rng := NewSeededRandomizer(201, 0)
 	expr, _ := ParseDice("4d6kh3")
-	r := RollDice(rng, expr)
+	r := RollDice(rng, expr).Terms[0]
 	keptCount := 0
 	for _, k := range r.Kept {
 		if k {
@@ -80,15 +86,35 @@ func TestRollDice_KeepHighest(t *testing.T) {
 	if keptCount != 3 {
 		t.Errorf("expected 3 kept, got %d", keptCount)
 	}
-	// Verify subtotal equals sum of kept dice
+	// Verify total equals sum of kept dice
 	sum := 0
 	for i, v := range r.Rolls {
 		if r.Kept[i] {
 			sum += v
 		}
 	}
-	if sum != r.Subtotal {
-		t.Errorf("subtotal %d != sum of kept %d", r.Subtotal, sum)
+	if sum != r.Total {
+		t.Errorf("total %d != sum of kept %d", r.Total, sum)
+	}
+}
+
+func TestRollDice_DropLowest(t *testing.T) {
+	rng := NewSeededRandomizer(204, 0)
+	expr, _ := ParseDice("4d6dl1")
+	for range 50 {
+		r := RollDice(rng, expr).Terms[0]
+		dropped := -1
+		for i, k := range r.Kept {
+			if !k {
+				if dropped >= 0 {
+					t.Fatalf("rolls %v kept %v: dropped more than one die", r.Rolls, r.Kept)
+				}
+				dropped = i
+			}
+		}
+		if dropped < 0 || r.Rolls[dropped] != slices.Min(r.Rolls) {
+			t.Fatalf("rolls %v kept %v: should drop a lowest die", r.Rolls, r.Kept)
+		}
 	}
 }
 
@@ -96,14 +122,60 @@ func TestRollDice_Modifier(t *testing.T) {
 	rng := NewSeededRandomizer(202, 0)
 	expr, _ := ParseDice("1d6+5")
 	r := RollDice(rng, expr)
-	if r.Total != r.Subtotal+5 {
-		t.Errorf("total %d != subtotal %d + 5", r.Total, r.Subtotal)
+	if r.Total != r.Terms[0].Total+5 {
+		t.Errorf("total %d != roll %d + 5", r.Total, r.Terms[0].Total)
 	}
 
 	expr2, _ := ParseDice("1d6-3")
 	r2 := RollDice(rng, expr2)
-	if r2.Total != r2.Subtotal-3 {
-		t.Errorf("total %d != subtotal %d - 3", r2.Total, r2.Subtotal)
+	if r2.Total != r2.Terms[0].Total-3 {
+		t.Errorf("total %d != roll %d - 3", r2.Total, r2.Terms[0].Total)
+	}
+}
+
+func TestRollDice_Arithmetic(t *testing.T) {
+	rng := NewSeededRandomizer(205, 0)
+	expr, _ := ParseDice("(1d8+2)*2 - d4/2")
+	for range 50 {
+		r := RollDice(rng, expr)
+		if len(r.Terms) != 2 {
+			t.Fatalf("terms = %+v", r.Terms)
+		}
+		a, b := r.Terms[0].Total, r.Terms[1].Total
+		if want := (a+2)*2 - b/2; r.Total != want {
+			t.Fatalf("rolled %d and %d: total %d, want %d", a, b, r.Total, want)
+		}
+	}
+}
+
+func TestRollDice_Fudge(t *testing.T) {
+	rng := NewSeededRandomizer(206, 0)
+	expr, _ := ParseDice("4dF")
+	r := RollDice(rng, expr)
+	for _, v := range r.Terms[0].Rolls {
+		if v < -1 || v > 1 {
+			t.Errorf("Fudge die rolled %d", v)
+		}
+	}
+	if len(r.Trace) != 4 || !strings.HasPrefix(r.Trace[0].String(), "dF ") {
+		t.Errorf("trace = %v", r.Trace)
+	}
+}
+
+func TestRollDice_Breakdown(t *testing.T) {
+	expr, _ := ParseDice("(2d6kh1+3)*2 + 1d4")
+	r := DiceRollResult{Expression: expr, Terms: []DiceTermResult{
+		{Rolls: []int{5, 2}, Kept: []bool{true, false}},
+		{Rolls: []int{3}, Kept: []bool{true}},
+	}}
+	got := r.Breakdown(func(face string, mark DieMark) string {
+		if mark == DieKept {
+			return face
+		}
+		return "x" + face
+	})
+	if want := "(2d6kh1 5 x2 + 3) × 2 + d4 3"; got != want {
+		t.Errorf("breakdown = %q, want %q", got, want)
 	}
 }
 
@@ -111,15 +183,17 @@ func TestRollDice_Exploding(t *testing.T) {
 	rng := NewSeededRandomizer(203, 0)
 	expr, _ := ParseDice("10d6!")
 	r := RollDice(rng, expr)
-	if len(r.Rolls) != 10 {
-		t.Errorf("expected 10 rolls, got %d", len(r.Rolls))
-	}
-	// Exploding dice can produce values > sides
-	hasHigh := false
-	for _, v := range r.Rolls {
-		if v > 6 {
-			hasHigh = true
+	// Each 6 explodes into another die.
+	sixes := 0
+	for _, v := range r.Terms[0].Rolls {
+		if v < 1 || v > 6 {
+			t.Errorf("roll %d out of range", v)
+		}
+		if v == 6 {
+			sixes++
 		}
 	}
-	_ = hasHigh // just ensure no panic
+	if len(r.Terms[0].Rolls) != 10+sixes {
+		t.Errorf("expected %d rolls, got %d", 10+sixes, len(r.Terms[0].Rolls))
+	}
 }
@@ -33,6 +33,7 @@ func RandomDirection(rng *Randomizer, points int) DirectionResult {
 	case 16:
 		table = dirs16
 	}
-	d := table[rng.Intn(len(table))]
-	return DirectionResult{Direction: d.Name, Abbrev: d.Abbrev, Arrow: d.Arrow}
+	rng.beginTrace("Direction")
+	d := table[rng.RollDN(len(table))-1]
+	return DirectionResult{Direction: d.Name, Abbrev: d.Abbrev, Arrow: d.Arrow, Trace: rng.endTrace()}
 }
@@ -10,61 +10,56 @@ var dungeonLocations = [7]string{
 	"Location for a specialized purpose",
 }
 
-func dungeonEncounter(roll int) string {
-	switch {
-	case roll <= 2:
-		return "None"
-	case roll <= 4:
-		return "Hostile enemies"
-	case roll == 5:
-		return "An obstacle blocks the way"
-	default:
-		return "Unique NPC or adversary"
-	}
+var dungeonEncounters = [7]string{
+	"",
+	"None",
+	"None",
+	"Hostile enemies",
+	"Hostile enemies",
+	"An obstacle blocks the way",
+	"Unique NPC or adversary",
 }
 
-func dungeonObject(roll int) string {
-	switch {
-	case roll <= 2:
-		return "Nothing, or mundane objects"
-	case roll == 3:
-		return "An interesting item or clue"
-	case roll == 4:
-		return "A useful tool, key, or device"
-	case roll == 5:
-		return "Something valuable"
-	default:
-		return "Rare or special item"
-	}
+var dungeonObjects = [7]string{
+	"",
+	"Nothing, or mundane objects",
+	"Nothing, or mundane objects",
+	"An interesting item or clue",
+	"A useful tool, key, or device",
+	"Something valuable",
+	"Rare or special item",
 }
 
-func dungeonExits(roll int) string {
-	switch {
-	case roll <= 2:
-		return "Dead end"
-	case roll <= 4:
-		return "1 additional exit"
-	default:
-		return "2 additional exits"
-	}
+var dungeonExits = [7]string{
+	"",
+	"Dead end",
+	"Dead end",
+	"1 additional exit",
+	"1 additional exit",
+	"2 additional exits",
+	"2 additional exits",
 }
 
 func DungeonTheme(deck *Deck) DungeonThemeResult {
+	deck.rng.beginTrace("Dungeon Theme")
 	return DungeonThemeResult{
 		Looks: DetailFocus(deck),
 		Used:  ActionFocus(deck),
+		Trace: deck.rng.endTrace(),
 	}
 }
 
 func DungeonRoom(rng *Randomizer) DungeonRoomResult {
+	rng.beginTrace("Dungeon Room")
 	lr := rng.RollD6()
 	er := rng.RollD6()
 	or_ := rng.RollD6()
 	xr := rng.RollD6()
 	return DungeonRoomResult{
 		LocationRoll: lr, Location: dungeonLocations[lr],
-		EncounterRoll: er, Encounter: dungeonEncounter(er),
-		ObjectRoll: or_, Object: dungeonObject(or_),
-		ExitsRoll: xr, Exits: dungeonExits(xr),
+		EncounterRoll: er, Encounter: dungeonEncounters[er],
+		ObjectRoll: or_, Object: dungeonObjects[or_],
+		ExitsRoll: xr, Exits: dungeonExits[xr],
+		Trace: rng.endTrace(),
 	}
 }
@@ -34,16 +34,16 @@ func TestDungeonRoom_GroupedRanges(t *testing.T) {
 }
 
 func TestDungeonEncounterGrouped(t *testing.T) {
-	if dungeonEncounter(1) != "None" || dungeonEncounter(2) != "None" {
+	if dungeonEncounters[1] != "None" || dungeonEncounters[2] != "None" {
 		t.Error("1-2 should be None")
 	}
-	if dungeonEncounter(3) != "Hostile enemies" || dungeonEncounter(4) != "Hostile enemies" {
+	if dungeonEncounters[3] != "Hostile enemies" || dungeonEncounters[4] != "Hostile enemies" {
 		t.Error("3-4 should be Hostile enemies")
 	}
-	if dungeonEncounter(5) != "An obstacle blocks the way" {
+	if dungeonEncounters[5] != "An obstacle blocks the way" {
 		t.Error("5 should be obstacle")
 	}
-	if dungeonEncounter(6) != "Unique NPC or adversary" {
+	if dungeonEncounters[6] != "Unique NPC or adversary" {
 		t.Error("6 should be unique NPC")
 	}
 }
@@ -0,0 +1,201 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxExpandDepth limits how deeply table references may nest.
+const MaxExpandDepth = 8
+
+// refPattern matches a generator reference, {{Detail Focus}}, or an inline
+// roll, [[2d6]].
+var refPattern = regexp.MustCompile(`\{\{([^{}]+)\}\}|\[\[([^\[\]]+)\]\]`)
+
+// Texter is a result with a single value that can stand in for a
+// reference to its generator.
+type Texter interface {
+	Text() string
+}
+
+// expander resolves references for one generated result. Stack holds the
+// generators being expanded, outermost first, to catch cycles.
+type expander struct {
+	s     *Session
+	stack []string
+}
+
+// Generate runs the generator and resolves the references in its result.
+// Each referenced generator and inline roll is traced, nested under the
+// result's own steps. A result pointing at a plot arc, an NPC or a
+// faction names one of the session's open threads, roster NPCs or active
+// factions.
+func (g Generator) Generate(s *Session, args []string) (any, error) {
+	res, err := g.Run(s, args)
+	if err != nil {
+		return nil, err
+	}
+	return (&expander{s: s, stack: []string{g.ID}}).result(res)
+}
+
+// result returns a copy of res with the references in its table entries
+// resolved, and their steps added to its trace. A table entry is a string
+// field tagged `expand:"entry"`; other strings, such as a question the
+// player typed, are left as they are.
+func (x *expander) result(res any) (any, error) {
+	v := reflect.New(reflect.TypeOf(res)).Elem()
+	v.Set(reflect.ValueOf(res))
+	x.s.Rng.beginTrace("")
+	err := x.walk(v, false)
+	steps := x.s.Rng.endTrace()
+	if err != nil {
+		return nil, err
+	}
+	if t := v.FieldByName("Trace"); len(steps) > 0 && t.IsValid() {
+		t.Set(reflect.ValueOf(append(t.Interface().(Trace), steps...)))
+	}
+	return v.Interface(), nil
+}
+
+var traceType = reflect.TypeFor[Trace]()
+
+// walk resolves the table entries in v; entry says v is one.
+func (x *expander) walk(v reflect.Value, entry bool) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !entry {
+			return nil
+		}
+		if !refPattern.MatchString(v.String()) {
+			return nil
+		}
+		s, err := x.text(v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if f := v.Field(i); f.CanSet() && f.Type() != traceType {
+				if err := x.walk(f, v.Type().Field(i).Tag.Get("expand") == "entry"); err != nil {
+					return err
+				}
+			}
+		}
+		if !v.CanAddr() {
+			break
+		}
+		if r, ok := v.Addr().Interface().(rowResult); ok {
+			x.s.name(r)
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return x.walk(v.Elem(), entry)
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			if err := x.walk(v.Index(i), entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// text replaces every reference in s.
+func (x *expander) text(s string) (string, error) {
+	var err error
+	out := refPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if err != nil {
+			return m
+		}
+		var v string
+		sub := refPattern.FindStringSubmatch(m)
+		if sub[1] != "" {
+			v, err = x.reference(strings.TrimSpace(sub[1]))
+		} else {
+			v, err = x.roll(strings.TrimSpace(sub[2]))
+		}
+		return v
+	})
+	return out, err
+}
+
+// reference runs the generator named by ref and returns its value, with
+// its own references resolved in turn.
+func (x *expander) reference(ref string) (string, error) {
+	g, ok := findReference(ref)
+	if !ok {
+		return "", fmt.Errorf("{{%s}}: no such table or generator", ref)
+	}
+	for _, id := range x.stack {
+		if id == g.ID {
+			return "", fmt.Errorf("{{%s}}: reference cycle", ref)
+		}
+	}
+	if len(x.stack) > MaxExpandDepth {
+		return "", fmt.Errorf("{{%s}}: references nest more than %d deep", ref, MaxExpandDepth)
+	}
+	res, err := g.Run(x.s, nil)
+	if err != nil {
+		return "", fmt.Errorf("{{%s}}: %w", ref, err)
+	}
+	v := reflect.New(reflect.TypeOf(res))
+	v.Elem().Set(reflect.ValueOf(res))
+	if r, ok := v.Interface().(rowResult); ok {
+		x.s.name(r)
+	}
+	t, ok := v.Elem().Interface().(Texter)
+	if !ok {
+		return "", fmt.Errorf("{{%s}}: %s has no single value to insert", ref, g.Name)
+	}
+	x.stack = append(x.stack, g.ID)
+	defer func() { x.stack = x.stack[:len(x.stack)-1] }()
+	return x.text(t.Text())
+}
+
+// roll evaluates an inline roll such as [[2d6]].
+func (x *expander) roll(expr string) (string, error) {
+	d, err := ParseDice(expr)
+	if err != nil {
+		return "", fmt.Errorf("[[%s]]: %w", expr, err)
+	}
+	return RollDice(x.s.Rng, d).Text(), nil
+}
+
+// findReference looks a reference up by generator name, sidebar label,
+// slash command, table name or ID, ignoring case.
+func findReference(ref string) (Generator, bool) {
+	for _, g := range generators {
+		names := []string{g.Name, g.Label, g.ID}
+		if g.Sub == "" {
+			names = append(names, g.Commands()...)
+		} else if g.Command == TableCommand {
+			names = append(names, g.Sub)
+		}
+		for _, n := range names {
+			if strings.EqualFold(n, ref) {
+				return g, true
+			}
+		}
+	}
+	return Generator{}, false
+}
+
+func (r CardTableResult) Text() string         { return r.Entry }
+func (r OracleHowResult) Text() string         { return r.Result }
+func (r SceneComplicationResult) Text() string { return r.Result }
+func (r PacingMoveResult) Text() string        { return r.Result }
+func (r FailureMoveResult) Text() string       { return r.Result }
+func (r DiceRollResult) Text() string          { return strconv.Itoa(r.Total) }
+func (r DirectionResult) Text() string         { return r.Direction }
+func (r ColorResult) Text() string             { return r.Color }
+func (r SoundResult) Text() string             { return r.Sound }
+func (r TableResult) Text() string             { return r.Entry }
+
+func (r WeatherResult) Text() string {
+	return r.Condition + ", " + r.Temperature + ", " + r.Wind
+}
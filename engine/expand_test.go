@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// withTables registers, for the rest of the test, a table for each name
+// that always gives its entry.
+func withTables(t *testing.T, entries map[string]string) {
+	t.Helper()
+	var tables []Table
+	for name, entry := range entries {
+		tbl, err := ParseTable([]byte(fmt.Sprintf(`{"name": %q, "roll": "d2", "rows": [%q, %q]}`, name, entry, entry)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, tbl)
+	}
+	SetTables(tables)
+	t.Cleanup(func() { SetTables(nil) })
+}
+
+func generateTable(t *testing.T, name string) (TableResult, error) {
+	t.Helper()
+	rng := NewSeededRandomizer(120, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Utility: NewUtilityDeck(rng, false)}
+	g, _, err := LookupCommand(TableCommand, []string{name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Generate(s, nil)
+	if err != nil {
+		return TableResult{}, err
+	}
+	return res.(TableResult), nil
+}
+
+func TestExpandReferences(t *testing.T) {
+	withTables(t, map[string]string{
+		"Tavern":  "The {{color}} {{Beast}}, [[2d6]] guards",
+		"Beast":   "{{Detail Focus}} Stag",
+		"Nothing": "plain",
+	})
+	r, err := generateTable(t, "tavern")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^The \S.* \S+ Stag, \d+ guards$`).MatchString(r.Entry) || strings.ContainsAny(r.Entry, "{}[]") {
+		t.Errorf("entry = %q", r.Entry)
+	}
+	var scopes []string
+	for _, s := range r.Trace {
+		if s.Kind == StepScope {
+			scopes = append(scopes, s.Label)
+		}
+	}
+	if got := strings.Join(scopes, ", "); got != "Color, Beast, Detail Focus, Dice" {
+		t.Errorf("trace scopes = %q; trace %v", got, r.Trace)
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	withTables(t, map[string]string{
+		"Loop A":  "{{Loop B}}",
+		"Loop B":  "{{loop-a}}",
+		"Missing": "{{No Such Table}}",
+		"Complex": "{{NPC}}",
+		"Bad Die": "[[2d0]]",
+	})
+	tests := map[string]string{
+		"loop-a":  "reference cycle",
+		"missing": "no such table",
+		"complex": "no single value",
+		"bad-die": "[[2d0]]",
+	}
+	for name, want := range tests {
+		if _, err := generateTable(t, name); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", name, err, want)
+		}
+	}
+}
+
+func TestExpandDepthLimit(t *testing.T) {
+	chain := map[string]string{}
+	for i := range MaxExpandDepth + 1 {
+		chain[fmt.Sprintf("Level %d", i)] = fmt.Sprintf("{{Level %d}}", i+1)
+	}
+	chain[fmt.Sprintf("Level %d", MaxExpandDepth+1)] = "bottom"
+	withTables(t, chain)
+	if _, err := generateTable(t, "level-1"); err != nil {
+		t.Errorf("%d levels should resolve: %v", MaxExpandDepth, err)
+	}
+	if _, err := generateTable(t, "level-0"); err == nil || !strings.Contains(err.Error(), "deep") {
+		t.Errorf("err = %v, want depth limit", err)
+	}
+}
+
+func TestExpandLeavesUserTextAlone(t *testing.T) {
+	rng := NewSeededRandomizer(121, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Utility: NewUtilityDeck(rng, false)}
+	g, args, err := LookupCommand("ask", []string{"--", "Is", "the", "{{NPC}}", "here", "after", "[[2d6]]", "days?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Generate(s, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := res.(OracleYesNoResult); r.Question != "Is the {{NPC}} here after [[2d6]] days?" {
+		t.Errorf("question = %q", r.Question)
+	}
+	g, args, _ = LookupCommand("scene", []string{"The", "{{color}}", "door", "goal:", "[[d6]]", "keys"})
+	res, err = g.Generate(s, args)
+	if r, ok := res.(SetTheSceneResult); err != nil || !ok || r.Title != "The {{color}} door" || r.Goal != "[[d6]] keys" {
+		t.Errorf("scene = %+v, %v", res, err)
+	}
+}
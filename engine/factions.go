@@ -0,0 +1,270 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultFactionClock is the number of segments on a new faction's clock.
+const DefaultFactionClock = 6
+
+// Faction is a group pursuing a goal off-screen. Its progress toward the
+// goal is kept on a clock, which is done once every segment is filled.
+type Faction struct {
+	Name     string `json:"name"`
+	Goal     string `json:"goal,omitempty"`
+	Clock    int    `json:"clock"`
+	Progress int    `json:"progress,omitempty"`
+	// Relations are the faction's stances toward others, by their names.
+	Relations map[string]string `json:"relations,omitempty"`
+}
+
+// Done reports whether the faction's clock is full.
+func (f Faction) Done() bool { return f.Progress >= f.Clock }
+
+// factionRows are the OPSE rows that point at a faction: Topic Focus K,
+// Plot Hook adversary 1, and Pacing Move and Failure Move 4.
+var factionRows = []tableRow{{"Topic Focus", int(RankKing)}, {"Adversaries", 1}, {"Pacing Moves", 4}, {"Failure Moves", 4}}
+
+// factionTicks is how many segments a faction advances by on each Oracle
+// (How) roll: none when it is lacking or less than expected.
+var factionTicks = [7]int{0, 0, 0, 1, 1, 2, 3}
+
+// ActiveFactions lists the names of the factions whose clocks are not
+// yet full.
+func ActiveFactions(factions []Faction) []string {
+	var active []string
+	for _, f := range factions {
+		if !f.Done() {
+			active = append(active, f.Name)
+		}
+	}
+	return active
+}
+
+// FindFaction returns the index of the faction called name, ignoring
+// case, or -1.
+func FindFaction(factions []Faction, name string) int {
+	return slices.IndexFunc(factions, func(f Faction) bool { return strings.EqualFold(f.Name, name) })
+}
+
+// AddFaction adds a faction with an empty clock of the given number of
+// segments, or DefaultFactionClock if it is not positive, unless the name
+// is taken.
+func AddFaction(factions []Faction, name, goal string, clock int) ([]Faction, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return factions, fmt.Errorf("a faction needs a name")
+	}
+	if i := FindFaction(factions, name); i >= 0 {
+		return factions, fmt.Errorf("%s is already a faction", factions[i].Name)
+	}
+	if clock <= 0 {
+		clock = DefaultFactionClock
+	}
+	return append(factions, Faction{Name: name, Goal: strings.TrimSpace(goal), Clock: clock}), nil
+}
+
+// SplitFactionAdd reads the arguments of /faction add,
+// NAME [clock: N] [goal: GOAL]. A quoted name, as the other /faction
+// commands take, may hold anything; an unquoted one runs up to the first
+// word starting "clock:" or "goal:". The goal runs to the end, so a clock
+// comes before it. The clock is 0 when not given.
+func SplitFactionAdd(text string) (name, goal string, clock int, err error) {
+	name, text, quoted, err := cutQuoted(text)
+	if err != nil {
+		return "", "", 0, err
+	}
+	words := strings.Fields(text)
+	i := 0
+	if !quoted {
+		for i < len(words) && !isOptionWord(words[i], clockWord) && !isOptionWord(words[i], goalWord) {
+			i++
+		}
+		name = strings.Join(words[:i], " ")
+	}
+	if i < len(words) && isOptionWord(words[i], clockWord) {
+		n := words[i][len(clockWord):]
+		if i++; n == "" && i < len(words) {
+			n, i = words[i], i+1
+		}
+		if clock, err = strconv.Atoi(n); err != nil || clock < 1 {
+			return "", "", 0, fmt.Errorf("clock: needs a number of segments, not %q", n)
+		}
+	}
+	if i < len(words) {
+		if !isOptionWord(words[i], goalWord) {
+			return "", "", 0, fmt.Errorf("after the name, only clock: N and goal: GOAL")
+		}
+		rest := append([]string{words[i][len(goalWord):]}, words[i+1:]...)
+		goal = strings.TrimSpace(strings.Join(rest, " "))
+	}
+	return name, goal, clock, nil
+}
+
+// SplitFactionName takes the name of one of factions off the front of the
+// arguments of a /faction command. A quoted name is taken as it is; an
+// unquoted one is the longest run of leading words that names a faction.
+func SplitFactionName(factions []Faction, text string) (name, rest string, err error) {
+	name, rest, quoted, err := cutQuoted(text)
+	if err != nil || quoted {
+		return name, rest, err
+	}
+	words := strings.Fields(text)
+	for n := len(words); n > 0; n-- {
+		if i := FindFaction(factions, strings.Join(words[:n], " ")); i >= 0 {
+			return factions[i].Name, strings.Join(words[n:], " "), nil
+		}
+	}
+	if len(words) == 0 {
+		return "", "", fmt.Errorf("a faction needs a name")
+	}
+	return "", "", fmt.Errorf("no faction %q", strings.Join(words, " "))
+}
+
+// cutQuoted cuts a leading quoted name off text. When text doesn't start
+// with a quote it is returned trimmed, as rest, with quoted false.
+func cutQuoted(text string) (name, rest string, quoted bool, err error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, `"`) {
+		return "", text, false, nil
+	}
+	end := strings.Index(text[1:], `"`)
+	if end < 0 {
+		return "", "", false, fmt.Errorf("missing closing quote")
+	}
+	return text[1 : end+1], strings.TrimSpace(text[end+2:]), true, nil
+}
+
+const (
+	clockWord = "clock:"
+	goalWord  = "goal:"
+)
+
+// isOptionWord reports whether s starts with the option word, ignoring
+// case.
+func isOptionWord(s, word string) bool {
+	return len(s) >= len(word) && strings.EqualFold(s[:len(word)], word)
+}
+
+// RemoveFaction takes the faction called name off the list, and out of
+// the others' relations.
+func RemoveFaction(factions []Faction, name string) ([]Faction, error) {
+	i := FindFaction(factions, strings.TrimSpace(name))
+	if i < 0 {
+		return factions, fmt.Errorf("no faction %q", name)
+	}
+	gone := factions[i].Name
+	factions = slices.Delete(factions, i, i+1)
+	for _, f := range factions {
+		delete(f.Relations, gone)
+	}
+	return factions, nil
+}
+
+// RelateFactions sets the stance between two factions, both ways, such as
+// "allies" or "at war". An empty stance clears it.
+func RelateFactions(factions []Faction, a, b, stance string) error {
+	i, j := FindFaction(factions, a), FindFaction(factions, b)
+	switch {
+	case i < 0:
+		return fmt.Errorf("no faction %q", a)
+	case j < 0:
+		return fmt.Errorf("no faction %q", b)
+	case i == j:
+		return fmt.Errorf("a faction can't relate to itself")
+	}
+	stance = strings.TrimSpace(stance)
+	for _, p := range [][2]int{{i, j}, {j, i}} {
+		f, other := &factions[p[0]], factions[p[1]].Name
+		if stance == "" {
+			delete(f.Relations, other)
+			continue
+		}
+		if f.Relations == nil {
+			f.Relations = map[string]string{}
+		}
+		f.Relations[other] = stance
+	}
+	return nil
+}
+
+// AdvanceFaction fills n segments of a faction's clock, or empties them if
+// n is negative, keeping the progress within the clock.
+func AdvanceFaction(factions []Faction, name string, n int) error {
+	i := FindFaction(factions, strings.TrimSpace(name))
+	if i < 0 {
+		return fmt.Errorf("no faction %q", name)
+	}
+	factions[i].Progress = min(max(factions[i].Progress+n, 0), factions[i].Clock)
+	return nil
+}
+
+// FactionMove is what one faction did in a faction turn.
+type FactionMove struct {
+	Faction string
+	Goal    string
+	How     OracleHowResult
+	// Action is the Action Focus for what the faction did, if it advanced.
+	Action *CardTableResult
+	// Target is the faction the action was aimed at, one the mover has a
+	// stance toward, and Stance is that stance. Both are empty when the
+	// faction didn't advance or has no relations.
+	Target   string
+	Stance   string
+	Ticks    int
+	Progress int
+	Clock    int
+}
+
+// Done reports whether the move filled the faction's clock.
+func (m FactionMove) Done() bool { return m.Progress >= m.Clock }
+
+type FactionTurnResult struct {
+	Moves []FactionMove
+	Trace Trace
+}
+
+// FactionTurn advances the factions off-screen. For each faction whose
+// clock is not full, Oracle (How) says how far it gets, by factionTicks,
+// and a faction that gets anywhere draws an Action Focus for what it did,
+// aimed at one of the factions it has a stance toward, picked at random.
+// The factions' clocks are updated in place.
+func FactionTurn(factions []Faction, deck *Deck, rng *Randomizer) FactionTurnResult {
+	rng.beginTrace("Faction Turn")
+	var moves []FactionMove
+	for i := range factions {
+		f := &factions[i]
+		if f.Done() {
+			continue
+		}
+		how := OracleHow(rng)
+		m := FactionMove{Faction: f.Name, Goal: f.Goal, How: how, Ticks: factionTicks[how.Roll]}
+		if m.Ticks > 0 {
+			action := ActionFocus(deck)
+			m.Action = &action
+			m.Target, m.Stance = factionTarget(*f, rng)
+			f.Progress = min(f.Progress+m.Ticks, f.Clock)
+		}
+		m.Progress, m.Clock = f.Progress, f.Clock
+		moves = append(moves, m)
+	}
+	return FactionTurnResult{Moves: moves, Trace: rng.endTrace()}
+}
+
+// factionTarget picks one of the factions f has a stance toward, in name
+// order so the same seed picks the same one.
+func factionTarget(f Faction, rng *Randomizer) (target, stance string) {
+	if len(f.Relations) == 0 {
+		return "", ""
+	}
+	var others []string
+	for other := range f.Relations {
+		others = append(others, other)
+	}
+	slices.Sort(others)
+	target = others[rng.Intn(len(others))]
+	return target, f.Relations[target]
+}
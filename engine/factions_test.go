@@ -0,0 +1,164 @@
+package engine
+
+import "testing"
+
+func TestFactionList(t *testing.T) {
+	factions, _ := AddFaction(nil, "The Red Hand", "seize the harbor", 0)
+	factions, _ = AddFaction(factions, "Iron Guild", "", 0)
+	if _, err := AddFaction(factions, "iron guild", "", 0); err == nil {
+		t.Error("a taken name should be an error")
+	}
+	if factions[0].Clock != DefaultFactionClock {
+		t.Errorf("clock = %d, want %d", factions[0].Clock, DefaultFactionClock)
+	}
+	if more, _ := AddFaction(factions, "Grey Wardens", "", 8); more[2].Clock != 8 {
+		t.Errorf("clock = %d, want 8", more[2].Clock)
+	}
+	if err := RelateFactions(factions, "the red hand", "Iron Guild", "at war"); err != nil {
+		t.Fatal(err)
+	}
+	if factions[0].Relations["Iron Guild"] != "at war" || factions[1].Relations["The Red Hand"] != "at war" {
+		t.Errorf("relations should go both ways: %+v", factions)
+	}
+	if err := AdvanceFaction(factions, "Iron Guild", 10); err != nil || !factions[1].Done() {
+		t.Errorf("advance past the clock: %v, %+v", err, factions[1])
+	}
+	if active := ActiveFactions(factions); len(active) != 1 || active[0] != "The Red Hand" {
+		t.Errorf("active = %q", active)
+	}
+	factions, err := RemoveFaction(factions, "IRON GUILD")
+	if err != nil || len(factions) != 1 || len(factions[0].Relations) != 0 {
+		t.Errorf("remove should drop the relations too: %v, %+v", err, factions)
+	}
+}
+
+func TestFactionTurn(t *testing.T) {
+	rng := NewSeededRandomizer(25, 0)
+	deck := NewDeck(rng)
+	factions, _ := AddFaction(nil, "The Red Hand", "", 0)
+	factions, _ = AddFaction(factions, "Iron Guild", "", 0)
+	AdvanceFaction(factions, "Iron Guild", DefaultFactionClock)
+	RelateFactions(factions, "The Red Hand", "Iron Guild", "rivals")
+	for range 50 {
+		before := factions[0].Progress
+		r := FactionTurn(factions, deck, rng)
+		if len(r.Moves) != 1 || r.Moves[0].Faction != "The Red Hand" {
+			t.Fatalf("moves = %+v, want only the active faction", r.Moves)
+		}
+		m := r.Moves[0]
+		if m.Ticks != factionTicks[m.How.Roll] || (m.Action != nil) != (m.Ticks > 0) {
+			t.Errorf("move = %+v", m)
+		}
+		if advanced := m.Action != nil; advanced != (m.Target == "Iron Guild" && m.Stance == "rivals") {
+			t.Errorf("move = %+v, want an advance aimed at its rival", m)
+		}
+		if want := min(before+m.Ticks, m.Clock); m.Progress != want || factions[0].Progress != want {
+			t.Errorf("progress = %d, want %d", m.Progress, want)
+		}
+		if m.Done() {
+			return
+		}
+	}
+	t.Error("the clock never filled")
+}
+
+func TestPlotHookNamesFaction(t *testing.T) {
+	rng := NewSeededRandomizer(25, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Factions: []string{"The Red Hand"}}
+	g, _ := LookupGenerator("plot_hook")
+	for range 100 {
+		res, err := g.Generate(s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := res.(PlotHookResult); r.AdversaryRoll == 1 {
+			if r.Adversary != "A powerful organization → The Red Hand" {
+				t.Errorf("adversary = %q, want the faction named", r.Adversary)
+			}
+			return
+		}
+	}
+	t.Error("never rolled A powerful organization")
+}
+
+func TestFactionNamedByRowNotText(t *testing.T) {
+	if err := SetOverrides(map[string][]string{"adversaries": {
+		"The Syndicate", "A powerful organization", "Guardians", "Local inhabitants", "Enemy horde or force", "A new or recurring villain",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetOverrides(nil) })
+	rng := NewSeededRandomizer(28, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Factions: []string{"The Red Hand"}}
+	g, _ := LookupGenerator("plot_hook")
+	seen := map[int]bool{}
+	for range 200 {
+		res, err := g.Generate(s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := res.(PlotHookResult)
+		seen[r.AdversaryRoll] = true
+		want := map[int]string{1: "The Syndicate → The Red Hand", 2: "A powerful organization"}[r.AdversaryRoll]
+		if want != "" && r.Adversary != want {
+			t.Errorf("roll %d = %q, want %q", r.AdversaryRoll, r.Adversary, want)
+		}
+	}
+	if !seen[1] || !seen[2] {
+		t.Errorf("rolled %v", seen)
+	}
+}
+
+func TestSplitFactionAdd(t *testing.T) {
+	tests := []struct {
+		text, name, goal string
+		clock            int
+		err              bool
+	}{
+		{"The Red Hand", "The Red Hand", "", 0, false},
+		{"The Red Hand goal: Seize the docks", "The Red Hand", "Seize the docks", 0, false},
+		{"Iron Guild GOAL:Corner the ore trade", "Iron Guild", "Corner the ore trade", 0, false},
+		{`"The Red Hand" goal: Seize the docks`, "The Red Hand", "Seize the docks", 0, false},
+		{`"Goal: Keepers"`, "Goal: Keepers", "", 0, false},
+		{`"Goal: Keepers" goal: Guard the gate`, "Goal: Keepers", "Guard the gate", 0, false},
+		{"Ghosts of the goalposts", "Ghosts of the goalposts", "", 0, false},
+		{"Iron Guild clock: 8", "Iron Guild", "", 8, false},
+		{"Iron Guild clock:4 goal: Corner the ore trade", "Iron Guild", "Corner the ore trade", 4, false},
+		{`"The Red Hand" clock: 10 goal: Seize the docks`, "The Red Hand", "Seize the docks", 10, false},
+		{"Iron Guild goal: Beat the clock: 8", "Iron Guild", "Beat the clock: 8", 0, false},
+		{"Iron Guild clock: six", "", "", 0, true},
+		{"Iron Guild clock: 0", "", "", 0, true},
+		{"Iron Guild clock:", "", "", 0, true},
+		{`"The Red Hand`, "", "", 0, true},
+		{`"The Red Hand" rivals`, "", "", 0, true},
+	}
+	for _, tt := range tests {
+		name, goal, clock, err := SplitFactionAdd(tt.text)
+		if name != tt.name || goal != tt.goal || clock != tt.clock || (err != nil) != tt.err {
+			t.Errorf("SplitFactionAdd(%q) = %q, %q, %d, %v", tt.text, name, goal, clock, err)
+		}
+	}
+}
+
+func TestSplitFactionName(t *testing.T) {
+	factions := []Faction{{Name: "Iron"}, {Name: "Iron Circle"}, {Name: "The Red Hand"}}
+	tests := []struct {
+		text, name, rest string
+		err              bool
+	}{
+		{"Iron Circle", "Iron Circle", "", false},
+		{"iron circle 2", "Iron Circle", "2", false},
+		{"Iron 2", "Iron", "2", false},
+		{"Iron Circle The Red Hand at war", "Iron Circle", "The Red Hand at war", false},
+		{`"Iron Circle" 2`, "Iron Circle", "2", false},
+		{"Red Hand", "", "", true},
+		{`"Iron Circle`, "", "", true},
+		{"", "", "", true},
+	}
+	for _, tt := range tests {
+		name, rest, err := SplitFactionName(factions, tt.text)
+		if name != tt.name || rest != tt.rest || (err != nil) != tt.err {
+			t.Errorf("SplitFactionName(%q) = %q, %q, %v", tt.text, name, rest, err)
+		}
+	}
+}
@@ -25,11 +25,13 @@ var topicFocusTable = map[Rank]string{
 }
 
 func drawFocus(deck *Deck, table map[Rank]string, tableName string) CardTableResult {
+	deck.rng.beginTrace(tableName)
 	draw := deck.Draw(nil)
 	return CardTableResult{
 		Draw:      draw,
 		TableName: tableName,
 		Entry:     table[draw.Card.Rank],
+		Trace:     deck.rng.endTrace(),
 	}
 }
 
@@ -1,10 +1,12 @@
 package engine
 
 func GenericGenerator(deck *Deck, rng *Randomizer) GenericGeneratorResult {
+	rng.beginTrace("Generic Generator")
 	return GenericGeneratorResult{
 		Action:       ActionFocus(deck),
 		Detail:       DetailFocus(deck),
 		Significance: OracleHow(rng),
+		Trace:        rng.endTrace(),
 	}
 }
 
@@ -39,6 +41,7 @@ var rewards = [7]string{
 }
 
 func PlotHook(rng *Randomizer) PlotHookResult {
+	rng.beginTrace("Plot Hook")
 	o := rng.RollD6()
 	a := rng.RollD6()
 	r := rng.RollD6()
@@ -46,6 +49,7 @@ func PlotHook(rng *Randomizer) PlotHookResult {
 		ObjectiveRoll: o, Objective: objectives[o],
 		AdversaryRoll: a, Adversary: adversaries[a],
 		RewardRoll: r, Reward: rewards[r],
+		Trace: rng.endTrace(),
 	}
 }
 
@@ -76,6 +80,7 @@ var notableFeatures = [7]string{
 }
 
 func NPCGenerator(deck *Deck, rng *Randomizer) NPCResult {
+	rng.beginTrace("NPC")
 	idDraw := deck.Draw(nil)
 	identity := CardTableResult{
 		Draw: idDraw, TableName: "Identity",
@@ -101,5 +106,6 @@ func NPCGenerator(deck *Deck, rng *Randomizer) NPCResult {
 		FeatureDetail: featDetail,
 		Attitude:      attitude,
 		Topic:         topic,
+		Trace:         rng.endTrace(),
 	}
 }
@@ -1,23 +1,24 @@
 package engine
 
-func hexTerrain(roll int) string {
-	switch {
-	case roll <= 2:
-		return "Same as current hex"
-	case roll <= 4:
-		return "Common terrain"
-	case roll == 5:
-		return "Uncommon terrain"
-	default:
-		return "Rare terrain"
-	}
+var hexTerrains = [7]string{
+	"",
+	"Same as current hex",
+	"Same as current hex",
+	"Common terrain",
+	"Common terrain",
+	"Uncommon terrain",
+	"Rare terrain",
 }
 
-func hexContents(roll int) string {
-	if roll == 6 {
-		return "Roll a FEATURE"
-	}
-	return "Nothing notable"
+// hexContents on a 6 rolls a feature, whatever the row says.
+var hexContents = [7]string{
+	"",
+	"Nothing notable",
+	"Nothing notable",
+	"Nothing notable",
+	"Nothing notable",
+	"Nothing notable",
+	"Roll a FEATURE",
 }
 
 var hexFeatures = [7]string{
@@ -30,22 +31,27 @@ var hexFeatures = [7]string{
 	"DUNGEON CRAWLER entrance",
 }
 
-func hexEvent(roll int) string {
-	if roll >= 5 {
-		return "RANDOM EVENT then SET THE SCENE"
-	}
-	return "None"
+// hexEvents on a 5 or 6 adds a random event, whatever the row says.
+var hexEvents = [7]string{
+	"",
+	"None",
+	"None",
+	"None",
+	"None",
+	"RANDOM EVENT then SET THE SCENE",
+	"RANDOM EVENT then SET THE SCENE",
 }
 
 func HexCrawl(rng *Randomizer, deck *Deck) HexResult {
+	rng.beginTrace("Hex")
 	tr := rng.RollD6()
 	cr := rng.RollD6()
 	er := rng.RollD6()
 
 	result := HexResult{
-		TerrainRoll: tr, Terrain: hexTerrain(tr),
-		ContentsRoll: cr, Contents: hexContents(cr),
-		EventRoll: er, Event: hexEvent(er),
+		TerrainRoll: tr, Terrain: hexTerrains[tr],
+		ContentsRoll: cr, Contents: hexContents[cr],
+		EventRoll: er, Event: hexEvents[er],
 	}
 
 	if cr == 6 {
@@ -59,5 +65,6 @@ func HexCrawl(rng *Randomizer, deck *Deck) HexResult {
 		result.RandomEvent = &evt
 	}
 
+	result.Trace = rng.endTrace()
 	return result
 }
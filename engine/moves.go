@@ -21,16 +21,19 @@ var failureMoves = [7]string{
 }
 
 func PacingMove(rng *Randomizer, deck *Deck) PacingMoveResult {
+	rng.beginTrace("Pacing Move")
 	roll := rng.RollD6()
 	result := PacingMoveResult{Roll: roll, Result: pacingMoves[roll]}
 	if roll == 6 {
 		evt := RandomEvent(deck, rng)
 		result.RandomEvent = &evt
 	}
+	result.Trace = rng.endTrace()
 	return result
 }
 
 func FailureMove(rng *Randomizer) FailureMoveResult {
+	rng.beginTrace("Failure Move")
 	roll := rng.RollD6()
-	return FailureMoveResult{Roll: roll, Result: failureMoves[roll]}
+	return FailureMoveResult{Roll: roll, Result: failureMoves[roll], Trace: rng.endTrace()}
 }
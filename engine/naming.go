@@ -0,0 +1,67 @@
+package engine
+
+import "slices"
+
+// tableRow is a row of a built-in table: the table's name and the roll or
+// card rank that gives it. House rules and rules files change what a row
+// says but not where it is, so the rows that point at a thread, an NPC or
+// a faction are known by their place.
+type tableRow struct {
+	table string
+	roll  int
+}
+
+// rowEntry is an entry of a result and the row it was looked up from.
+type rowEntry struct {
+	row  tableRow
+	text *string
+}
+
+// rowResult is a result with entries looked up from built-in tables.
+type rowResult interface {
+	rowEntries() []rowEntry
+}
+
+func (r *CardTableResult) rowEntries() []rowEntry {
+	return []rowEntry{{tableRow{r.TableName, int(r.Draw.Card.Rank)}, &r.Entry}}
+}
+
+func (r *SceneComplicationResult) rowEntries() []rowEntry {
+	return []rowEntry{{tableRow{"Scene Complications", r.Roll}, &r.Result}}
+}
+
+func (r *PacingMoveResult) rowEntries() []rowEntry {
+	return []rowEntry{{tableRow{"Pacing Moves", r.Roll}, &r.Result}}
+}
+
+func (r *FailureMoveResult) rowEntries() []rowEntry {
+	return []rowEntry{{tableRow{"Failure Moves", r.Roll}, &r.Result}}
+}
+
+func (r *PlotHookResult) rowEntries() []rowEntry {
+	return []rowEntry{
+		{tableRow{"Objectives", r.ObjectiveRoll}, &r.Objective},
+		{tableRow{"Adversaries", r.AdversaryRoll}, &r.Adversary},
+		{tableRow{"Rewards", r.RewardRoll}, &r.Reward},
+	}
+}
+
+// name names a thread, roster NPC or faction after each entry of res from
+// a row that points at one.
+func (s *Session) name(res rowResult) {
+	for _, e := range res.rowEntries() {
+		text := s.nameAfter(*e.text, e.row, plotArcRows, s.Threads)
+		text = s.nameAfter(text, e.row, npcRows, s.NPCs)
+		*e.text = s.nameAfter(text, e.row, factionRows, s.Factions)
+	}
+}
+
+// nameAfter names one of names, picked at random, after an entry from row
+// if it is one of rows. Other entries, or no names, are returned as they
+// are.
+func (s *Session) nameAfter(text string, row tableRow, rows []tableRow, names []string) string {
+	if len(names) == 0 || !slices.Contains(rows, row) {
+		return text
+	}
+	return text + " → " + names[s.Rng.Intn(len(names))]
+}
@@ -0,0 +1,562 @@
+package engine
+
+import (
+	"maps"
+	"math"
+	"sort"
+)
+
+// Distribution is the chance of each total of a dice expression, P[i]
+// being the chance of Min+i. Samples is how many rolls it was estimated
+// from when it was too large to work out exactly, or 0.
+type Distribution struct {
+	Min     int
+	P       []float64
+	Samples int
+}
+
+// OddsSamples is how many rolls estimate a distribution too large to work
+// out exactly.
+const OddsSamples = 100_000
+
+// maxOddsWork caps the steps spent working out a distribution exactly, a
+// fraction of a second; past it the distribution is estimated instead.
+const maxOddsWork = 10_000_000
+
+// maxSampledDice caps the dice rolled to estimate a distribution, so a
+// huge expression is estimated from fewer rolls, though never fewer than
+// minOddsSamples.
+const maxSampledDice, minOddsSamples = 1_000_000, 1_000
+
+// Max is the highest possible total.
+func (d Distribution) Max() int { return d.Min + len(d.P) - 1 }
+
+// Mean is the expected total.
+func (d Distribution) Mean() float64 {
+	var m float64
+	for i, p := range d.P {
+		m += float64(d.Min+i) * p
+	}
+	return m
+}
+
+// Percentile is the lowest total rolled at least q of the time, e.g. the
+// median for q = 0.5.
+func (d Distribution) Percentile(q float64) int {
+	var sum float64
+	for i, p := range d.P {
+		sum += p
+		if sum >= q-1e-9 {
+			return d.Min + i
+		}
+	}
+	return d.Max()
+}
+
+// AtLeast is the chance of rolling n or more.
+func (d Distribution) AtLeast(n int) float64 {
+	var sum float64
+	for i := max(n-d.Min, 0); i < len(d.P); i++ {
+		sum += d.P[i]
+	}
+	return min(sum, 1)
+}
+
+// dist is a distribution under construction.
+type dist map[int]float64
+
+func (d dist) distribution() Distribution {
+	lo, hi := math.MaxInt, math.MinInt
+	for v, p := range d {
+		if p > 0 {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if lo > hi {
+		return Distribution{P: []float64{1}}
+	}
+	out := Distribution{Min: lo, P: make([]float64, hi-lo+1)}
+	for v, p := range d {
+		if p > 0 {
+			out.P[v-lo] += p
+		}
+	}
+	return out
+}
+
+func (d dist) span() (int, int) {
+	lo, hi := math.MaxInt, math.MinInt
+	for v := range d {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return lo, hi
+}
+
+// prune drops outcomes too unlikely to matter, such as dice exploding
+// twenty times running.
+func prune[K comparable](d map[K]float64) map[K]float64 {
+	maps.DeleteFunc(d, func(_ K, p float64) bool { return p < 1e-15 })
+	return d
+}
+
+// oddsCalc counts the steps taken to work out a distribution, so that it
+// can give up on one too large to work out in good time.
+type oddsCalc struct{ work int }
+
+// spend counts n more steps, reporting false once past maxOddsWork.
+func (c *oddsCalc) spend(n int) bool {
+	c.work += n
+	return c.work <= maxOddsWork
+}
+
+// combine is the distribution of f(a, b) for independent a and b.
+func (c *oddsCalc) combine(a, b dist, f func(x, y int) int) (dist, bool) {
+	if !c.spend(len(a) * len(b)) {
+		return nil, false
+	}
+	out := dist{}
+	for x, px := range a {
+		for y, py := range b {
+			out[f(x, y)] += px * py
+		}
+	}
+	return out, true
+}
+
+// add is the distribution of a + b, summed over dense arrays since the
+// sum of many dice spreads wide.
+func (c *oddsCalc) add(a, b dist) (dist, bool) {
+	alo, ahi := a.span()
+	blo, bhi := b.span()
+	if !c.spend((ahi - alo + 1) * (bhi - blo + 1) / 64) {
+		return nil, false
+	}
+	bp := make([]float64, bhi-blo+1)
+	for v, p := range b {
+		bp[v-blo] = p
+	}
+	sum := make([]float64, ahi-alo+bhi-blo+1)
+	for x, px := range a {
+		for j, py := range bp {
+			sum[x-alo+j] += px * py
+		}
+	}
+	out := dist{}
+	for i, p := range sum {
+		if p >= 1e-15 {
+			out[alo+blo+i] = p
+		}
+	}
+	return out, true
+}
+
+// DiceOdds works out the chance of each total the expression can roll.
+// Exploding and rerolled dice are followed as deep as RollDice follows
+// them. An expression too large to work out in good time is estimated by
+// rolling it up to OddsSamples times.
+func DiceOdds(expr DiceExpression) Distribution {
+	c := &oddsCalc{}
+	d, ok := c.node(expr.Root)
+	if !ok {
+		return sampleOdds(expr)
+	}
+	return d.distribution()
+}
+
+func (c *oddsCalc) node(n *DiceNode) (dist, bool) {
+	switch {
+	case n.Dice != nil:
+		return c.term(*n.Dice)
+	case n.Op == 0:
+		return dist{n.Value: 1}, true
+	case n.Op == 'n':
+		l, ok := c.node(n.Left)
+		if !ok {
+			return nil, false
+		}
+		return c.combine(l, dist{0: 1}, func(x, _ int) int { return -x })
+	}
+	l, ok1 := c.node(n.Left)
+	if !ok1 {
+		return nil, false
+	}
+	r, ok2 := c.node(n.Right)
+	if !ok2 {
+		return nil, false
+	}
+	if n.Op == '+' {
+		return c.add(l, r)
+	}
+	return c.combine(l, r, func(x, y int) int { return applyOp(n.Op, x, y) })
+}
+
+// faceOdds is a single die's chance of each face.
+func (t DiceTerm) faceOdds() dist {
+	lo, hi := t.faces()
+	d := dist{}
+	for v := lo; v <= hi; v++ {
+		d[v] = 1 / float64(hi-lo+1)
+	}
+	return d
+}
+
+// firstOdds is a die's chance of each face after any reroll.
+func (t DiceTerm) firstOdds() dist {
+	faces := t.faceOdds()
+	if t.Reroll == "" {
+		return faces
+	}
+	var hit float64
+	for v, p := range faces {
+		if t.RerollOn.Match(v) {
+			hit += p
+		}
+	}
+	d := dist{}
+	for v, p := range faces {
+		if t.Reroll == "r" {
+			d[v] += hit * p
+		}
+		if !t.RerollOn.Match(v) {
+			if t.Reroll == "r" {
+				d[v] += p
+			} else {
+				d[v] += p / (1 - hit)
+			}
+		}
+	}
+	return d
+}
+
+func (t DiceTerm) explodes(v int) bool {
+	switch {
+	case t.Explode == "":
+		return false
+	case t.ExplodeOn.Op == "":
+		return v == t.Sides
+	}
+	return t.ExplodeOn.Match(v)
+}
+
+// count is what a die showing v adds to the term: its value, or for a
+// pool 1 for a success.
+func (t DiceTerm) count(v int) int {
+	if !t.IsPool() {
+		return v
+	}
+	if t.Success.Match(v) {
+		return 1
+	}
+	return 0
+}
+
+// chainOdds is the chance of each amount one die adds to the term,
+// counting the dice it explodes into. A compounding die is counted once,
+// on its total.
+func (c *oddsCalc) chainOdds(t DiceTerm) (dist, bool) {
+	if t.Explode == "" {
+		d := dist{}
+		for v, p := range t.firstOdds() {
+			d[t.count(v)] += p
+		}
+		return d, true
+	}
+	faces := t.faceOdds()
+	// Working up from the deepest explosion, rest is what the die one
+	// explosion deeper adds, along with everything it explodes into.
+	var rest dist
+	for depth := maxDiceRerolls; depth >= 0; depth-- {
+		start := faces
+		if depth == 0 {
+			start = t.firstOdds()
+		}
+		if !c.spend(len(start) * max(len(rest), 1)) {
+			return nil, false
+		}
+		next := dist{}
+		for v, p := range start {
+			val := v
+			if t.Explode == "!p" && depth > 0 {
+				val--
+			}
+			switch {
+			case !t.explodes(v) || depth == maxDiceRerolls:
+				next[t.chainValue(val)] += p
+			case t.Explode == "!!":
+				// rest holds raw totals; they are counted at the top.
+				for r, pr := range rest {
+					next[val+r] += p * pr
+				}
+			default:
+				for r, pr := range rest {
+					next[t.count(val)+r] += p * pr
+				}
+			}
+		}
+		rest = prune(next)
+	}
+	if t.Explode == "!!" {
+		counted := dist{}
+		for v, p := range rest {
+			counted[t.count(v)] += p
+		}
+		return counted, true
+	}
+	return rest, true
+}
+
+// chainValue is what the last die of a chain adds: its count, or for a
+// compounding chain its raw value, counted once the chain is summed.
+func (t DiceTerm) chainValue(v int) int {
+	if t.Explode == "!!" {
+		return v
+	}
+	return t.count(v)
+}
+
+func (c *oddsCalc) term(t DiceTerm) (dist, bool) {
+	switch {
+	case t.KeepMode != "" && (t.Explode == "!" || t.Explode == "!p"):
+		return c.explodeKeepOdds(t)
+	case t.KeepMode != "":
+		return c.keepOdds(t)
+	}
+	chain, ok := c.chainOdds(t)
+	if !ok {
+		return nil, false
+	}
+	// Summing n dice takes about n²/2 times the chain's width squared;
+	// give up before starting if that is past the budget.
+	lo, hi := chain.span()
+	if w := hi - lo + 1; c.work+t.Count*t.Count/2*w*w/64 > maxOddsWork {
+		return nil, false
+	}
+	d := dist{0: 1}
+	for range t.Count {
+		if d, ok = c.add(d, chain); !ok {
+			return nil, false
+		}
+	}
+	return d, true
+}
+
+// keepOdds works out a keep or drop term from the order statistics of its
+// dice: going through the faces from the first kept end, it tracks how
+// many dice have been placed and the total of those kept.
+func (c *oddsCalc) keepOdds(t DiceTerm) (dist, bool) {
+	die, ok := c.chainOdds(t)
+	if !ok {
+		return nil, false
+	}
+	if t.IsPool() {
+		// Keep by face, then count: the chain gave counts, not faces.
+		die = t.firstOdds()
+		if t.Explode == "!!" {
+			raw := t
+			raw.Success = DiceCompare{}
+			if die, ok = c.chainOdds(raw); !ok {
+				return nil, false
+			}
+		}
+	}
+	faces := make([]int, 0, len(die))
+	for v := range die {
+		faces = append(faces, v)
+	}
+	high := t.KeepMode == "kh" || t.KeepMode == "dl"
+	sort.Slice(faces, func(i, j int) bool {
+		if high {
+			return faces[i] > faces[j]
+		}
+		return faces[i] < faces[j]
+	})
+	n, keep := t.Count, t.kept(t.Count)
+
+	// placed[j] is the chance of each kept total with j dice placed.
+	placed := make([]dist, n+1)
+	placed[0] = dist{0: 1}
+	left := 1.0 // chance a die is on a face not yet reached
+	for _, v := range faces {
+		q := min(die[v]/left, 1)
+		if left <= 1e-12 {
+			q = 1
+		}
+		left -= die[v]
+		next := make([]dist, n+1)
+		for j, totals := range placed {
+			if totals == nil {
+				continue
+			}
+			r := n - j
+			if !c.spend((r + 1) * len(totals)) {
+				return nil, false
+			}
+			for k := 0; k <= r; k++ {
+				pk := binomial(r, k) * math.Pow(q, float64(k)) * math.Pow(1-q, float64(r-k))
+				if pk == 0 {
+					continue
+				}
+				kept := max(min(k, keep-j), 0)
+				if next[j+k] == nil {
+					next[j+k] = dist{}
+				}
+				for s, p := range totals {
+					next[j+k][s+kept*t.count(v)] += p * pk
+				}
+			}
+		}
+		for _, totals := range next {
+			prune(totals)
+		}
+		placed = next
+	}
+	return placed[n], true
+}
+
+// keepState is where a keep or drop term with exploding dice stands after
+// some of its dice: held are the dice the keep or drop could still pick,
+// in order, and rest the total of those already passed over that count.
+type keepState struct {
+	held string // a byte per die, its value plus heldOffset
+	rest int
+}
+
+// heldOffset shifts die values into bytes: the lowest, a Fudge die's -1
+// less one for a penetrating explosion, becomes 0.
+const heldOffset = 2
+
+// hold adds a die showing v to the held dice. Once more than KeepN are
+// held, the one furthest from the picked end is passed over: dropped by
+// kh or kl, and counted by dh or dl.
+func (s keepState) hold(t DiceTerm, v int) keepState {
+	b := byte(v + heldOffset)
+	i := 0
+	for i < len(s.held) && s.held[i] <= b {
+		i++
+	}
+	s.held = s.held[:i] + string([]byte{b}) + s.held[i:]
+	if len(s.held) <= t.KeepN {
+		return s
+	}
+	// Keep and drop highest hold the top dice, so pass over the lowest.
+	out := len(s.held) - 1
+	if t.KeepMode == "kh" || t.KeepMode == "dh" {
+		out = 0
+	}
+	if t.KeepMode == "dh" || t.KeepMode == "dl" {
+		s.rest += t.count(int(s.held[out]) - heldOffset)
+	}
+	s.held = s.held[:out] + s.held[out+1:]
+	return s
+}
+
+// explodeKeepOdds works out a keep or drop term whose dice explode into
+// extra dice, which are kept or dropped like the rest. It follows the
+// dice one at a time, as RollDice rolls them, tracking each keepState.
+func (c *oddsCalc) explodeKeepOdds(t DiceTerm) (dist, bool) {
+	faces, first := t.faceOdds(), t.firstOdds()
+	states := map[keepState]float64{{}: 1}
+	for range t.Count {
+		done := map[keepState]float64{}
+		live, roll := states, first
+		for depth := 0; len(live) > 0; depth++ {
+			if !c.spend(len(live) * len(roll) * 4) {
+				return nil, false
+			}
+			next := map[keepState]float64{}
+			for s, p := range live {
+				for v, pv := range roll {
+					val := v
+					if t.Explode == "!p" && depth > 0 {
+						val--
+					}
+					if t.explodes(v) && depth < maxDiceRerolls {
+						next[s.hold(t, val)] += p * pv
+					} else {
+						done[s.hold(t, val)] += p * pv
+					}
+				}
+			}
+			live, roll = prune(next), faces
+		}
+		states = prune(done)
+	}
+	out := dist{}
+	for s, p := range states {
+		total := s.rest
+		if t.KeepMode == "kh" || t.KeepMode == "kl" {
+			for _, b := range []byte(s.held) {
+				total += t.count(int(b) - heldOffset)
+			}
+		}
+		out[total] += p
+	}
+	return out, true
+}
+
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+// sampleOdds estimates the distribution by rolling the expression
+// OddsSamples times, or fewer if that would roll more than maxSampledDice
+// dice.
+func sampleOdds(expr DiceExpression) Distribution {
+	rng := NewSeededRandomizer(uint64(len(expr.Raw)), 0)
+	counts := map[int]int{}
+	n, dice := 0, 0
+	for ; n < OddsSamples && (n < minOddsSamples || dice < maxSampledDice); n++ {
+		res := RollDice(rng, expr)
+		counts[res.Total]++
+		for _, tr := range res.Terms {
+			dice += len(tr.Rolls)
+		}
+	}
+	d := dist{}
+	for v, k := range counts {
+		d[v] = float64(k) / float64(n)
+	}
+	out := d.distribution()
+	out.Samples = n
+	return out
+}
+
+// OracleOdds is the chance of each yes/no oracle answer for a likelihood.
+type OracleOdds struct {
+	Likelihood Likelihood
+	Yes, No    float64
+	And, But   float64 // the modifier's chances, the same for Yes and No
+}
+
+// OracleOddsFor works out the yes/no oracle's chances on each rung of the
+// ladder, with bonus added to the roll.
+func OracleOddsFor(ladder []Likelihood, bonus int) []OracleOdds {
+	out := make([]OracleOdds, len(ladder))
+	for i, l := range ladder {
+		// One d6 makes it with p; with an edge, any of the dice (the
+		// highest) or all of them (the lowest) must.
+		yes := 0
+		for v := 1; v <= 6; v++ {
+			if v+bonus >= l.Threshold {
+				yes++
+			}
+		}
+		p := float64(yes) / 6
+		dice := max(l.Edge, -l.Edge) + 1
+		if l.Edge > 0 {
+			p = 1 - math.Pow(1-p, float64(dice))
+		} else {
+			p = math.Pow(p, float64(dice))
+		}
+		out[i] = OracleOdds{
+			Likelihood: l,
+			Yes:        p, No: 1 - p,
+			And: 1.0 / 6, But: 1.0 / 6,
+		}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDiceOdds(t *testing.T) {
+	tests := []struct {
+		expr   string
+		mean   float64
+		lo, hi int
+	}{
+		{"2d6", 7, 2, 12},
+		{"4d6kh3", 12.2446, 3, 18},
+		{"4d6dl1", 12.2446, 3, 18},
+		{"2d20kl1", 7.175, 1, 20},
+		{"(d8+2)*2", 13, 6, 20},
+		{"4dF", 0, -4, 4},
+		{"d6!", 4.2, 1, -1},
+		{"d6r1", 3.9167, 1, 6},
+		{"6d10>=7", 2.4, 0, 6},
+	}
+	for _, tt := range tests {
+		expr, err := ParseDice(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := DiceOdds(expr)
+		if d.Samples != 0 {
+			t.Errorf("%s: should be exact", tt.expr)
+		}
+		if math.Abs(d.Mean()-tt.mean) > 1e-4 {
+			t.Errorf("%s: mean %.4f, want %.4f", tt.expr, d.Mean(), tt.mean)
+		}
+		if d.Min != tt.lo || (tt.hi >= 0 && d.Max() != tt.hi) {
+			t.Errorf("%s: totals %d-%d, want %d-%d", tt.expr, d.Min, d.Max(), tt.lo, tt.hi)
+		}
+		if sum := d.AtLeast(d.Min); math.Abs(sum-1) > 1e-9 {
+			t.Errorf("%s: chances sum to %f", tt.expr, sum)
+		}
+	}
+}
+
+func TestDiceOddsExplodeKeep(t *testing.T) {
+	tests := []struct {
+		expr string
+		mean float64
+	}{
+		{"4d6!kh3", 12.85},
+		{"3d6!dl1", 10.5},
+		{"2d6!pkl1", 2.9},
+		{"5d10!>=9kh2>=8", 1.3},
+	}
+	for _, tt := range tests {
+		expr, _ := ParseDice(tt.expr)
+		d := DiceOdds(expr)
+		if d.Samples != 0 {
+			t.Errorf("%s: should be exact", tt.expr)
+		}
+		if sum := d.AtLeast(d.Min); math.Abs(sum-1) > 1e-9 {
+			t.Errorf("%s: chances sum to %f", tt.expr, sum)
+		}
+		// Check against rolling it.
+		rng := NewSeededRandomizer(7, 0)
+		var total float64
+		for range 40_000 {
+			total += float64(RollDice(rng, expr).Total)
+		}
+		if rolled := total / 40_000; math.Abs(d.Mean()-rolled) > 0.05*max(tt.mean, 1) {
+			t.Errorf("%s: mean %.3f, rolled %.3f", tt.expr, d.Mean(), rolled)
+		}
+	}
+}
+
+func TestDiceOddsSampled(t *testing.T) {
+	for _, s := range []string{"999d100", "99d100!>=50", "99d100kh50"} {
+		expr, _ := ParseDice(s)
+		start := time.Now()
+		d := DiceOdds(expr)
+		if d.Samples < minOddsSamples || time.Since(start) > 3*time.Second {
+			t.Errorf("%s: estimated from %d rolls in %v", s, d.Samples, time.Since(start))
+		}
+	}
+	expr, _ := ParseDice("999d100")
+	if d := DiceOdds(expr); math.Abs(d.Mean()-50449.5) > 200 {
+		t.Errorf("999d100: mean %.1f", d.Mean())
+	}
+}
+
+func TestDistributionStats(t *testing.T) {
+	expr, _ := ParseDice("2d6")
+	d := DiceOdds(expr)
+	if got := d.Percentile(0.5); got != 7 {
+		t.Errorf("median = %d", got)
+	}
+	if got := d.AtLeast(8); math.Abs(got-15.0/36) > 1e-9 {
+		t.Errorf("P(>= 8) = %f", got)
+	}
+	if d.AtLeast(13) != 0 || d.AtLeast(-5) < 1-1e-9 {
+		t.Error("AtLeast outside the totals")
+	}
+}
+
+func TestOracleOdds(t *testing.T) {
+	odds := OracleOddsFor(DefaultLikelihoods(), -1)
+	likely := odds[2]
+	if likely.Likelihood.Name != "Likely" || math.Abs(likely.Yes-3.0/6) > 1e-9 || math.Abs(likely.Yes+likely.No-1) > 1e-9 {
+		t.Errorf("Likely -1 = %+v", likely)
+	}
+	if math.Abs(odds[0].Yes-8.0/9) > 1e-9 || odds[6].Yes != 0 {
+		t.Errorf("Almost Certain -1 = %+v, Nearly Impossible -1 = %+v", odds[0], odds[6])
+	}
+	odds = OracleOddsFor(DefaultLikelihoods(), 0)
+	if math.Abs(odds[0].Yes-35.0/36) > 1e-9 || math.Abs(odds[6].Yes-1.0/36) > 1e-9 {
+		t.Errorf("Almost Certain = %+v, Nearly Impossible = %+v", odds[0], odds[6])
+	}
+}
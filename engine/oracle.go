@@ -1,18 +1,82 @@
 package engine
 
-func OracleYesNo(rng *Randomizer, likelihood string) OracleYesNoResult {
-	answerRoll := rng.RollD6()
-	modRoll := rng.RollD6()
+import (
+	"fmt"
+	"strings"
+)
 
-	threshold := 4
-	switch likelihood {
-	case "Likely":
-		threshold = 3
-	case "Unlikely":
-		threshold = 5
+// Likelihood is one rung of the yes/no oracle's ladder: the answer is Yes
+// when the d6 (or the kept one, with an Edge), plus any modifier for the
+// question, reaches Threshold.
+type Likelihood struct {
+	Name      string `json:"name"`
+	Threshold int    `json:"threshold"`
+	// Edge is how many more d6 are rolled for the answer: the highest
+	// counts when it is positive, the lowest when it is negative.
+	Edge int    `json:"edge,omitempty"`
+	Key  string `json:"key,omitempty"` // shortcut key, or ""
+}
+
+// DefaultLikelihoods extends OPSE's Likely/Even/Unlikely with two rungs
+// either side. A single d6 can't go past Very Likely and Very Unlikely
+// and still give both answers, so Almost Certain takes the better of two
+// and Nearly Impossible the worse.
+func DefaultLikelihoods() []Likelihood {
+	return []Likelihood{
+		{Name: "Almost Certain", Threshold: 2, Edge: 1, Key: "!"},
+		{Name: "Very Likely", Threshold: 2, Key: "@"},
+		{Name: "Likely", Threshold: 3, Key: "1"},
+		{Name: "Even", Threshold: 4, Key: "2"},
+		{Name: "Unlikely", Threshold: 5, Key: "3"},
+		{Name: "Very Unlikely", Threshold: 6, Key: "#"},
+		{Name: "Nearly Impossible", Threshold: 6, Edge: -1, Key: "$"},
 	}
+}
+
+// Needs describes the roll the answer Yes needs, e.g. "3+ (d6)" or "6+
+// (worst of 2d6)".
+func (l Likelihood) Needs() string {
+	switch {
+	case l.Edge > 0:
+		return fmt.Sprintf("%d+ (best of %dd6)", l.Threshold, l.Edge+1)
+	case l.Edge < 0:
+		return fmt.Sprintf("%d+ (worst of %dd6)", l.Threshold, 1-l.Edge)
+	}
+	return fmt.Sprintf("%d+ (d6)", l.Threshold)
+}
+
+// Slug is the likelihood's name as a single command word, e.g.
+// "very-likely".
+func (l Likelihood) Slug() string {
+	return strings.ToLower(strings.Join(strings.Fields(l.Name), "-"))
+}
+
+// FindLikelihood looks up name on the ladder, ignoring case and accepting
+// the slug form.
+func FindLikelihood(ladder []Likelihood, name string) (Likelihood, error) {
+	for _, l := range ladder {
+		if strings.EqualFold(l.Name, name) || strings.EqualFold(l.Slug(), name) {
+			return l, nil
+		}
+	}
+	return Likelihood{}, fmt.Errorf("unknown likelihood %q", name)
+}
+
+// OracleYesNo answers with the d6 plus bonus against l's threshold.
+func OracleYesNo(rng *Randomizer, l Likelihood, bonus int) OracleYesNoResult {
+	rng.beginTrace("Oracle (Yes/No)")
+	answerRoll := rng.RollD6()
+	var dropped []int
+	for range max(l.Edge, -l.Edge) {
+		v := rng.RollD6()
+		if (l.Edge > 0) == (v > answerRoll) {
+			answerRoll, v = v, answerRoll
+		}
+		dropped = append(dropped, v)
+	}
+	modRoll := rng.RollD6()
 
-	answer := answerRoll >= threshold
+	answer := answerRoll+bonus >= l.Threshold
 
 	var modifier string
 	switch modRoll {
@@ -23,11 +87,15 @@ func OracleYesNo(rng *Randomizer, likelihood string) OracleYesNoResult {
 	}
 
 	return OracleYesNoResult{
-		Likelihood: likelihood,
+		Likelihood: l.Name,
+		Threshold:  l.Threshold,
 		AnswerRoll: answerRoll,
+		Dropped:    dropped,
+		Bonus:      bonus,
 		Answer:     answer,
 		ModRoll:    modRoll,
 		Modifier:   modifier,
+		Trace:      rng.endTrace(),
 	}
 }
 
@@ -42,6 +110,7 @@ var oracleHowTable = [7]string{
 }
 
 func OracleHow(rng *Randomizer) OracleHowResult {
+	rng.beginTrace("Oracle (How)")
 	roll := rng.RollD6()
-	return OracleHowResult{Roll: roll, Result: oracleHowTable[roll]}
+	return OracleHowResult{Roll: roll, Result: oracleHowTable[roll], Trace: rng.endTrace()}
 }
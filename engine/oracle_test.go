@@ -2,10 +2,19 @@ package engine
 
 import "testing"
 
+// rung returns the named likelihood from the default ladder.
+func rung(name string) Likelihood {
+	l, err := FindLikelihood(DefaultLikelihoods(), name)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
 func TestOracleYesNo_Likely(t *testing.T) {
 	rng := NewSeededRandomizer(42, 0)
 	for range 100 {
-		r := OracleYesNo(rng, "Likely")
+		r := OracleYesNo(rng, rung("Likely"), 0)
 		if r.AnswerRoll >= 3 && !r.Answer {
 			t.Errorf("Likely: roll %d should be Yes", r.AnswerRoll)
 		}
@@ -18,7 +27,7 @@ func TestOracleYesNo_Likely(t *testing.T) {
 func TestOracleYesNo_Even(t *testing.T) {
 	rng := NewSeededRandomizer(43, 0)
 	for range 100 {
-		r := OracleYesNo(rng, "Even")
+		r := OracleYesNo(rng, rung("Even"), 0)
 		if r.AnswerRoll >= 4 && !r.Answer {
 			t.Errorf("Even: roll %d should be Yes", r.AnswerRoll)
 		}
@@ -31,7 +40,7 @@ func TestOracleYesNo_Even(t *testing.T) {
 func TestOracleYesNo_Unlikely(t *testing.T) {
 	rng := NewSeededRandomizer(44, 0)
 	for range 100 {
-		r := OracleYesNo(rng, "Unlikely")
+		r := OracleYesNo(rng, rung("Unlikely"), 0)
 		if r.AnswerRoll >= 5 && !r.Answer {
 			t.Errorf("Unlikely: roll %d should be Yes", r.AnswerRoll)
 		}
@@ -45,7 +54,7 @@ func TestOracleYesNo_Modifier(t *testing.T) {
 	rng := NewSeededRandomizer(45, 0)
 	sawBut, sawAnd, sawNone := false, false, false
 	for range 1000 {
-		r := OracleYesNo(rng, "Even")
+		r := OracleYesNo(rng, rung("Even"), 0)
 		switch r.ModRoll {
 		case 1:
 			if r.Modifier != "but..." {
@@ -69,6 +78,46 @@ func TestOracleYesNo_Modifier(t *testing.T) {
 	}
 }
 
+func TestOracleYesNo_Bonus(t *testing.T) {
+	rng := NewSeededRandomizer(46, 0)
+	for range 100 {
+		r := OracleYesNo(rng, rung("Very Unlikely"), 2)
+		if r.Answer != (r.AnswerRoll >= 4) || r.Bonus != 2 || r.Threshold != 6 {
+			t.Errorf("roll %d +2 against 6+: %+v", r.AnswerRoll, r)
+		}
+	}
+}
+
+func TestOracleYesNo_Edge(t *testing.T) {
+	rng := NewSeededRandomizer(47, 0)
+	for _, name := range []string{"Almost Certain", "Nearly Impossible"} {
+		l := rung(name)
+		answers := map[bool]bool{}
+		for range 500 {
+			r := OracleYesNo(rng, l, 0)
+			if len(r.Dropped) != 1 || (l.Edge > 0) != (r.AnswerRoll >= r.Dropped[0]) && r.AnswerRoll != r.Dropped[0] {
+				t.Fatalf("%s kept %d over %v", name, r.AnswerRoll, r.Dropped)
+			}
+			answers[r.Answer] = true
+		}
+		if !answers[true] || !answers[false] {
+			t.Errorf("%s should give both answers, gave %v", name, answers)
+		}
+	}
+}
+
+func TestFindLikelihood(t *testing.T) {
+	ladder := DefaultLikelihoods()
+	for _, name := range []string{"Very Likely", "very likely", "very-likely", "VERY-LIKELY"} {
+		if l, err := FindLikelihood(ladder, name); err != nil || l.Threshold != 2 {
+			t.Errorf("FindLikelihood(%q) = %+v, %v", name, l, err)
+		}
+	}
+	if _, err := FindLikelihood(ladder, "Probable"); err == nil {
+		t.Error("unknown likelihood should be rejected")
+	}
+}
+
 func TestOracleHow_AllValues(t *testing.T) {
 	rng := NewSeededRandomizer(50, 0)
 	seen := make(map[string]bool)
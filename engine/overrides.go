@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// builtinTable is an OPSE table that house rules may replace. Exactly one
+// of d6, cards and list is set.
+type builtinTable struct {
+	name     string
+	rules    string          // heading in the OPSE rules text, if it has one
+	d6       *[7]string      // rows 1-6
+	cards    map[Rank]string // rows 2 to A
+	list     *[]string       // any number of rows
+	compiled []string        // rows as built in
+	orig     []string        // rows before house rules: compiled, or from a rules text
+}
+
+var builtinTables = []*builtinTable{
+	{name: "Action Focus", rules: "ACTION FOCUS", cards: actionFocusTable},
+	{name: "Detail Focus", rules: "DETAIL FOCUS", cards: detailFocusTable},
+	{name: "Topic Focus", rules: "TOPIC FOCUS", cards: topicFocusTable},
+	{name: "Oracle How", rules: "ORACLE (HOW)", d6: &oracleHowTable},
+	{name: "Scene Complications", rules: "SCENE COMPLICATION", d6: &sceneComplications},
+	{name: "Altered Scenes", rules: "ALTERED SCENE", d6: &alteredScenes},
+	{name: "Pacing Moves", rules: "PACING MOVES", d6: &pacingMoves},
+	{name: "Failure Moves", rules: "FAILURE MOVES", d6: &failureMoves},
+	{name: "Objectives", rules: "OBJECTIVE", d6: &objectives},
+	{name: "Adversaries", rules: "ADVERSARIES", d6: &adversaries},
+	{name: "Rewards", rules: "REWARDS", d6: &rewards},
+	{name: "Identity", rules: "IDENTITY", cards: identityTable},
+	{name: "Goal", rules: "GOAL", cards: goalTable},
+	{name: "Notable Features", rules: "NOTABLE FEATURE", d6: &notableFeatures},
+	{name: "Dungeon Locations", rules: "LOCATION", d6: &dungeonLocations},
+	{name: "Dungeon Encounters", rules: "ENCOUNTER", d6: &dungeonEncounters},
+	{name: "Dungeon Objects", rules: "OBJECT", d6: &dungeonObjects},
+	{name: "Dungeon Exits", rules: "TOTAL EXITS", d6: &dungeonExits},
+	{name: "Hex Terrain", rules: "TERRAIN", d6: &hexTerrains},
+	{name: "Hex Contents", rules: "CONTENTS", d6: &hexContents},
+	{name: "Hex Features", rules: "FEATURES", d6: &hexFeatures},
+	{name: "Hex Events", rules: "EVENT", d6: &hexEvents},
+	{name: "Colors", list: &colors},
+	{name: "Weather Conditions", list: &conditions},
+	{name: "Temperatures", d6: &temps},
+	{name: "Winds", d6: &winds},
+}
+
+func init() {
+	for _, t := range builtinTables {
+		t.compiled = t.rows()
+		t.orig = t.compiled
+	}
+}
+
+var cardOrder = []Rank{
+	RankTwo, RankThree, RankFour, RankFive, RankSix, RankSeven, RankEight,
+	RankNine, RankTen, RankJack, RankQueen, RankKing, RankAce,
+}
+
+func (t *builtinTable) slug() string {
+	return strings.ToLower(strings.Join(strings.Fields(t.name), "-"))
+}
+
+// rows returns the table's current rows in roll order.
+func (t *builtinTable) rows() []string {
+	switch {
+	case t.d6 != nil:
+		return append([]string(nil), t.d6[1:]...)
+	case t.cards != nil:
+		rows := make([]string, len(cardOrder))
+		for i, r := range cardOrder {
+			rows[i] = t.cards[r]
+		}
+		return rows
+	default:
+		return append([]string(nil), *t.list...)
+	}
+}
+
+func (t *builtinTable) rolls(n int) []string {
+	rolls := make([]string, n)
+	for i := range rolls {
+		if t.cards != nil {
+			rolls[i] = cardOrder[i].String()
+		} else {
+			rolls[i] = strconv.Itoa(i + 1)
+		}
+	}
+	return rolls
+}
+
+// set replaces the table's rows, which must be the right number for it.
+func (t *builtinTable) set(rows []string) error {
+	switch {
+	case t.d6 != nil:
+		if len(rows) != 6 {
+			return fmt.Errorf("%s: want 6 rows (d6 1-6), got %d", t.slug(), len(rows))
+		}
+		copy(t.d6[1:], rows)
+	case t.cards != nil:
+		if len(rows) != len(cardOrder) {
+			return fmt.Errorf("%s: want 13 rows (ranks 2 to A), got %d", t.slug(), len(rows))
+		}
+		for i, r := range cardOrder {
+			t.cards[r] = rows[i]
+		}
+	default:
+		if len(rows) == 0 {
+			return fmt.Errorf("%s: want at least one row", t.slug())
+		}
+		*t.list = append([]string(nil), rows...)
+	}
+	return nil
+}
+
+// TableOverride is a built-in table as replaced by house rules.
+type TableOverride struct {
+	Name  string
+	Rolls []string // the roll or card rank for each row
+	Rows  []string
+}
+
+// SetOverrides restores every built-in table, then replaces those named in
+// overrides, keyed by table name or its hyphenated form, e.g.
+// "pacing-moves". Unknown tables and rows of the wrong length are reported
+// together; the valid overrides still apply.
+func SetOverrides(overrides map[string][]string) error {
+	for _, t := range builtinTables {
+		t.set(t.orig)
+	}
+	var errs []error
+	for key, rows := range overrides {
+		t := findBuiltinTable(key)
+		if t == nil {
+			errs = append(errs, fmt.Errorf("%s: no built-in table by that name", key))
+			continue
+		}
+		if err := t.set(rows); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func findBuiltinTable(key string) *builtinTable {
+	for _, t := range builtinTables {
+		if strings.EqualFold(t.name, key) || strings.EqualFold(t.slug(), key) {
+			return t
+		}
+	}
+	return nil
+}
+
+// Overrides lists the built-in tables that differ from OPSE, in table
+// order.
+func Overrides() []TableOverride {
+	var out []TableOverride
+	for _, t := range builtinTables {
+		rows := t.rows()
+		if !slices.Equal(rows, t.orig) {
+			out = append(out, TableOverride{Name: t.name, Rolls: t.rolls(len(rows)), Rows: rows})
+		}
+	}
+	return out
+}
+
+// BuiltinTableNames lists the built-in tables that can be overridden.
+func BuiltinTableNames() []string {
+	names := make([]string, len(builtinTables))
+	for i, t := range builtinTables {
+		names[i] = t.slug()
+	}
+	return names
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetOverrides(t *testing.T) {
+	defer SetOverrides(nil)
+	moves := []string{"Hurt", "Hurt", "Hurt", "Hurt", "Hurt", "Hurt"}
+	focus := strings.Fields("a b c d e f g h i j k l Ace")
+	err := SetOverrides(map[string][]string{
+		"failure-moves": moves,
+		"Action Focus":  focus,
+		"pacing-moves":  {"too", "short"},
+		"nonsense":      {"x"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "want 6 rows") || !strings.Contains(err.Error(), "nonsense") {
+		t.Errorf("err = %v", err)
+	}
+
+	rng := NewSeededRandomizer(130, 0)
+	if r := FailureMove(rng); r.Result != "Hurt" {
+		t.Errorf("failure move = %q, want the house rule", r.Result)
+	}
+	deck := RestoreDeck(rng, []Card{{Rank: RankAce, Suit: Spades}})
+	if r := ActionFocus(deck); r.Entry != "Ace" {
+		t.Errorf("action focus = %q, want the house rule", r.Entry)
+	}
+	if PacingMove(rng, NewDeck(rng)).Result == "too" {
+		t.Error("an invalid override should not apply")
+	}
+
+	got := Overrides()
+	if len(got) != 2 || got[0].Name != "Action Focus" || got[1].Name != "Failure Moves" {
+		t.Fatalf("Overrides() = %+v", got)
+	}
+	if got[0].Rolls[12] != "A" || got[1].Rolls[0] != "1" {
+		t.Errorf("rolls = %v, %v", got[0].Rolls, got[1].Rolls)
+	}
+
+	SetOverrides(nil)
+	if len(Overrides()) != 0 || FailureMove(rng).Result == "Hurt" {
+		t.Error("SetOverrides(nil) should restore the OPSE tables")
+	}
+}
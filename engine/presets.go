@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Preset is a game system's mechanical toolkit: the check a bare /check
+// rolls, how its result is read, and the system's common rolls, shown
+// alongside the saved rolls.
+type Preset struct {
+	ID    string
+	Name  string
+	Check string // the expression /check rolls with no expression given
+	// Challenge, if set, is dice the check's total must beat one by one,
+	// e.g. "2d10"; the bands then read the number of dice beaten.
+	Challenge string
+	// BonusDice makes a bonus such as /check +1 add dice to the check's
+	// pool, as in dice pool systems, rather than to its total.
+	BonusDice bool
+	// ZeroPool is what a pool bonused down to no dice rolls instead, e.g.
+	// Forged in the Dark's two dice keeping the lower. Without it, such a
+	// pool is an error.
+	ZeroPool string
+	Bands    []CheckBand
+	Rolls    []SavedRoll
+}
+
+// WithBonus returns the preset's check with a bonus of n: n more (or
+// fewer) dice in the pool if the preset takes BonusDice, or n added to the
+// total if not.
+func (p Preset) WithBonus(n int) (string, error) {
+	if !p.BonusDice {
+		return fmt.Sprintf("%s%+d", p.Check, n), nil
+	}
+	count, rest, ok := strings.Cut(p.Check, "d")
+	dice, err := strconv.Atoi(count)
+	if !ok || err != nil {
+		return "", fmt.Errorf("%s: the check %q has no dice count to add to", p.Name, p.Check)
+	}
+	if dice += n; dice < 1 {
+		if p.ZeroPool == "" {
+			return "", fmt.Errorf("%s: a check needs at least one die", p.Name)
+		}
+		return p.ZeroPool, nil
+	}
+	return fmt.Sprintf("%dd%s", dice, rest), nil
+}
+
+// checkRolls are saved rolls that make a check of expr with each modifier.
+func checkRolls(id, expr string, mods ...int) []SavedRoll {
+	rolls := make([]SavedRoll, len(mods))
+	for i, mod := range mods {
+		e := expr
+		if mod != 0 {
+			e += fmt.Sprintf("%+d", mod)
+		}
+		rolls[i] = SavedRoll{ID: fmt.Sprintf("%s_%d", id, i+1), Name: fmt.Sprintf("Check %+d", mod), Expression: e, Check: true}
+	}
+	return rolls
+}
+
+// Presets are the built-in game systems.
+var Presets = []Preset{
+	{ID: "osr", Name: "d20 OSR", Check: "1d20",
+		Bands: []CheckBand{{Label: "Failure", Failure: true}, {Min: 11, Label: "Success"}},
+		Rolls: []SavedRoll{
+			{ID: "osr_1", Name: "Attack", Expression: "1d20"},
+			{ID: "osr_2", Name: "Damage", Expression: "1d6"},
+			{ID: "osr_3", Name: "Ability Score", Expression: "3d6"},
+			{ID: "osr_4", Name: "Reaction", Expression: "2d6"},
+			{ID: "osr_5", Name: "Morale", Expression: "2d6"},
+			{ID: "osr_6", Name: "Surprise", Expression: "1d6"},
+		}},
+	{ID: "pbta", Name: "Powered by the Apocalypse", Check: "2d6",
+		Bands: DefaultCheckBands(),
+		Rolls: checkRolls("pbta", "2d6", -1, 0, 1, 2, 3)},
+	{ID: "year-zero", Name: "Year Zero Engine", Check: "3d6>=6", BonusDice: true,
+		Bands: []CheckBand{{Label: "Failure", Failure: true}, {Min: 1, Label: "Success"}, {Min: 2, Label: "Success with Stunts"}},
+		Rolls: []SavedRoll{
+			{ID: "year-zero_1", Name: "2 Dice", Expression: "2d6>=6", Check: true},
+			{ID: "year-zero_2", Name: "4 Dice", Expression: "4d6>=6", Check: true},
+			{ID: "year-zero_3", Name: "6 Dice", Expression: "6d6>=6", Check: true},
+			{ID: "year-zero_4", Name: "8 Dice", Expression: "8d6>=6", Check: true},
+		}},
+	{ID: "ironsworn", Name: "Ironsworn-style Action Roll", Check: "1d6", Challenge: "2d10",
+		Bands: []CheckBand{{Label: "Miss", Failure: true}, {Min: 1, Label: "Weak Hit"}, {Min: 2, Label: "Strong Hit"}},
+		Rolls: append(checkRolls("ironsworn", "1d6", 1, 2, 3),
+			SavedRoll{ID: "ironsworn_4", Name: "Oracle", Expression: "1d100"}),
+	},
+	{ID: "fitd", Name: "Forged in the Dark", Check: "2d6kh1", BonusDice: true, ZeroPool: "2d6kl1",
+		Bands: []CheckBand{{Label: "Failure", Failure: true}, {Min: 4, Label: "Partial Success"}, {Min: 6, Label: "Full Success"}},
+		Rolls: []SavedRoll{
+			{ID: "fitd_1", Name: "0 Dice", Expression: "2d6kl1", Check: true},
+			{ID: "fitd_2", Name: "1 Die", Expression: "1d6", Check: true},
+			{ID: "fitd_3", Name: "2 Dice", Expression: "2d6kh1", Check: true},
+			{ID: "fitd_4", Name: "3 Dice", Expression: "3d6kh1", Check: true},
+			{ID: "fitd_5", Name: "4 Dice", Expression: "4d6kh1", Check: true},
+		}},
+}
+
+// FindPreset looks a preset up by ID or name, ignoring case.
+func FindPreset(name string) (Preset, error) {
+	for _, p := range Presets {
+		if strings.EqualFold(p.ID, name) || strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	ids := make([]string, len(Presets))
+	for i, p := range Presets {
+		ids[i] = p.ID
+	}
+	return Preset{}, fmt.Errorf("unknown system %q (%s)", name, strings.Join(ids, ", "))
+}
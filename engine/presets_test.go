@@ -0,0 +1,68 @@
+package engine
+
+import "testing"
+
+func TestPresetsAreValid(t *testing.T) {
+	for _, p := range Presets {
+		cfg := &SessionConfig{System: p.ID, CheckBands: p.Bands}
+		if err := cfg.validate(); err != nil {
+			t.Errorf("%s: %v", p.ID, err)
+		}
+		for _, e := range append([]string{p.Check, p.Challenge}, rollExpressions(p.Rolls)...) {
+			if _, err := ParseDice(e); e != "" && err != nil {
+				t.Errorf("%s: %v", p.ID, err)
+			}
+		}
+		if found, err := FindPreset(p.Name); err != nil || found.ID != p.ID {
+			t.Errorf("FindPreset(%q) = %s, %v", p.Name, found.ID, err)
+		}
+	}
+	if _, err := FindPreset("gurps"); err == nil {
+		t.Error("an unknown system should be an error")
+	}
+}
+
+func rollExpressions(rolls []SavedRoll) []string {
+	out := make([]string, len(rolls))
+	for i, r := range rolls {
+		out[i] = r.Expression
+	}
+	return out
+}
+
+func TestCheckChallengeDice(t *testing.T) {
+	cfg := &SessionConfig{System: "ironsworn"}
+	s := &Session{Rng: NewSeededRandomizer(18, 0), Config: cfg}
+	for range 100 {
+		res, err := check(s, []string{"+2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := res.(CheckResult)
+		if r.Roll.Expression.Raw != "1d6+2" || r.Challenge == nil {
+			t.Fatalf("check = %+v, want 1d6+2 against challenge dice", r)
+		}
+		beaten := r.Challenge.beatenBy(r.Roll.Total)
+		want := []string{"Miss", "Weak Hit", "Strong Hit"}[beaten]
+		if r.Outcome.Label != want {
+			t.Fatalf("%d vs %v: %q, want %q", r.Roll.Total, r.Challenge.Terms[0].Rolls, r.Outcome.Label, want)
+		}
+	}
+}
+
+func TestCheckUsesSystem(t *testing.T) {
+	s := &Session{Rng: NewSeededRandomizer(1, 0)}
+	res, _ := check(s, nil)
+	if r := res.(CheckResult); r.Roll.Expression.Raw != "2d6" {
+		t.Errorf("bare /check with no system rolled %q", r.Roll.Expression.Raw)
+	}
+	s.Config = &SessionConfig{System: "fitd"}
+	res, _ = check(s, []string{"vs", "4"})
+	r := res.(CheckResult)
+	if r.Roll.Expression.Raw != "2d6kh1" || !r.HasTarget || r.Target != 4 {
+		t.Errorf("/check vs 4 = %+v", r)
+	}
+	if bands := s.Config.Bands(); bands[1].Label != "Partial Success" {
+		t.Errorf("bands = %+v, want Forged in the Dark's", bands)
+	}
+}
@@ -1,6 +1,7 @@
 package engine
 
 func RandomEvent(deck *Deck, rng *Randomizer) RandomEventResult {
+	rng.beginTrace("Random Event")
 	eventFn := func() *RandomEventResult {
 		evt := RandomEvent(deck, rng)
 		return &evt
@@ -20,5 +21,5 @@ func RandomEvent(deck *Deck, rng *Randomizer) RandomEventResult {
 		Entry:     topicFocusTable[topicDraw.Card.Rank],
 	}
 
-	return RandomEventResult{Action: action, Topic: topic}
+	return RandomEventResult{Action: action, Topic: topic, Trace: rng.endTrace()}
 }
@@ -0,0 +1,498 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Session is the engine state a generator draws on.
+type Session struct {
+	Rng     *Randomizer
+	Deck    *Deck
+	Utility *UtilityDeck
+	Config  *SessionConfig
+
+	// Sheets are the journal's character sheets, read by stat references
+	// in dice expressions; Character is the one @stat means.
+	Sheets    []CharacterSheet
+	Character string
+
+	// Threads are the open plot threads, one of which is named by each
+	// result that points at a plot arc.
+	Threads []string
+	// NPCs are the names on the roster, one of which is named by each
+	// result that calls on an NPC.
+	NPCs []string
+	// Factions are the active factions, one of which is named by each
+	// result that points at a faction.
+	Factions []string
+}
+
+// Stats resolves stat references against the session's sheets.
+func (s *Session) Stats() StatResolver { return Stats(s.Sheets, s.Character) }
+
+// Journal entry types a generator's results are filed under. They match
+// journal.EntryType.
+const (
+	EntryOracle    = "oracle"
+	EntryScene     = "scene"
+	EntryTool      = "tool"
+	EntryGenerator = "generator"
+)
+
+// Generator describes one OPSE table or tool. The sidebar, shortcuts,
+// slash commands, autocomplete, help and journal loader are all built from
+// Generators, so a new one shows up everywhere at once.
+type Generator struct {
+	ID       string   // action ID, e.g. "oracle_likely"
+	Name     string   // journal label, and the bold heading of its markdown
+	Label    string   // sidebar label
+	Category string   // sidebar heading
+	Key      string   // shortcut key, or ""
+	Command  string   // slash command, or ""
+	Sub      string   // word after Command when several generators share it
+	Aliases  []string // other slash commands for the same generator
+	Args     string   // argument synopsis, e.g. "[N]"
+	Repeat   bool     // a trailing count runs it that many times
+	Help     string   // help text; lines after the first continue it
+	Entry    string   // journal entry type: EntryOracle, EntryScene, ...
+	Run      func(s *Session, args []string) (any, error)
+}
+
+// Commands returns the generator's slash command followed by its aliases.
+func (g Generator) Commands() []string {
+	if g.Command == "" {
+		return nil
+	}
+	return append([]string{g.Command}, g.Aliases...)
+}
+
+// Usage is the slash command synopsis, e.g. "/dungeon room [N]".
+func (g Generator) Usage() string {
+	u := "/" + g.Command
+	if g.Sub != "" {
+		u += " " + g.Sub
+	}
+	if g.Args != "" {
+		u += " " + g.Args
+	}
+	if g.Repeat {
+		u += " [N]"
+	}
+	return u
+}
+
+// NeedsArgs reports whether the generator can't run without arguments:
+// whether any of Args is outside square brackets, as in "[vs N]".
+func (g Generator) NeedsArgs() bool {
+	depth := 0
+	for _, r := range g.Args {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0 && r != ' ':
+			return true
+		}
+	}
+	return false
+}
+
+// MaxRepeat caps the count accepted by a repeatable generator.
+const MaxRepeat = 20
+
+// SplitRepeat takes a trailing count off args for a repeatable generator.
+// It returns 1 and args unchanged when there is none.
+func (g Generator) SplitRepeat(args []string) (int, []string) {
+	if !g.Repeat || len(args) == 0 {
+		return 1, args
+	}
+	last := args[len(args)-1]
+	n, err := strconv.Atoi(last)
+	if err != nil || n < 1 || strings.HasPrefix(last, "+") {
+		return 1, args
+	}
+	return min(n, MaxRepeat), args[:len(args)-1]
+}
+
+// intArg reads args[0] as an int, or returns def.
+func intArg(args []string, def int) int {
+	if len(args) > 0 {
+		fmt.Sscanf(args[0], "%d", &def)
+	}
+	return def
+}
+
+// parseBonus reads a signed modifier such as "+1" or "-2".
+func parseBonus(word string) (int, bool) {
+	if !strings.HasPrefix(word, "+") && !strings.HasPrefix(word, "-") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(word)
+	return n, err == nil
+}
+
+// matchLikelihood finds the likelihood named by the leading words of args,
+// e.g. "very likely", and returns how many words it used.
+func matchLikelihood(ladder []Likelihood, args []string) (Likelihood, int) {
+	for n := min(len(args), 3); n > 0; n-- {
+		if l, err := FindLikelihood(ladder, strings.Join(args[:n], " ")); err == nil {
+			return l, n
+		}
+	}
+	return Likelihood{}, 0
+}
+
+// evenOdds is the rung /ask uses when none is given: Even, or the middle
+// of a ladder without it.
+func evenOdds(ladder []Likelihood) Likelihood {
+	if l, err := FindLikelihood(ladder, "Even"); err == nil {
+		return l
+	}
+	return ladder[len(ladder)/2]
+}
+
+// ask answers "[LIKELIHOOD] [MOD] [--] QUESTION" with the yes/no oracle, or
+// the How oracle for "how". Without a likelihood the odds are even, but
+// the question must then follow a modifier or "--", so that a misspelt
+// likelihood is an error rather than the start of the question.
+func ask(s *Session, args []string) (any, error) {
+	if len(args) > 0 && strings.EqualFold(args[0], "how") {
+		question := strings.Join(args[1:], " ")
+		if question == "" {
+			return nil, fmt.Errorf("usage: /ask how QUESTION")
+		}
+		r := OracleHow(s.Rng)
+		r.Question = question
+		return r, nil
+	}
+	ladder := s.Config.Ladder()
+	l, n := matchLikelihood(ladder, args)
+	if n == 0 {
+		l = evenOdds(ladder)
+	}
+	args = args[n:]
+	var bonus int
+	hasBonus := false
+	if len(args) > 0 && args[0] != "--" {
+		bonus, hasBonus = parseBonus(args[0])
+		if hasBonus {
+			args = args[1:]
+		}
+	}
+	switch {
+	case len(args) > 0 && args[0] == "--":
+		args = args[1:]
+	case n == 0 && !hasBonus && len(args) > 0:
+		return nil, fmt.Errorf("unknown likelihood %q (use /ask -- QUESTION for even odds)", args[0])
+	}
+	question := strings.Join(args, " ")
+	if question == "" {
+		return nil, fmt.Errorf("usage: /ask [LIKELIHOOD|how] [MOD] [--] QUESTION")
+	}
+	r := OracleYesNo(s.Rng, l, bonus)
+	r.Question = question
+	return r, nil
+}
+
+// OracleCommand is the slash command shared by the yes/no oracles, one
+// subcommand per likelihood.
+const OracleCommand = "oracle"
+
+// oracleGenerators builds a yes/no oracle generator for each rung of the
+// ladder, sharing the /oracle command.
+func oracleGenerators(ladder []Likelihood) []Generator {
+	gens := make([]Generator, len(ladder))
+	for i, l := range ladder {
+		gens[i] = Generator{
+			ID:    "oracle_" + strings.ReplaceAll(l.Slug(), "-", "_"),
+			Name:  "Oracle (Yes/No, " + l.Name + ")",
+			Label: l.Name, Category: "ORACLE", Key: l.Key,
+			Command: OracleCommand, Sub: l.Slug(), Args: "[MOD]", Repeat: true, Entry: EntryOracle,
+			Help: fmt.Sprintf("Yes on %s, plus a but.../and... modifier. "+
+				"MOD, e.g. +1, is added to the roll.", l.Needs()),
+			Run: func(s *Session, args []string) (any, error) {
+				var bonus int
+				if len(args) > 0 {
+					b, ok := parseBonus(args[0])
+					if !ok {
+						return nil, fmt.Errorf("usage: /oracle %s [+N|-N] [N]", l.Slug())
+					}
+					bonus = b
+				}
+				return OracleYesNo(s.Rng, l, bonus), nil
+			},
+		}
+	}
+	return gens
+}
+
+// TableCommand is the slash command shared by user-defined tables, one
+// subcommand per table.
+const TableCommand = "table"
+
+// tableGenerators builds a generator for each user-defined table.
+func tableGenerators(tables []Table) []Generator {
+	gens := make([]Generator, len(tables))
+	for i, t := range tables {
+		help := t.Description
+		if help == "" {
+			help = fmt.Sprintf("Rolls %s on %s.", t.Roll, filepath.Base(t.Source))
+		}
+		gens[i] = Generator{
+			ID:   "table_" + strings.ReplaceAll(t.Slug(), "-", "_"),
+			Name: t.Name, Label: t.Name, Category: t.category(),
+			Command: TableCommand, Sub: t.Slug(), Repeat: true, Entry: EntryGenerator,
+			Help: help,
+			Run: func(s *Session, _ []string) (any, error) {
+				return RollTable(t, s.Deck, s.Rng), nil
+			},
+		}
+	}
+	return gens
+}
+
+var (
+	ladderGenerators = oracleGenerators(DefaultLikelihoods())
+	userGenerators   []Generator
+	generators       = slices.Concat(ladderGenerators, builtinGenerators)
+)
+
+// SetLikelihoods rebuilds the yes/no oracle generators from ladder. The app
+// calls it once the session config is loaded.
+func SetLikelihoods(ladder []Likelihood) {
+	ladderGenerators = oracleGenerators(ladder)
+	generators = slices.Concat(ladderGenerators, builtinGenerators, userGenerators)
+}
+
+// SetTables adds a generator for each user-defined table, after the
+// built-in ones, replacing any tables set before.
+func SetTables(tables []Table) {
+	userGenerators = tableGenerators(tables)
+	generators = slices.Concat(ladderGenerators, builtinGenerators, userGenerators)
+}
+
+var builtinGenerators = []Generator{
+	{ID: "oracle_how", Name: "Oracle (How)", Label: "How",
+		Category: "ORACLE", Key: "4", Command: "how", Repeat: true, Entry: EntryOracle,
+		Help: "How much, how strong, how many (d6).",
+		Run:  func(s *Session, _ []string) (any, error) { return OracleHow(s.Rng), nil }},
+
+	{ID: "ask", Name: "Oracle (Ask)", Label: "Ask a Question",
+		Category: "ORACLE", Command: "ask", Args: "[LIKELIHOOD] [MOD] [--] QUESTION", Entry: EntryOracle,
+		Help: "Ask the oracle and record the question with the answer. " +
+			"LIKELIHOOD is any rung of the ladder, or how for the How oracle; " +
+			"MOD, e.g. +1, is added to the roll. For even odds, start the question with --.\n" +
+			"Example: /ask very likely -1 Is the door locked?",
+		Run: ask},
+
+	{ID: "focus_action", Name: "Action Focus", Label: "Action",
+		Category: "FOCUS", Key: "5", Command: "action", Repeat: true, Entry: EntryGenerator,
+		Help: "What does it do? (card)",
+		Run:  func(s *Session, _ []string) (any, error) { return ActionFocus(s.Deck), nil }},
+	{ID: "focus_detail", Name: "Detail Focus", Label: "Detail",
+		Category: "FOCUS", Key: "6", Command: "detail", Repeat: true, Entry: EntryGenerator,
+		Help: "What kind of thing is it? (card)",
+		Run:  func(s *Session, _ []string) (any, error) { return DetailFocus(s.Deck), nil }},
+	{ID: "focus_topic", Name: "Topic Focus", Label: "Topic",
+		Category: "FOCUS", Key: "7", Command: "topic", Repeat: true, Entry: EntryGenerator,
+		Help: "What is this about? (card)",
+		Run:  func(s *Session, _ []string) (any, error) { return TopicFocus(s.Deck), nil }},
+
+	{ID: "set_scene", Name: "Set the Scene", Label: "Set the Scene",
+		Category: "SCENE", Key: "8", Command: "scene", Args: "[TITLE] [goal: GOAL]", Entry: EntryScene,
+		Help: "Opens the next numbered scene: a complication, and maybe an altered scene.",
+		Run:  setScene},
+	{ID: "random_event", Name: "Random Event", Label: "Random Event",
+		Category: "SCENE", Key: "9", Command: "event", Repeat: true, Entry: EntryGenerator,
+		Help: "What happens, involving what (two cards).",
+		Run:  func(s *Session, _ []string) (any, error) { return RandomEvent(s.Deck, s.Rng), nil }},
+
+	{ID: "pacing_move", Name: "Pacing Move", Label: "Pacing Move",
+		Category: "GM MOVES", Key: "0", Command: "pacing", Repeat: true, Entry: EntryGenerator,
+		Help: "When there's a lull, or \"what now?\" (d6).",
+		Run:  func(s *Session, _ []string) (any, error) { return PacingMove(s.Rng, s.Deck), nil }},
+	{ID: "failure_move", Name: "Failure Move", Label: "Failure Move",
+		Category: "GM MOVES", Key: "-", Command: "failure", Repeat: true, Entry: EntryGenerator,
+		Help: "Consequences when the PCs fail (d6).",
+		Run:  func(s *Session, _ []string) (any, error) { return FailureMove(s.Rng), nil }},
+	{ID: "check", Name: "Check", Label: "Check",
+		Category: "GM MOVES", Command: "check", Args: "[EXPR|MOD] [vs N]", Entry: EntryTool,
+		Help: "Roll a check and read it against N, or without vs against the outcome bands\n" +
+			"(PbtA by default: 6- Miss, 7-9 Weak Hit, 10+ Strong Hit). A failure rolls a Failure Move.\n" +
+			"With no EXPR, or just a modifier, rolls the system's check (2d6 with no /system).\n" +
+			"Examples: /check 2d6+2 vs 10, /check +1, /check vs 8",
+		Run: check},
+
+	{ID: "generic", Name: "Generic Generator", Label: "Generic",
+		Category: "GENERATORS", Key: "=", Command: "generic", Repeat: true, Entry: EntryGenerator,
+		Help: "Towns, factions, items, monsters, anything.",
+		Run:  func(s *Session, _ []string) (any, error) { return GenericGenerator(s.Deck, s.Rng), nil }},
+	{ID: "plot_hook", Name: "Plot Hook", Label: "Plot Hook",
+		Category: "GENERATORS", Command: "hook", Repeat: true, Entry: EntryGenerator,
+		Help: "Objective, adversary and reward (3d6).",
+		Run:  func(s *Session, _ []string) (any, error) { return PlotHook(s.Rng), nil }},
+	{ID: "npc", Name: "NPC", Label: "NPC",
+		Category: "GENERATORS", Command: "npc", Repeat: true, Entry: EntryGenerator,
+		Help: "Identity, goal, feature, attitude and topic.",
+		Run:  func(s *Session, _ []string) (any, error) { return NPCGenerator(s.Deck, s.Rng), nil }},
+	{ID: "dungeon_theme", Name: "Dungeon Theme", Label: "Dungeon Theme",
+		Category: "GENERATORS", Command: "dungeon", Sub: "theme", Repeat: true, Entry: EntryGenerator,
+		Help: "How a dungeon looks and how it's used (cards).",
+		Run:  func(s *Session, _ []string) (any, error) { return DungeonTheme(s.Deck), nil }},
+	{ID: "dungeon_room", Name: "Dungeon Room", Label: "Dungeon Room",
+		Category: "GENERATORS", Command: "dungeon", Sub: "room", Repeat: true, Entry: EntryGenerator,
+		Help: "Location, encounter, object and exits (4d6).",
+		Run:  func(s *Session, _ []string) (any, error) { return DungeonRoom(s.Rng), nil }},
+	{ID: "hex", Name: "Hex", Label: "Hex",
+		Category: "GENERATORS", Command: "hex", Repeat: true, Entry: EntryGenerator,
+		Help: "Terrain, contents, feature and event (d6s).",
+		Run:  func(s *Session, _ []string) (any, error) { return HexCrawl(s.Rng, s.Deck), nil }},
+
+	{ID: "dice_roller", Name: "Dice", Label: "Dice Roller",
+		Category: "TOOLS", Key: "/", Command: "roll", Aliases: []string{"r"}, Args: "EXPR",
+		Entry: EntryTool,
+		Help: "Roll dice: NdS for N dice with S sides, d% for d100, dF for Fudge dice.\n" +
+			"Add ! to explode, khN/klN to keep and dhN/dlN to drop the highest or lowest N.\n" +
+			"Combine with + - * / and parentheses.\n" +
+			"Pools: >=N counts successes, fN failures; rN rerolls once, rrN until it misses;\n" +
+			"!>=N explodes on N+, !! compounds, !p penetrates.\n" +
+			"@stat adds a stat from the current /char's sheet; @name.stat or @\"Full Name\".stat another's.\n" +
+			"Examples: /roll 2d6+1d4+3, /roll 4d6dl1, /roll (1d8+2)*2, /r 4dF, /r 6d10>=7f1, /r d20+@str",
+		Run: func(s *Session, args []string) (any, error) {
+			if len(args) == 0 {
+				return nil, fmt.Errorf("usage: /roll EXPR")
+			}
+			expr, err := ParseDiceWith(strings.Join(args, " "), s.Stats())
+			if err != nil {
+				return nil, err
+			}
+			return RollDice(s.Rng, expr), nil
+		}},
+	{ID: "coin_flip", Name: "Coin Flip", Label: "Coin Flip",
+		Category: "TOOLS", Command: "flip", Aliases: []string{"f"}, Args: "[N]",
+		Entry: EntryTool,
+		Help:  "Flip N coins (default 1).",
+		Run:   func(s *Session, args []string) (any, error) { return FlipCoins(s.Rng, intArg(args, 1)), nil }},
+	{ID: "card_draw", Name: "Card Draw", Label: "Card Draw",
+		Category: "TOOLS", Command: "draw", Aliases: []string{"card"}, Args: "[N]",
+		Entry: EntryTool,
+		Help:  "Draw N cards from the utility deck (default 1).",
+		Run:   func(s *Session, args []string) (any, error) { return s.Utility.Draw(intArg(args, 1)), nil }},
+	{ID: "direction", Name: "Direction", Label: "Direction",
+		Category: "TOOLS", Command: "dir", Aliases: []string{"direction"}, Args: "[N]",
+		Entry: EntryTool,
+		Help:  "Random N-point compass direction (4, 8 or 16).",
+		Run: func(s *Session, args []string) (any, error) {
+			return RandomDirection(s.Rng, intArg(args, 8)), nil
+		}},
+	{ID: "weather", Name: "Weather", Label: "Weather",
+		Category: "TOOLS", Command: "weather", Aliases: []string{"w"}, Repeat: true, Entry: EntryTool,
+		Help: "Random weather (condition, temperature, wind).",
+		Run:  func(s *Session, _ []string) (any, error) { return RandomWeather(s.Rng), nil }},
+	{ID: "color", Name: "Color", Label: "Color",
+		Category: "TOOLS", Command: "color", Repeat: true, Entry: EntryTool,
+		Help: "Random color.",
+		Run:  func(s *Session, _ []string) (any, error) { return RandomColor(s.Rng), nil }},
+	{ID: "sound", Name: "Sound", Label: "Sound",
+		Category: "TOOLS", Command: "sound", Args: "[CATEGORY]", Repeat: true, Entry: EntryTool,
+		Help: "Random sound effect, optionally from one category:\n" +
+			strings.Join(SoundCategoryNames(), ", "),
+		Run: func(s *Session, args []string) (any, error) {
+			cat := ""
+			if len(args) > 0 {
+				cat = args[0]
+			}
+			return RandomSound(s.Rng, cat), nil
+		}},
+}
+
+// Generators returns every registered generator, in sidebar order.
+func Generators() []Generator { return generators }
+
+// LookupGenerator finds a generator by ID.
+func LookupGenerator(id string) (Generator, bool) {
+	for _, g := range generators {
+		if g.ID == id {
+			return g, true
+		}
+	}
+	return Generator{}, false
+}
+
+// LookupCommand finds the generator behind a slash command or alias and
+// returns the arguments left for it. A shared command is resolved by its
+// first argument, e.g. "/dungeon room".
+func LookupCommand(cmd string, args []string) (Generator, []string, error) {
+	var subs []string
+	for _, g := range generators {
+		if !slices.Contains(g.Commands(), cmd) {
+			continue
+		}
+		if g.Sub == "" {
+			return g, args, nil
+		}
+		if len(args) > 0 && strings.EqualFold(args[0], g.Sub) {
+			return g, args[1:], nil
+		}
+		subs = append(subs, g.Sub)
+	}
+	if len(subs) == 0 {
+		return Generator{}, nil, fmt.Errorf("unknown command /%s", cmd)
+	}
+	return Generator{}, nil, fmt.Errorf("usage: /%s %s", cmd, strings.Join(subs, "|"))
+}
+
+// Renderers turn generator results into journal markdown or TUI text.
+// They live with the package that owns the output format and register
+// themselves by result type.
+var (
+	markdownRenderers = map[reflect.Type]func(any) string{}
+	tuiRenderers      = map[reflect.Type]func(any) string{}
+)
+
+func RegisterMarkdown[T any](fn func(T) string) {
+	markdownRenderers[reflect.TypeFor[T]()] = func(v any) string { return fn(v.(T)) }
+}
+
+func RegisterTUI[T any](fn func(T) string) {
+	tuiRenderers[reflect.TypeFor[T]()] = func(v any) string { return fn(v.(T)) }
+}
+
+// RenderMarkdown renders a generator result with its registered markdown
+// renderer. It reports false for a result type with no renderer.
+func RenderMarkdown(result any) (string, bool) {
+	fn, ok := markdownRenderers[reflect.TypeOf(result)]
+	if !ok {
+		return "", false
+	}
+	return fn(result), true
+}
+
+// RenderTUI is RenderMarkdown for the terminal view.
+func RenderTUI(result any) (string, bool) {
+	fn, ok := tuiRenderers[reflect.TypeOf(result)]
+	if !ok {
+		return "", false
+	}
+	return fn(result), true
+}
+
+// TraceOf returns the Trace recorded on a generator result.
+func TraceOf(result any) Trace {
+	v := reflect.Indirect(reflect.ValueOf(result))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f := v.FieldByName("Trace")
+	if !f.IsValid() {
+		return nil
+	}
+	t, _ := f.Interface().(Trace)
+	return t
+}
@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+// sampleArgs are arguments for generators that require them.
+var sampleArgs = map[string][]string{
+	"dice_roller": {"2d6"},
+	"check":       {"2d6", "vs", "8"},
+	"ask":         {"likely", "Is", "the", "door", "locked?"},
+}
+
+func TestGeneratorsUnique(t *testing.T) {
+	ids := map[string]bool{}
+	keys := map[string]bool{}
+	commands := map[string]bool{}
+	for _, g := range Generators() {
+		if ids[g.ID] {
+			t.Errorf("duplicate ID %q", g.ID)
+		}
+		ids[g.ID] = true
+		if g.Key != "" {
+			if keys[g.Key] {
+				t.Errorf("duplicate key %q", g.Key)
+			}
+			keys[g.Key] = true
+		}
+		for _, c := range g.Commands() {
+			c += " " + g.Sub
+			if commands[c] {
+				t.Errorf("duplicate command %q", c)
+			}
+			commands[c] = true
+		}
+		if g.Name == "" || g.Label == "" || g.Category == "" || g.Entry == "" || g.Run == nil {
+			t.Errorf("%s: incomplete registration %+v", g.ID, g)
+		}
+	}
+}
+
+func TestGeneratorsRun(t *testing.T) {
+	rng := NewSeededRandomizer(100, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Utility: NewUtilityDeck(rng, false), Config: DefaultSessionConfig()}
+	for _, g := range Generators() {
+		res, err := g.Run(s, sampleArgs[g.ID])
+		if err != nil {
+			t.Errorf("%s: %v", g.ID, err)
+			continue
+		}
+		if len(TraceOf(res)) == 0 {
+			t.Errorf("%s: result has no trace", g.ID)
+		}
+	}
+}
+
+func TestLookupCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		id   string
+		rest int
+	}{
+		{"roll 2d6", "dice_roller", 1},
+		{"r 2d6", "dice_roller", 1},
+		{"scene", "set_scene", 0},
+		{"w", "weather", 0},
+		{"oracle likely", "oracle_likely", 0},
+		{"oracle Unlikely 3", "oracle_unlikely", 1},
+		{"oracle very-likely +1", "oracle_very_likely", 1},
+		{"dungeon room", "dungeon_room", 0},
+		{"npc 2", "npc", 1},
+	}
+	for _, tt := range tests {
+		f := strings.Fields(tt.line)
+		g, rest, err := LookupCommand(f[0], f[1:])
+		if err != nil || g.ID != tt.id || len(rest) != tt.rest {
+			t.Errorf("LookupCommand(%q) = %q, %v, %v; want %q", tt.line, g.ID, rest, err, tt.id)
+		}
+	}
+	if _, _, err := LookupCommand("oracle", []string{"sorta"}); err == nil {
+		t.Error("unknown likelihood should not resolve")
+	}
+	if _, _, err := LookupCommand("nope", nil); err == nil {
+		t.Error("unknown command should not resolve")
+	}
+	_, _, err := LookupCommand("dungeon", []string{"cave"})
+	if err == nil || !strings.Contains(err.Error(), "theme|room") {
+		t.Errorf("unknown subcommand error = %v", err)
+	}
+}
+
+func TestSplitRepeat(t *testing.T) {
+	npc, _ := LookupGenerator("npc")
+	if n, rest := npc.SplitRepeat([]string{"3"}); n != 3 || len(rest) != 0 {
+		t.Errorf("npc 3 = %d, %v", n, rest)
+	}
+	if n, _ := npc.SplitRepeat([]string{"500"}); n != MaxRepeat {
+		t.Errorf("repeat should be capped at %d, got %d", MaxRepeat, n)
+	}
+	sound, _ := LookupGenerator("sound")
+	if n, rest := sound.SplitRepeat([]string{"nature", "2"}); n != 2 || len(rest) != 1 {
+		t.Errorf("sound nature 2 = %d, %v", n, rest)
+	}
+	flip, _ := LookupGenerator("coin_flip")
+	if n, rest := flip.SplitRepeat([]string{"5"}); n != 1 || len(rest) != 1 {
+		t.Errorf("flip's count is its own argument, got %d, %v", n, rest)
+	}
+	likely, _ := LookupGenerator("oracle_likely")
+	if n, rest := likely.SplitRepeat([]string{"+2"}); n != 1 || len(rest) != 1 {
+		t.Errorf("a modifier is not a count, got %d, %v", n, rest)
+	}
+	if u := sound.Usage(); u != "/sound [CATEGORY] [N]" {
+		t.Errorf("Usage() = %q", u)
+	}
+}
+
+func TestNeedsArgs(t *testing.T) {
+	for id, want := range map[string]bool{"dice_roller": true, "ask": true, "check": false, "set_scene": false, "sound": false} {
+		g, _ := LookupGenerator(id)
+		if got := g.NeedsArgs(); got != want {
+			t.Errorf("%s (%q) NeedsArgs = %v, want %v", id, g.Args, got, want)
+		}
+	}
+}
+
+func TestAsk(t *testing.T) {
+	rng := NewSeededRandomizer(103, 0)
+	s := &Session{Rng: rng}
+	res, err := ask(s, []string{"LIKELY", "Is", "the", "door", "locked?"})
+	if r, ok := res.(OracleYesNoResult); err != nil || !ok || r.Likelihood != "Likely" || r.Question != "Is the door locked?" {
+		t.Errorf("ask likely = %+v, %v", res, err)
+	}
+	res, _ = ask(s, []string{"--", "Anyone", "home?"})
+	if r, ok := res.(OracleYesNoResult); !ok || r.Likelihood != "Even" || r.Question != "Anyone home?" {
+		t.Errorf("ask -- = %+v", res)
+	}
+	if _, err := ask(s, []string{"likley", "Anyone", "home?"}); err == nil || !strings.Contains(err.Error(), `"likley"`) {
+		t.Errorf("ask with an unknown likelihood = %v", err)
+	}
+	res, _ = ask(s, []string{"likely", "--", "How", "far?"})
+	if r, ok := res.(OracleYesNoResult); !ok || r.Likelihood != "Likely" || r.Question != "How far?" {
+		t.Errorf("ask likely -- = %+v", res)
+	}
+	res, _ = ask(s, []string{"how", "Strong?"})
+	if r, ok := res.(OracleHowResult); !ok || r.Question != "Strong?" {
+		t.Errorf("ask how = %+v", res)
+	}
+	if _, err := ask(s, []string{"likely"}); err == nil {
+		t.Error("ask without a question should fail")
+	}
+	res, _ = ask(s, []string{"very", "unlikely", "+2", "Rain?"})
+	if r, ok := res.(OracleYesNoResult); !ok || r.Likelihood != "Very Unlikely" || r.Bonus != 2 || r.Question != "Rain?" {
+		t.Errorf("ask very unlikely +2 = %+v", res)
+	}
+	res, _ = ask(s, []string{"-1", "Rain?"})
+	if r, ok := res.(OracleYesNoResult); !ok || r.Likelihood != "Even" || r.Bonus != -1 {
+		t.Errorf("ask -1 = %+v", res)
+	}
+}
+
+func TestSetLikelihoods(t *testing.T) {
+	defer SetLikelihoods(DefaultLikelihoods())
+	SetLikelihoods([]Likelihood{{Name: "Sure Thing", Threshold: 2, Key: "!"}, {Name: "Coin Toss", Threshold: 4}})
+	g, rest, err := LookupCommand("oracle", []string{"sure-thing", "-1"})
+	if err != nil || g.ID != "oracle_sure_thing" || g.Key != "!" || len(rest) != 1 {
+		t.Fatalf("LookupCommand(oracle sure-thing) = %+v, %v, %v", g, rest, err)
+	}
+	if _, ok := LookupGenerator("oracle_likely"); ok {
+		t.Error("the default ladder should be replaced")
+	}
+	if _, ok := LookupGenerator("oracle_how"); !ok {
+		t.Error("other generators should be kept")
+	}
+}
@@ -1,33 +1,45 @@
 package engine
 
 type OracleYesNoResult struct {
+	Question   string // what was asked, if anything
 	Likelihood string
+	Threshold  int
 	AnswerRoll int
+	Dropped    []int // the d6 rolled for the Likelihood's Edge and not kept
+	Bonus      int   // the question's modifier, added to AnswerRoll
 	Answer     bool
 	ModRoll    int
 	Modifier   string
+	Trace      Trace
 }
 
 type OracleHowResult struct {
-	Roll   int
-	Result string
+	Question string
+	Roll     int
+	Result   string `expand:"entry"`
+	Trace    Trace
 }
 
 type CardTableResult struct {
 	Draw      DrawResult
 	TableName string
-	Entry     string
+	Entry     string `expand:"entry"`
+	Trace     Trace
 }
 
 type SceneComplicationResult struct {
 	Roll   int
-	Result string
+	Result string `expand:"entry"`
+	Trace  Trace
 }
 
+// AlteredSceneResult sets at most one cascade, for rolls 4, 5 and 6.
 type AlteredSceneResult struct {
-	Roll    int
-	Result  string
-	Cascade interface{}
+	Roll         int
+	Result       string `expand:"entry"`
+	Complication *SceneComplicationResult
+	PacingMove   *PacingMoveResult
+	RandomEvent  *RandomEventResult
 }
 
 type SetTheSceneResult struct {
@@ -35,123 +47,195 @@ type SetTheSceneResult struct {
 	AlteredRoll  int
 	Altered      bool
 	AlteredScene *AlteredSceneResult
+	Title        string // optional, given with /scene
+	Goal         string // optional, given with /scene
+	Trace        Trace
 }
 
 type PacingMoveResult struct {
 	Roll        int
-	Result      string
+	Result      string `expand:"entry"`
 	RandomEvent *RandomEventResult
+	Trace       Trace
 }
 
 type FailureMoveResult struct {
 	Roll   int
-	Result string
+	Result string `expand:"entry"`
+	Trace  Trace
 }
 
 type RandomEventResult struct {
 	Action CardTableResult
 	Topic  CardTableResult
+	Trace  Trace
 }
 
 type GenericGeneratorResult struct {
 	Action       CardTableResult
 	Detail       CardTableResult
 	Significance OracleHowResult
+	Trace        Trace
 }
 
 type PlotHookResult struct {
 	ObjectiveRoll int
-	Objective     string
+	Objective     string `expand:"entry"`
 	AdversaryRoll int
-	Adversary     string
+	Adversary     string `expand:"entry"`
 	RewardRoll    int
-	Reward        string
+	Reward        string `expand:"entry"`
+	Trace         Trace
 }
 
 type NPCResult struct {
 	Identity      CardTableResult
 	Goal          CardTableResult
 	FeatureRoll   int
-	Feature       string
+	Feature       string `expand:"entry"`
 	FeatureDetail CardTableResult
 	Attitude      OracleHowResult
 	Topic         CardTableResult
+	Trace         Trace
 }
 
 type DungeonThemeResult struct {
 	Looks CardTableResult
 	Used  CardTableResult
+	Trace Trace
 }
 
 type DungeonRoomResult struct {
 	LocationRoll  int
-	Location      string
+	Location      string `expand:"entry"`
 	EncounterRoll int
-	Encounter     string
+	Encounter     string `expand:"entry"`
 	ObjectRoll    int
-	Object        string
+	Object        string `expand:"entry"`
 	ExitsRoll     int
-	Exits         string
+	Exits         string `expand:"entry"`
+	Trace         Trace
 }
 
 type HexResult struct {
 	TerrainRoll  int
-	Terrain      string
+	Terrain      string `expand:"entry"`
 	ContentsRoll int
-	Contents     string
+	Contents     string `expand:"entry"`
 	FeatureRoll  int
-	Feature      string
+	Feature      string `expand:"entry"`
 	EventRoll    int
-	Event        string
+	Event        string `expand:"entry"`
 	RandomEvent  *RandomEventResult
+	Trace        Trace
 }
 
+// DiceExpression is a parsed dice expression such as "2d6+1d4+3" or
+// "(1d8+2)*2".
 type DiceExpression struct {
-	Count    int
-	Sides    int
-	Explode  bool
-	KeepMode string
-	KeepN    int
-	Modifier int
-	Raw      string
-}
-
+	Root *DiceNode
+	Raw  string
+}
+
+// DiceNode is a node of a dice expression's tree: a dice term, a constant,
+// a negation of Left, or Left Op Right for Op one of + - * /.
+type DiceNode struct {
+	Dice  *DiceTerm
+	Value int
+	Ref   string // the stat reference a leaf's Value came from, e.g. "@str"
+	Op    byte   // '+', '-', '*', '/', or 'n' to negate Left; 0 for a leaf
+	Left  *DiceNode
+	Right *DiceNode
+}
+
+// DiceTerm is one group of dice, e.g. 4d6dl1, 2d20kh1, 3d6!, 4dF or the
+// pool 6d10>=7f1.
+type DiceTerm struct {
+	Count     int
+	Sides     int         // 100 for d%; unused for Fudge dice
+	Fudge     bool        // dF: each die is -1, 0 or +1
+	Explode   string      // "!" adds a die, "!!" compounds into one, "!p" penetrates (each extra die -1)
+	ExplodeOn DiceCompare // faces that explode; unset for the highest
+	Reroll    string      // "r" rerolls once, "rr" until the die misses RerollOn
+	RerollOn  DiceCompare
+	KeepMode  string // "kh", "kl", "dh" or "dl"
+	KeepN     int
+	Success   DiceCompare // when set, the term counts dice that match instead of summing
+	Failure   DiceCompare // dice that count as failures, e.g. botches
+}
+
+// DiceTermResult is the dice rolled for one term of an expression, with
+// rerolled dice left in place. Kept marks the dice that count toward
+// Total; for a pool, Success and Failure mark the kept dice that matched
+// and Total is the number of successes.
+type DiceTermResult struct {
+	Term      DiceTerm
+	Rolls     []int
+	Kept      []bool
+	Success   []bool
+	Failure   []bool
+	Successes int
+	Failures  int
+	Total     int
+}
+
+// DiceRollResult holds the rolls for each dice term, in the order they
+// appear in the expression.
+// For an expression with a pool, Total counts successes and Failures the
+// failures.
 type DiceRollResult struct {
 	Expression DiceExpression
-	Rolls      []int
-	Kept       []bool
-	Subtotal   int
+	Terms      []DiceTermResult
 	Total      int
+	Pool       bool
+	Failures   int
+	Trace      Trace
 }
 
 type CoinFlipResult struct {
 	Flips []bool
 	Heads int
 	Tails int
+	Trace Trace
 }
 
 type CardDrawResult struct {
 	Cards     []Card
 	Remaining int
+	Trace     Trace
 }
 
 type DirectionResult struct {
 	Direction string
 	Abbrev    string
 	Arrow     string
+	Trace     Trace
 }
 
 type WeatherResult struct {
-	Condition   string
-	Temperature string
-	Wind        string
+	Condition   string `expand:"entry"`
+	Temperature string `expand:"entry"`
+	Wind        string `expand:"entry"`
+	Trace       Trace
 }
 
 type ColorResult struct {
-	Color string
+	Color string `expand:"entry"`
+	Trace Trace
 }
 
 type SoundResult struct {
-	Sound    string
+	Sound    string `expand:"entry"`
 	Category string
+	Trace    Trace
+}
+
+type TableResult struct {
+	TableName string
+	Roll      string // the table's roll, e.g. "2d6" or "card"
+	Rolls     []int
+	Total     int // dice total, or the card's rank
+	Draw      *DrawResult
+	Entry     string `expand:"entry"`
+	Trace     Trace
 }
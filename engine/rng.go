@@ -1,22 +1,125 @@
 package engine
 
-import "math/rand/v2"
+import (
+	"math/rand/v2"
+	"slices"
+)
 
 type Randomizer struct {
-	rng *rand.Rand
+	src   *rand.PCG
+	rng   *rand.Rand
+	d6    D6Source
+	trace tracer
 }
 
+// D6Source supplies the d6 results used by the OPSE tables in place of the
+// pseudo-random generator, e.g. card draws under the "use only cards" rule.
+type D6Source interface {
+	RollD6() int
+}
+
+// DieSource is a D6Source that also supplies the other dice and coin
+// flips, e.g. the player's own under physical mode. It is asked for the
+// StandardDice only: a pick from a list of any other size, such as a
+// color, or Intn, stays pseudo-random.
+type DieSource interface {
+	D6Source
+	RollDN(n int) int
+	RollDF() int
+	CoinFlip() bool
+}
+
+// StandardDice are the sizes of die a DieSource rolls.
+var StandardDice = []int{4, 6, 8, 10, 12, 20, 100}
+
 func NewRandomizer() *Randomizer {
-	return &Randomizer{rng: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))}
+	return newRandomizer(rand.NewPCG(rand.Uint64(), rand.Uint64()))
 }
 
 func NewSeededRandomizer(seed1, seed2 uint64) *Randomizer {
-	return &Randomizer{rng: rand.New(rand.NewPCG(seed1, seed2))}
+	return newRandomizer(rand.NewPCG(seed1, seed2))
+}
+
+func newRandomizer(src *rand.PCG) *Randomizer {
+	return &Randomizer{src: src, rng: rand.New(src)}
+}
+
+// SetD6Source routes RollD6 through src, and the other dice and coin
+// flips too if it is a DieSource. A nil src restores dice rolls.
+func (r *Randomizer) SetD6Source(src D6Source) { r.d6 = src }
+
+// MarshalBinary returns the PCG seed/state so the sequence can be resumed.
+func (r *Randomizer) MarshalBinary() ([]byte, error) { return r.src.MarshalBinary() }
+
+// UnmarshalBinary restores a PCG state previously produced by MarshalBinary.
+func (r *Randomizer) UnmarshalBinary(data []byte) error {
+	return r.src.UnmarshalBinary(data)
 }
 
-func (r *Randomizer) Intn(n int) int    { return r.rng.IntN(n) }
-func (r *Randomizer) RollD6() int       { return r.Intn(6) + 1 }
-func (r *Randomizer) RollD4() int       { return r.Intn(4) + 1 }
-func (r *Randomizer) RollD12() int      { return r.Intn(12) + 1 }
-func (r *Randomizer) RollDN(n int) int  { return r.Intn(n) + 1 }
-func (r *Randomizer) CoinFlip() bool    { return r.Intn(2) == 0 }
+func (r *Randomizer) Intn(n int) int { return r.rng.IntN(n) }
+
+func (r *Randomizer) RollD6() int {
+	if r.d6 == nil {
+		return r.RollDN(6)
+	}
+	var v int
+	r.recordSourced(func() TraceStep {
+		v = r.d6.RollD6()
+		return TraceStep{Kind: StepDie, Sides: 6, Value: v}
+	})
+	return v
+}
+
+func (r *Randomizer) RollD4() int  { return r.RollDN(4) }
+func (r *Randomizer) RollD12() int { return r.RollDN(12) }
+
+func (r *Randomizer) RollDN(n int) int {
+	if ds, ok := r.d6.(DieSource); ok && slices.Contains(StandardDice, n) {
+		var v int
+		r.recordSourced(func() TraceStep {
+			v = ds.RollDN(n)
+			return TraceStep{Kind: StepDie, Sides: n, Value: v}
+		})
+		return v
+	}
+	v := r.Intn(n) + 1
+	r.record(TraceStep{Kind: StepDie, Sides: n, Value: v})
+	return v
+}
+
+// RollDF rolls a Fudge die: -1, 0 or +1.
+func (r *Randomizer) RollDF() int {
+	if ds, ok := r.d6.(DieSource); ok {
+		var v int
+		r.recordSourced(func() TraceStep {
+			v = ds.RollDF()
+			return TraceStep{Kind: StepDie, Value: v}
+		})
+		return v
+	}
+	v := r.Intn(3) - 1
+	r.record(TraceStep{Kind: StepDie, Value: v})
+	return v
+}
+
+func (r *Randomizer) CoinFlip() bool {
+	var heads bool
+	if ds, ok := r.d6.(DieSource); ok {
+		r.recordSourced(func() TraceStep {
+			heads = ds.CoinFlip()
+			return coinStep(heads)
+		})
+		return heads
+	}
+	heads = r.Intn(2) == 0
+	r.record(coinStep(heads))
+	return heads
+}
+
+func coinStep(heads bool) TraceStep {
+	step := TraceStep{Kind: StepCoin}
+	if heads {
+		step.Value = 1
+	}
+	return step
+}
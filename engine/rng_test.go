@@ -1,6 +1,9 @@
 package engine
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestSeededRandomizerDeterministic(t *testing.T) {
 	r1 := NewSeededRandomizer(42, 0)
@@ -48,3 +51,29 @@ func TestCoinFlipBool(t *testing.T) {
 		t.Fatal("CoinFlip never produced one of the outcomes")
 	}
 }
+
+// fixedDice answers every die with its size and every coin with heads.
+type fixedDice struct{ asked []int }
+
+func (f *fixedDice) RollD6() int      { return f.RollDN(6) }
+func (f *fixedDice) RollDN(n int) int { f.asked = append(f.asked, n); return n }
+func (f *fixedDice) RollDF() int      { f.asked = append(f.asked, 3); return 1 }
+func (f *fixedDice) CoinFlip() bool   { f.asked = append(f.asked, 2); return true }
+
+func TestDieSource(t *testing.T) {
+	rng := NewSeededRandomizer(4, 0)
+	src := &fixedDice{}
+	rng.SetD6Source(src)
+	if v := rng.RollDN(20); v != 20 {
+		t.Errorf("d20 = %d, want the source's", v)
+	}
+	if !rng.CoinFlip() || rng.RollDF() != 1 {
+		t.Error("coin and dF should come from the source")
+	}
+	if v := rng.RollDN(73); v < 1 || v > 73 {
+		t.Errorf("d73 = %d", v)
+	}
+	if want := []int{20, 2, 3}; !slices.Equal(src.asked, want) {
+		t.Errorf("asked %v, want %v (no d73)", src.asked, want)
+	}
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RosterNPC is a generated NPC kept as a named character: what the NPC
+// generator rolled for it, and its portrait.
+type RosterNPC struct {
+	Name     string         `json:"name"`
+	Identity string         `json:"identity"`
+	Goal     string         `json:"goal"`
+	Feature  string         `json:"feature"`
+	Attitude string         `json:"attitude"`
+	Portrait PortraitParams `json:"portrait"`
+}
+
+// npcRows are the OPSE rows that call on an NPC: Scene Complication 4 and
+// Pacing Move 3.
+var npcRows = []tableRow{{"Scene Complications", 4}, {"Pacing Moves", 3}}
+
+// NewRosterNPC names an NPC result, to keep it on the roster.
+func NewRosterNPC(name string, r NPCResult, portrait PortraitParams) RosterNPC {
+	return RosterNPC{
+		Name:     strings.TrimSpace(name),
+		Identity: r.Identity.Entry,
+		Goal:     r.Goal.Entry,
+		Feature:  r.Feature + " — " + r.FeatureDetail.Entry,
+		Attitude: r.Attitude.Result,
+		Portrait: portrait,
+	}
+}
+
+// FindNPC returns the index of the roster NPC called name, ignoring case,
+// or -1.
+func FindNPC(roster []RosterNPC, name string) int {
+	return slices.IndexFunc(roster, func(n RosterNPC) bool { return strings.EqualFold(n.Name, name) })
+}
+
+// AddNPC adds n to the roster, unless the name is taken.
+func AddNPC(roster []RosterNPC, n RosterNPC) ([]RosterNPC, error) {
+	if n.Name == "" {
+		return roster, fmt.Errorf("an NPC needs a name")
+	}
+	if FindNPC(roster, n.Name) >= 0 {
+		return roster, fmt.Errorf("%s is already on the roster", n.Name)
+	}
+	return append(roster, n), nil
+}
+
+// RemoveNPC takes the NPC called name off the roster.
+func RemoveNPC(roster []RosterNPC, name string) ([]RosterNPC, error) {
+	i := FindNPC(roster, strings.TrimSpace(name))
+	if i < 0 {
+		return roster, fmt.Errorf("no NPC %q on the roster", name)
+	}
+	return slices.Delete(roster, i, i+1), nil
+}
@@ -0,0 +1,72 @@
+package engine
+
+import "testing"
+
+func TestRoster(t *testing.T) {
+	rng := NewSeededRandomizer(24, 0)
+	r := NPCGenerator(NewDeck(rng), rng)
+	vex := NewRosterNPC(" Vex ", r, GenerateRandomPortrait(rng))
+	if vex.Name != "Vex" || vex.Identity != r.Identity.Entry || vex.Attitude != r.Attitude.Result {
+		t.Errorf("npc = %+v", vex)
+	}
+	roster, err := AddNPC(nil, vex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddNPC(roster, RosterNPC{Name: "vex"}); err == nil {
+		t.Error("a taken name should be an error")
+	}
+	if roster, err = RemoveNPC(roster, "VEX"); err != nil || len(roster) != 0 {
+		t.Errorf("remove: %v, %+v", err, roster)
+	}
+	if _, err := RemoveNPC(roster, "Vex"); err == nil {
+		t.Error("removing an NPC not on the roster should be an error")
+	}
+}
+
+func TestSceneComplicationNamesNPC(t *testing.T) {
+	rng := NewSeededRandomizer(24, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), NPCs: []string{"Vex"}}
+	g, _ := LookupGenerator("set_scene")
+	for range 100 {
+		res, err := g.Generate(s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := res.(SetTheSceneResult).Complication; r.Roll == 4 {
+			if r.Result != "An NPC acts suddenly → Vex" {
+				t.Errorf("result = %q, want the roster NPC named", r.Result)
+			}
+			return
+		}
+	}
+	t.Error("never rolled An NPC acts suddenly")
+}
+
+func TestNPCNamedByRowNotText(t *testing.T) {
+	if err := SetOverrides(map[string][]string{"scene-complications": {
+		"Hostile forces oppose you", "An obstacle blocks your way", "An NPC acts suddenly", "Someone steps in", "All is not as it seems", "Things actually go as planned",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetOverrides(nil) })
+	rng := NewSeededRandomizer(27, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), NPCs: []string{"Vex"}}
+	g, _ := LookupGenerator("set_scene")
+	seen := map[int]bool{}
+	for range 200 {
+		res, err := g.Generate(s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := res.(SetTheSceneResult).Complication
+		seen[r.Roll] = true
+		want := map[int]string{3: "An NPC acts suddenly", 4: "Someone steps in → Vex"}[r.Roll]
+		if want != "" && r.Result != want {
+			t.Errorf("roll %d = %q, want %q", r.Roll, r.Result, want)
+		}
+	}
+	if !seen[3] || !seen[4] {
+		t.Errorf("rolled %v", seen)
+	}
+}
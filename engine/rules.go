@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Rules is the table data read from an OPSE rules text, such as the
+// opse_rules.txt shipped with the app.
+type Rules struct {
+	Version string
+	Tables  []RulesTable
+}
+
+// RulesTable is one table from the rules text, under its heading there,
+// e.g. "PACING MOVES". Rows are in roll order: 1-6, or ranks 2 to A.
+type RulesTable struct {
+	Heading string
+	Card    bool
+	Rows    []string
+}
+
+var (
+	// rulesTableHeading matches "PACING MOVES (D6):" or "GOAL (CARD):".
+	rulesTableHeading = regexp.MustCompile(`^(.+?) \((D6|CARD)\):$`)
+	// rulesSection matches an all-caps section heading such as
+	// "ORACLE (HOW)", which names the "Answer (D6):" table under it.
+	rulesSection = regexp.MustCompile(`^[A-Z][A-Z0-9 ()/]*:?$`)
+)
+
+// typography maps the rules text's punctuation to the plain ASCII the
+// compiled tables use.
+var typography = strings.NewReplacer("‘", "'", "’", "'", "“", `"`, "”", `"`, "…", "...")
+
+// ParseRules reads the tables from an OPSE rules text. A table starts at a
+// heading line such as "SCENE COMPLICATION (D6):" and runs to the next
+// blank line; lines before its first row describe it and are skipped.
+// Rows are a roll or range and the result, separated by a tab, with card
+// tables laid out in two columns.
+func ParseRules(text string) (Rules, error) {
+	var (
+		rules   Rules
+		errs    []error
+		section string
+		cur     *RulesTable
+		filled  []bool
+	)
+	finish := func() {
+		if cur == nil {
+			return
+		}
+		for i, ok := range filled {
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: no row for %s", cur.Heading, rulesRoll(cur.Card, i)))
+				break
+			}
+		}
+		rules.Tables = append(rules.Tables, *cur)
+		cur = nil
+	}
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if v, ok := strings.CutPrefix(trimmed, "Version:"); ok && rules.Version == "" {
+			rules.Version = strings.TrimSpace(strings.Split(v, "\t")[0])
+			continue
+		}
+		if m := rulesTableHeading.FindStringSubmatch(trimmed); m != nil {
+			finish()
+			heading := m[1]
+			if heading != strings.ToUpper(heading) {
+				heading = section
+			}
+			cur = &RulesTable{Heading: heading, Card: m[2] == "CARD"}
+			size := 6
+			if cur.Card {
+				size = len(cardOrder)
+			}
+			cur.Rows = make([]string, size)
+			filled = make([]bool, size)
+			continue
+		}
+		if cur == nil {
+			if rulesSection.MatchString(trimmed) {
+				section = strings.TrimSuffix(trimmed, ":")
+			}
+			continue
+		}
+		if trimmed == "" {
+			if slices.Contains(filled, true) {
+				finish()
+			}
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool { return r == '\t' })
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fields = slices.DeleteFunc(fields, func(f string) bool { return f == "" })
+		if len(fields)%2 != 0 {
+			if !slices.Contains(filled, true) {
+				continue // a description line
+			}
+			errs = append(errs, fmt.Errorf("line %d: %s: want a roll and a result", n+1, cur.Heading))
+			continue
+		}
+		for i := 0; i < len(fields); i += 2 {
+			lo, hi, err := parseRulesRange(cur.Card, fields[i])
+			if err != nil {
+				if !slices.Contains(filled, true) {
+					break // a description line
+				}
+				errs = append(errs, fmt.Errorf("line %d: %s: %w", n+1, cur.Heading, err))
+				break
+			}
+			for r := lo; r <= hi; r++ {
+				cur.Rows[r] = typography.Replace(fields[i+1])
+				filled[r] = true
+			}
+		}
+	}
+	finish()
+	if len(rules.Tables) == 0 {
+		errs = append(errs, errors.New("no tables found"))
+	}
+	return rules, errors.Join(errs...)
+}
+
+// parseRulesRange reads a roll such as "4" or "3-4", or a card rank, as
+// row indexes.
+func parseRulesRange(card bool, s string) (int, int, error) {
+	lo, hi, found := strings.Cut(s, "-")
+	if !found {
+		hi = lo
+	}
+	first, ok1 := rulesIndex(card, lo)
+	last, ok2 := rulesIndex(card, hi)
+	if !ok1 || !ok2 || last < first {
+		return 0, 0, fmt.Errorf("invalid roll %q", s)
+	}
+	return first, last, nil
+}
+
+func rulesIndex(card bool, s string) (int, bool) {
+	if card {
+		r, ok := parseRank(strings.ToLower(s))
+		if !ok {
+			return 0, false
+		}
+		return slices.Index(cardOrder, r), true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 6 {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+func rulesRoll(card bool, i int) string {
+	if card {
+		return cardOrder[i].String()
+	}
+	return strconv.Itoa(i + 1)
+}
+
+// LoadRules reads and parses a rules text file.
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, err
+	}
+	r, err := ParseRules(string(data))
+	if err != nil {
+		return r, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+func (r Rules) table(heading string) (RulesTable, bool) {
+	for _, t := range r.Tables {
+		if t.Heading == heading {
+			return t, true
+		}
+	}
+	return RulesTable{}, false
+}
+
+// SetRules makes the rules text's tables the ones the engine rolls on, in
+// place of the compiled ones. House rules then apply on top, so call
+// SetOverrides afterwards. Built-in tables the text lacks keep their
+// compiled rows and are reported.
+func SetRules(r Rules) error {
+	var errs []error
+	for _, t := range builtinTables {
+		t.orig = t.compiled
+		if t.rules == "" {
+			continue
+		}
+		rt, ok := r.table(t.rules)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: not in the rules text", t.rules))
+			continue
+		}
+		if err := t.set(rt.Rows); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		t.orig = rt.Rows
+	}
+	for _, t := range builtinTables {
+		t.set(t.orig)
+	}
+	return errors.Join(errs...)
+}
+
+// RulesDiff is a row where the rules text and the compiled tables
+// disagree. An empty Roll means the whole table is missing from one side.
+type RulesDiff struct {
+	Table    string // built-in table name, or the rules heading if it has none
+	Roll     string
+	Rules    string
+	Compiled string
+}
+
+// DiffRules compares the rules text's tables with the compiled ones, in
+// table order, followed by tables in the text the engine doesn't know.
+func DiffRules(r Rules) []RulesDiff {
+	var diffs []RulesDiff
+	known := map[string]bool{}
+	for _, t := range builtinTables {
+		if t.rules == "" {
+			continue
+		}
+		known[t.rules] = true
+		rt, ok := r.table(t.rules)
+		if !ok {
+			diffs = append(diffs, RulesDiff{Table: t.name, Compiled: t.rules})
+			continue
+		}
+		rolls := t.rolls(len(t.compiled))
+		for i, row := range t.compiled {
+			if i < len(rt.Rows) && rt.Rows[i] != row {
+				diffs = append(diffs, RulesDiff{Table: t.name, Roll: rolls[i], Rules: rt.Rows[i], Compiled: row})
+			}
+		}
+	}
+	for _, rt := range r.Tables {
+		if !known[rt.Heading] {
+			diffs = append(diffs, RulesDiff{Table: rt.Heading, Rules: rt.Heading})
+		}
+	}
+	return diffs
+}
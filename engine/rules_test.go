@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseShippedRules(t *testing.T) {
+	r, err := LoadRules("../opse_rules.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != "1.6" {
+		t.Errorf("version = %q", r.Version)
+	}
+	how, ok := r.table("ORACLE (HOW)")
+	if !ok || how.Rows[2] != "About average" || how.Rows[3] != "About average" {
+		t.Errorf("ORACLE (HOW) = %+v", how)
+	}
+	action, ok := r.table("ACTION FOCUS")
+	if !ok || !action.Card || action.Rows[6] != "Reveal" || action.Rows[8] != "Take" {
+		t.Errorf("ACTION FOCUS = %+v", action)
+	}
+	diffs := DiffRules(r)
+	want := RulesDiff{Table: "Scene Complications", Roll: "5", Rules: "All is not as is seems", Compiled: "All is not as it seems"}
+	if len(diffs) != 1 || diffs[0] != want {
+		t.Errorf("diffs = %+v, want only the Scene Complication typo", diffs)
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	tests := map[string]string{
+		"PACING MOVES (D6):\n1\ta\n2\tb\n3-6\tc\n":    "",
+		"PACING MOVES (D6):\nWhat now?\n1-5\ta\n6\tb": "",
+		"PACING MOVES (D6):\n1\ta\n2\tb\n":            "no row for 3",
+		"PACING MOVES (D6):\n1-6\ta\n7\tb\n":          `invalid roll "7"`,
+		"Nothing to see here.\n":                      "no tables",
+	}
+	for text, want := range tests {
+		_, err := ParseRules(text)
+		if want == "" && err != nil {
+			t.Errorf("%q: %v", text, err)
+		}
+		if want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+			t.Errorf("%q: err = %v, want %q", text, err, want)
+		}
+	}
+}
+
+func TestSetRules(t *testing.T) {
+	defer SetOverrides(nil)
+	defer SetRules(Rules{})
+	r, err := ParseRules("FAILURE MOVES (D6):\n1\tA\n2\tB\n3\tC\n4\tD\n5\tE\n6\tF\n\nMYSTERY (D6):\n1-6\tx\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRules(r); err == nil || !strings.Contains(err.Error(), "PACING MOVES: not in the rules text") {
+		t.Errorf("err = %v", err)
+	}
+	if failureMoves[3] != "C" || pacingMoves[1] != "Foreshadow Trouble" {
+		t.Errorf("failure moves = %q, pacing moves = %q", failureMoves, pacingMoves)
+	}
+	if err := SetOverrides(map[string][]string{"pacing-moves": {"1", "2", "3", "4", "5", "6"}}); err != nil {
+		t.Fatal(err)
+	}
+	if o := Overrides(); len(o) != 1 || o[0].Name != "Pacing Moves" {
+		t.Errorf("the rules text shouldn't count as house rules: %+v", o)
+	}
+	diffs := DiffRules(r)
+	last := diffs[len(diffs)-1]
+	if last.Table != "MYSTERY" || last.Roll != "" {
+		t.Errorf("unknown table not reported: %+v", last)
+	}
+}
@@ -2,8 +2,12 @@ package engine
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 )
 
 type SavedRoll struct {
@@ -12,6 +16,7 @@ type SavedRoll struct {
 	Expression string `json:"expression"`
 	Folder     string `json:"folder"`
 	SortOrder  int    `json:"sort_order"`
+	Check      bool   `json:"check,omitempty"` // roll it as a /check
 }
 
 type RollFolder struct {
@@ -22,6 +27,126 @@ type RollFolder struct {
 type SavedRollsConfig struct {
 	Folders []RollFolder `json:"folders"`
 	Rolls   []SavedRoll  `json:"rolls"`
+
+	// Macros have their own folders, apart from the rolls'.
+	MacroFolders []RollFolder `json:"macro_folders,omitempty"`
+	Macros       []Macro      `json:"macros,omitempty"`
+}
+
+// Macro is a saved sequence of steps run in order: slash commands such as
+// /scene, and narrative lines logged as if typed. $name in a step is a
+// parameter, given when the macro runs.
+type Macro struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Folder string   `json:"folder"`
+	Steps  []string `json:"steps"`
+}
+
+var macroParam = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ParseMacroSteps splits steps written on one line, separated by ";".
+func ParseMacroSteps(line string) []string {
+	var steps []string
+	for _, s := range strings.Split(line, ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// Params lists the macro's parameters in the order they first appear.
+func (m Macro) Params() []string {
+	var params []string
+	for _, step := range m.Steps {
+		for _, p := range macroParam.FindAllStringSubmatch(step, -1) {
+			if !slices.Contains(params, p[1]) {
+				params = append(params, p[1])
+			}
+		}
+	}
+	return params
+}
+
+// Expand fills in the macro's parameters from args, one word each except
+// the last, which takes the rest. It returns the parameters still missing
+// if there are too few args.
+func (m Macro) Expand(args []string) (steps, missing []string) {
+	params := m.Params()
+	if len(args) < len(params) {
+		return nil, params[len(args):]
+	}
+	values := map[string]string{}
+	for i, p := range params {
+		values[p] = args[i]
+		if i == len(params)-1 {
+			values[p] = strings.Join(args[i:], " ")
+		}
+	}
+	for _, step := range m.Steps {
+		steps = append(steps, macroParam.ReplaceAllStringFunc(step, func(p string) string { return values[p[1:]] }))
+	}
+	return steps, nil
+}
+
+// Macro finds a macro by ID.
+func (c *SavedRollsConfig) Macro(id string) (Macro, bool) {
+	i := slices.IndexFunc(c.Macros, func(m Macro) bool { return m.ID == id })
+	if i < 0 {
+		return Macro{}, false
+	}
+	return c.Macros[i], true
+}
+
+// FindMacro finds a macro by name, ignoring case.
+func (c *SavedRollsConfig) FindMacro(name string) (Macro, bool) {
+	i := slices.IndexFunc(c.Macros, func(m Macro) bool { return strings.EqualFold(m.Name, name) })
+	if i < 0 {
+		return Macro{}, false
+	}
+	return c.Macros[i], true
+}
+
+func (c *SavedRollsConfig) AddMacro(m Macro) {
+	c.Macros = append(c.Macros, m)
+}
+
+func (c *SavedRollsConfig) DeleteMacro(id string) {
+	c.Macros = slices.DeleteFunc(c.Macros, func(m Macro) bool { return m.ID == id })
+}
+
+func (c *SavedRollsConfig) MacrosByFolder() map[string][]Macro {
+	m := make(map[string][]Macro)
+	for _, mac := range c.Macros {
+		m[mac.Folder] = append(m[mac.Folder], mac)
+	}
+	return m
+}
+
+func (c *SavedRollsConfig) AddMacroFolder(name string) {
+	for _, f := range c.MacroFolders {
+		if f.Name == name {
+			return
+		}
+	}
+	c.MacroFolders = append(c.MacroFolders, RollFolder{Name: name, SortOrder: len(c.MacroFolders)})
+}
+
+// DeleteMacroFolder removes a macro folder and the macros in it.
+func (c *SavedRollsConfig) DeleteMacroFolder(name string) {
+	c.MacroFolders = slices.DeleteFunc(c.MacroFolders, func(f RollFolder) bool { return f.Name == name })
+	c.Macros = slices.DeleteFunc(c.Macros, func(m Macro) bool { return m.Folder == name })
+}
+
+// NextMacroID returns an ID no macro has yet.
+func (c *SavedRollsConfig) NextMacroID() string {
+	for n := len(c.Macros) + 1; ; n++ {
+		id := fmt.Sprintf("mc_%d", n)
+		if _, ok := c.Macro(id); !ok {
+			return id
+		}
+	}
 }
 
 func savedRollsPath() string {
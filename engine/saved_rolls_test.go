@@ -58,3 +58,40 @@ func TestLoadSavedRolls_NonExistent(t *testing.T) {
 		t.Error("should return non-nil config")
 	}
 }
+
+func TestMacroExpand(t *testing.T) {
+	mac := Macro{Name: "Meet", Steps: ParseMacroSteps("/npc ; $who arrives at the $place;; /oracle likely Is $who hostile?")}
+	if len(mac.Steps) != 3 {
+		t.Fatalf("steps = %q", mac.Steps)
+	}
+	if p := mac.Params(); len(p) != 2 || p[0] != "who" || p[1] != "place" {
+		t.Errorf("params = %q", p)
+	}
+	if _, missing := mac.Expand([]string{"Vex"}); len(missing) != 1 || missing[0] != "place" {
+		t.Errorf("missing = %q, want place", missing)
+	}
+	steps, missing := mac.Expand([]string{"Vex", "old", "mill"})
+	if missing != nil {
+		t.Fatalf("missing = %q", missing)
+	}
+	if steps[1] != "Vex arrives at the old mill" || steps[2] != "/oracle likely Is Vex hostile?" {
+		t.Errorf("steps = %q", steps)
+	}
+}
+
+func TestSavedRollsConfig_DeleteMacroFolder(t *testing.T) {
+	cfg := &SavedRollsConfig{}
+	cfg.AddMacroFolder("Scenes")
+	cfg.AddMacro(Macro{ID: cfg.NextMacroID(), Name: "New Scene", Folder: "Scenes"})
+	cfg.AddMacro(Macro{ID: cfg.NextMacroID(), Name: "Loot"})
+	if cfg.Macros[1].ID != "mc_2" {
+		t.Errorf("second macro ID = %q", cfg.Macros[1].ID)
+	}
+	if _, ok := cfg.FindMacro("new scene"); !ok {
+		t.Error("FindMacro should ignore case")
+	}
+	cfg.DeleteMacroFolder("Scenes")
+	if len(cfg.MacroFolders) != 0 || len(cfg.Macros) != 1 || cfg.Macros[0].Name != "Loot" {
+		t.Errorf("after delete: %+v", cfg)
+	}
+}
@@ -1,5 +1,7 @@
 package engine
 
+import "strings"
+
 var sceneComplications = [7]string{
 	"",
 	"Hostile forces oppose you",
@@ -21,11 +23,13 @@ var alteredScenes = [7]string{
 }
 
 func SceneComplication(rng *Randomizer) SceneComplicationResult {
+	rng.beginTrace("Scene Complication")
 	roll := rng.RollD6()
-	return SceneComplicationResult{Roll: roll, Result: sceneComplications[roll]}
+	return SceneComplicationResult{Roll: roll, Result: sceneComplications[roll], Trace: rng.endTrace()}
 }
 
 func SetTheScene(rng *Randomizer, deck *Deck) SetTheSceneResult {
+	rng.beginTrace("Set the Scene")
 	comp := SceneComplication(rng)
 	altRoll := rng.RollD6()
 	result := SetTheSceneResult{
@@ -34,28 +38,45 @@ func SetTheScene(rng *Randomizer, deck *Deck) SetTheSceneResult {
 		Altered:      altRoll >= 5,
 	}
 
-	if !result.Altered {
-		return result
+	if result.Altered {
+		result.AlteredScene = alterScene(rng, deck)
 	}
+	result.Trace = rng.endTrace()
+	return result
+}
 
-	altSceneRoll := rng.RollD6()
-	alt := AlteredSceneResult{
-		Roll:   altSceneRoll,
-		Result: alteredScenes[altSceneRoll],
-	}
+func alterScene(rng *Randomizer, deck *Deck) *AlteredSceneResult {
+	roll := rng.RollD6()
+	alt := &AlteredSceneResult{Roll: roll, Result: alteredScenes[roll]}
 
-	switch altSceneRoll {
+	switch roll {
 	case 4:
 		cascade := SceneComplication(rng)
-		alt.Cascade = &cascade
+		alt.Complication = &cascade
 	case 5:
 		cascade := PacingMove(rng, deck)
-		alt.Cascade = &cascade
+		alt.PacingMove = &cascade
 	case 6:
 		cascade := RandomEvent(deck, rng)
-		alt.Cascade = &cascade
+		alt.RandomEvent = &cascade
 	}
+	return alt
+}
 
-	result.AlteredScene = &alt
-	return result
+// SplitSceneTitle splits /scene's arguments, "TITLE goal: GOAL", into the
+// scene's title and goal. Either may be empty.
+func SplitSceneTitle(args []string) (title, goal string) {
+	text := strings.Join(args, " ")
+	i := strings.Index(strings.ToLower(" "+text), " goal:")
+	if i < 0 {
+		return strings.TrimSpace(text), ""
+	}
+	return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+len("goal:"):])
+}
+
+// setScene rolls /scene [TITLE] [goal: GOAL].
+func setScene(s *Session, args []string) (any, error) {
+	r := SetTheScene(s.Rng, s.Deck)
+	r.Title, r.Goal = SplitSceneTitle(args)
+	return r, nil
 }
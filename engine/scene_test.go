@@ -1,6 +1,9 @@
 package engine
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestSceneComplicationValidResult(t *testing.T) {
 	rng := NewSeededRandomizer(70, 0)
@@ -54,11 +57,11 @@ func TestSetTheScene_CascadeToComplication(t *testing.T) {
 	for range 500 {
 		r := SetTheScene(rng, NewDeck(rng))
 		if r.Altered && r.AlteredScene != nil && r.AlteredScene.Roll == 4 {
-			if r.AlteredScene.Cascade == nil {
+			if r.AlteredScene.Complication == nil {
 				t.Error("altered scene roll 4 should cascade to complication")
 			}
-			if _, ok := r.AlteredScene.Cascade.(*SceneComplicationResult); !ok {
-				t.Error("altered scene roll 4 cascade should be SceneComplicationResult")
+			if r.AlteredScene.PacingMove != nil || r.AlteredScene.RandomEvent != nil {
+				t.Error("altered scene roll 4 should only cascade to complication")
 			}
 			return
 		}
@@ -70,13 +73,28 @@ func TestSetTheScene_CascadeToPacingMove(t *testing.T) {
 	for range 500 {
 		r := SetTheScene(rng, NewDeck(rng))
 		if r.Altered && r.AlteredScene != nil && r.AlteredScene.Roll == 5 {
-			if r.AlteredScene.Cascade == nil {
+			if r.AlteredScene.PacingMove == nil {
 				t.Error("altered scene roll 5 should cascade to pacing move")
 			}
-			if _, ok := r.AlteredScene.Cascade.(*PacingMoveResult); !ok {
-				t.Error("altered scene roll 5 cascade should be PacingMoveResult")
+			if r.AlteredScene.Complication != nil || r.AlteredScene.RandomEvent != nil {
+				t.Error("altered scene roll 5 should only cascade to pacing move")
 			}
 			return
 		}
 	}
 }
+
+func TestSplitSceneTitle(t *testing.T) {
+	tests := map[string][2]string{
+		"":                                   {"", ""},
+		"The Old Mill":                       {"The Old Mill", ""},
+		"The Old Mill Goal: find the miller": {"The Old Mill", "find the miller"},
+		"goal: get out alive":                {"", "get out alive"},
+	}
+	for in, want := range tests {
+		title, goal := SplitSceneTitle(strings.Fields(in))
+		if title != want[0] || goal != want[1] {
+			t.Errorf("%q = %q, %q, want %q, %q", in, title, goal, want[0], want[1])
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Stat kinds on a character sheet.
+const (
+	StatAttribute = "attribute" // e.g. str, wits
+	StatSkill     = "skill"     // e.g. stealth
+	StatResource  = "resource"  // spent and regained, e.g. hp
+)
+
+// Stat is one named number on a character sheet.
+type Stat struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+	Kind  string `json:"kind,omitempty"` // an attribute when empty
+}
+
+// CharacterSheet holds the stats of a character, named as in /char.
+type CharacterSheet struct {
+	Name  string `json:"name"`
+	Stats []Stat `json:"stats"`
+}
+
+// Stat finds a stat by name, ignoring case.
+func (c *CharacterSheet) Stat(name string) (Stat, bool) {
+	i := c.index(name)
+	if i < 0 {
+		return Stat{}, false
+	}
+	return c.Stats[i], true
+}
+
+func (c *CharacterSheet) index(name string) int {
+	return slices.IndexFunc(c.Stats, func(s Stat) bool { return strings.EqualFold(s.Name, name) })
+}
+
+// Edit applies changes such as "str=2", "hp-=3" or "luck+=1", and "str="
+// to remove a stat. A new stat is an attribute, or after the word skills
+// or resources, one of those.
+func (c *CharacterSheet) Edit(changes []string) error {
+	kind := StatAttribute
+	for _, ch := range changes {
+		switch strings.ToLower(ch) {
+		case "attributes", "attribute":
+			kind = StatAttribute
+			continue
+		case "skills", "skill":
+			kind = StatSkill
+			continue
+		case "resources", "resource":
+			kind = StatResource
+			continue
+		}
+		name, value, ok := strings.Cut(ch, "=")
+		op := byte('=')
+		if n := len(name); ok && n > 0 && (name[n-1] == '+' || name[n-1] == '-') {
+			name, op = name[:n-1], name[n-1]
+		}
+		if !ok || !validStatName(name) {
+			return fmt.Errorf("%q: want STAT=N, STAT+=N or STAT-=N", ch)
+		}
+		i := c.index(name)
+		if value == "" && op == '=' {
+			if i >= 0 {
+				c.Stats = slices.Delete(c.Stats, i, i+1)
+			}
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q: %q is not a number", ch, value)
+		}
+		if i < 0 {
+			if op != '=' {
+				return fmt.Errorf("%s has no %s to change", c.Name, name)
+			}
+			c.Stats = append(c.Stats, Stat{Name: name, Kind: kind})
+			i = len(c.Stats) - 1
+		}
+		switch op {
+		case '+':
+			c.Stats[i].Value += n
+		case '-':
+			c.Stats[i].Value -= n
+		default:
+			c.Stats[i].Value = n
+		}
+	}
+	return nil
+}
+
+// validStatName reports whether name can be written in a reference such
+// as @name: letters, digits and underscores.
+func validStatName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range strings.ToLower(name) {
+		if r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// FindSheet returns the index of the sheet for character, ignoring case,
+// or -1.
+func FindSheet(sheets []CharacterSheet, character string) int {
+	return slices.IndexFunc(sheets, func(c CharacterSheet) bool { return strings.EqualFold(c.Name, character) })
+}
+
+// Stats resolves stat references against sheets. A reference with no
+// character reads the current one, or the only sheet if there is no
+// current character.
+func Stats(sheets []CharacterSheet, current string) StatResolver {
+	return func(character, stat string) (int, error) {
+		if character == "" {
+			character = current
+		}
+		if character == "" {
+			if len(sheets) != 1 {
+				return 0, fmt.Errorf("no current character; use /char NAME or @NAME.%s", stat)
+			}
+			character = sheets[0].Name
+		}
+		i := FindSheet(sheets, character)
+		if i < 0 {
+			return 0, fmt.Errorf("%s has no character sheet", character)
+		}
+		s, ok := sheets[i].Stat(stat)
+		if !ok {
+			return 0, fmt.Errorf("%s has no %s", sheets[i].Name, stat)
+		}
+		return s.Value, nil
+	}
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCharacterSheetEdit(t *testing.T) {
+	c := CharacterSheet{Name: "Elara"}
+	if err := c.Edit([]string{"str=2", "wits=-1", "skills", "stealth=3", "resources", "hp=10", "hp-=4", "STR+=1"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []Stat{{"str", 3, StatAttribute}, {"wits", -1, StatAttribute}, {"stealth", 3, StatSkill}, {"hp", 6, StatResource}}
+	if len(c.Stats) != len(want) {
+		t.Fatalf("stats = %+v", c.Stats)
+	}
+	for i, s := range want {
+		if c.Stats[i] != s {
+			t.Errorf("stat %d = %+v, want %+v", i, c.Stats[i], s)
+		}
+	}
+	if err := c.Edit([]string{"wits="}); err != nil || len(c.Stats) != 3 {
+		t.Errorf("wits= should remove it: %v, %+v", err, c.Stats)
+	}
+	for _, bad := range []string{"str", "str=x", "luck+=1", "st-r=1"} {
+		if err := c.Edit([]string{bad}); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestParseDiceStatReferences(t *testing.T) {
+	sheets := []CharacterSheet{
+		{Name: "Elara", Stats: []Stat{{Name: "str", Value: 3}}},
+		{Name: "Sir Beans", Stats: []Stat{{Name: "Wits", Value: -1}}},
+	}
+	stats := Stats(sheets, "elara")
+	tests := map[string]string{
+		"d20+@str":                  "d20+@str",
+		"2d6 + @\"Sir Beans\".wits": `2d6+@"Sir Beans".wits`,
+		"(d8+@elara.str)*2":         "(d8+@elara.str)*2",
+	}
+	for in, want := range tests {
+		expr, err := ParseDiceWith(in, stats)
+		if err != nil {
+			t.Errorf("%q: %v", in, err)
+			continue
+		}
+		if got := expr.String(); got != want {
+			t.Errorf("%q = %q, want %q", in, got, want)
+		}
+	}
+	expr, _ := ParseDiceWith(`2d6+@"Sir Beans".wits`, stats)
+	if lo, hi := expr.Span(); lo != 1 || hi != 11 {
+		t.Errorf("span = %d..%d, want the stat counted", lo, hi)
+	}
+	r := RollDice(NewSeededRandomizer(1, 0), expr)
+	if b := r.Breakdown(func(f string, _ DieMark) string { return f }); !strings.HasSuffix(b, `+ @"Sir Beans".wits (-1)`) {
+		t.Errorf("breakdown = %q, want the resolved value shown", b)
+	}
+
+	errs := map[string]string{
+		"d20+@dex":      "Elara has no dex",
+		"d20+@bob.str":  "bob has no character sheet",
+		`d20+@"Sir B`:   "missing closing quote",
+		`d20+@"Elara"`:  "needs a stat",
+		"d20+@":         "needs a stat name",
+		"d20+@str/@str": "",
+	}
+	for in, want := range errs {
+		_, err := ParseDiceWith(in, stats)
+		if want == "" && err != nil {
+			t.Errorf("%q: %v", in, err)
+		}
+		if want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+			t.Errorf("%q: err = %v, want %q", in, err, want)
+		}
+	}
+	if _, err := ParseDice("d20+@str"); err == nil {
+		t.Error("a reference with no sheets should be an error")
+	}
+	if _, err := ParseDiceWith("d20+@str", Stats(sheets, "")); err == nil {
+		t.Error("a bare @stat with no current character and two sheets should be an error")
+	}
+}
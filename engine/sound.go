@@ -89,11 +89,13 @@ func SoundCategoryNames() []string {
 }
 
 func RandomSound(rng *Randomizer, category string) SoundResult {
+	rng.beginTrace("Sound")
 	if category != "" {
 		if sounds, ok := soundCategories[category]; ok {
-			return SoundResult{Sound: sounds[rng.Intn(len(sounds))], Category: category}
+			sound := sounds[rng.RollDN(len(sounds))-1]
+			return SoundResult{Sound: sound, Category: category, Trace: rng.endTrace()}
 		}
 	}
-	entry := allSoundsFlat[rng.Intn(len(allSoundsFlat))]
-	return SoundResult{Sound: entry.sound, Category: entry.category}
+	entry := allSoundsFlat[rng.RollDN(len(allSoundsFlat))-1]
+	return SoundResult{Sound: entry.sound, Category: entry.category, Trace: rng.endTrace()}
 }
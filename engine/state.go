@@ -0,0 +1,33 @@
+package engine
+
+// EngineState is a serializable snapshot of the randomizer and both decks,
+// so an adventure resumed later keeps drawing from the same shuffle.
+type EngineState struct {
+	RNG           []byte `json:"rng"`
+	Deck          []Card `json:"deck"`
+	UtilityDeck   []Card `json:"utility_deck"`
+	UtilityJokers bool   `json:"utility_jokers"`
+}
+
+// CaptureState snapshots the PCG state and the remaining cards of both decks.
+func CaptureState(rng *Randomizer, deck *Deck, utility *UtilityDeck) *EngineState {
+	seed, _ := rng.MarshalBinary()
+	return &EngineState{
+		RNG:           seed,
+		Deck:          deck.Cards(),
+		UtilityDeck:   utility.Cards(),
+		UtilityJokers: utility.Jokers(),
+	}
+}
+
+// RestoreState rebuilds the randomizer and decks from a snapshot. An empty
+// deck is reshuffled on its next draw, same as during play.
+func RestoreState(s *EngineState) (*Randomizer, *Deck, *UtilityDeck, error) {
+	rng := NewSeededRandomizer(0, 0)
+	if err := rng.UnmarshalBinary(s.RNG); err != nil {
+		return nil, nil, nil, err
+	}
+	deck := RestoreDeck(rng, s.Deck)
+	utility := RestoreUtilityDeck(rng, s.UtilityJokers, s.UtilityDeck)
+	return rng, deck, utility, nil
+}
@@ -0,0 +1,33 @@
+package engine
+
+import "testing"
+
+func TestRestoreStateContinuesSequence(t *testing.T) {
+	rng := NewSeededRandomizer(11, 22)
+	deck := NewDeck(rng)
+	utility := NewUtilityDeck(rng, true)
+	for range 5 {
+		deck.Draw(nil)
+	}
+	utility.Draw(3)
+
+	state := CaptureState(rng, deck, utility)
+	rng2, deck2, utility2, err := RestoreState(state)
+	if err != nil {
+		t.Fatalf("restore error: %v", err)
+	}
+	if deck2.Remaining() != deck.Remaining() {
+		t.Fatalf("deck remaining %d, want %d", deck2.Remaining(), deck.Remaining())
+	}
+	if !utility2.Jokers() || utility2.Remaining() != utility.Remaining() {
+		t.Fatal("utility deck not restored")
+	}
+	for i := range 100 {
+		if a, b := deck.Draw(nil).Card, deck2.Draw(nil).Card; a != b {
+			t.Fatalf("draw %d diverged: %s vs %s", i, a, b)
+		}
+		if a, b := rng.RollD6(), rng2.RollD6(); a != b {
+			t.Fatalf("roll %d diverged: %d vs %d", i, a, b)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// TableCategory is the sidebar heading for tables that don't name one.
+const TableCategory = "TABLES"
+
+// Table is a user-defined random table, loaded from a JSON, YAML or TOML
+// file:
+//
+//	{
+//	  "name": "Tavern Names",
+//	  "roll": "2d6",
+//	  "rows": [{"range": "2-6", "result": "The Red Lion"}, ...]
+//	}
+//
+// Roll is a dice expression such as "d6", "2d6" or "d100", or "card" to
+// index the rows by the rank of a card drawn from the deck. Rows are either
+// plain strings, one per possible roll (2 to A for cards), or objects with
+// a range such as "4", "1-3" or "J-A" so rows can cover several rolls.
+type Table struct {
+	Name        string     `json:"name"`
+	Category    string     `json:"category,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Roll        string     `json:"roll"`
+	Rows        []TableRow `json:"rows"`
+	Source      string     `json:"-"` // file the table was loaded from
+
+	dice     DiceTerm
+	modifier int
+}
+
+// TableRow is the result for every roll from Min to Max.
+type TableRow struct {
+	Range  string `json:"range,omitempty"`
+	Result string `json:"result"`
+
+	Min, Max int `json:"-"`
+}
+
+// UnmarshalJSON reads a row as a plain string or an object. A bare number
+// stands for its text, as YAML and TOML write `range: 4` or `- 12`.
+func (r *TableRow) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] != '{' {
+		return unmarshalText(data, &r.Result)
+	}
+	var row struct {
+		Range  json.RawMessage `json:"range"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+	if row.Range != nil {
+		if err := unmarshalText(row.Range, &r.Range); err != nil {
+			return fmt.Errorf("range: %w", err)
+		}
+	}
+	if row.Result == nil {
+		return nil
+	}
+	return unmarshalText(row.Result, &r.Result)
+}
+
+// unmarshalText reads a JSON string, or a number as written.
+func unmarshalText(data []byte, s *string) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err == nil {
+		*s = n.String()
+		return nil
+	}
+	return json.Unmarshal(data, s)
+}
+
+// tableFormats turn a table file into JSON for ParseTable, by extension.
+var tableFormats = map[string]func([]byte) ([]byte, error){
+	".json": func(data []byte) ([]byte, error) { return data, nil },
+	".yaml": yamlToJSON,
+	".yml":  yamlToJSON,
+	".toml": tomlToJSON,
+}
+
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func tomlToJSON(data []byte) ([]byte, error) {
+	var v map[string]any
+	if err := toml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// IsCard reports whether the table is indexed by card rank.
+func (t Table) IsCard() bool { return strings.EqualFold(t.Roll, "card") }
+
+// Slug is the table's name as a single command word, e.g. "tavern-names".
+func (t Table) Slug() string {
+	return strings.ToLower(strings.Join(strings.Fields(t.Name), "-"))
+}
+
+// span is the lowest and highest possible roll.
+func (t Table) span() (int, int) {
+	if t.IsCard() {
+		return int(RankTwo), int(RankAce)
+	}
+	lo, hi := t.dice.Span()
+	return lo + t.modifier, hi + t.modifier
+}
+
+// ParseTable reads a table from JSON and checks its rows cover every possible
+// roll exactly once.
+func ParseTable(data []byte) (Table, error) {
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Table{}, err
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		return Table{}, fmt.Errorf("table has no name")
+	}
+	if !t.IsCard() {
+		d, err := ParseDice(t.Roll)
+		if err != nil {
+			return Table{}, err
+		}
+		var ok bool
+		if t.dice, t.modifier, ok = plainDice(d); !ok {
+			return Table{}, fmt.Errorf("roll %q: tables take plain dice such as 2d6 or d6+1", t.Roll)
+		}
+	}
+	if err := t.assignRanges(); err != nil {
+		return Table{}, fmt.Errorf("%s: %w", t.Name, err)
+	}
+	return t, nil
+}
+
+// plainDice reports whether d is a single term of ordinary dice, plus or
+// minus a number.
+func plainDice(d DiceExpression) (DiceTerm, int, bool) {
+	n, mod := d.Root, 0
+	if n.Op == '+' || n.Op == '-' {
+		if n.Right.Dice != nil || n.Right.Op != 0 {
+			return DiceTerm{}, 0, false
+		}
+		n, mod = n.Left, n.Right.Value
+		if d.Root.Op == '-' {
+			mod = -mod
+		}
+	}
+	if t := n.Dice; t == nil || t.Fudge || t.Explode != "" || t.Reroll != "" || t.KeepMode != "" || t.IsPool() {
+		return DiceTerm{}, 0, false
+	}
+	return *n.Dice, mod, true
+}
+
+func (t *Table) assignRanges() error {
+	lo, hi := t.span()
+	next := lo
+	for i := range t.Rows {
+		r := &t.Rows[i]
+		if r.Range == "" {
+			r.Min, r.Max = next, next
+		} else {
+			var err error
+			if r.Min, r.Max, err = t.parseRange(r.Range); err != nil {
+				return err
+			}
+		}
+		if r.Min != next {
+			return fmt.Errorf("row %d starts at %s, want %s", i+1, t.rollName(r.Min), t.rollName(next))
+		}
+		if r.Max < r.Min || r.Max > hi {
+			return fmt.Errorf("row %d: range %s-%s is outside %s-%s",
+				i+1, t.rollName(r.Min), t.rollName(r.Max), t.rollName(lo), t.rollName(hi))
+		}
+		next = r.Max + 1
+	}
+	if next != hi+1 {
+		return fmt.Errorf("rows stop at %s, want %s", t.rollName(next-1), t.rollName(hi))
+	}
+	return nil
+}
+
+// parseRange reads "4", "1-3", "-2--1" or, for card tables, "J-A". The
+// range is split on the last dash that follows a digit or rank, so either
+// end may be negative.
+func (t Table) parseRange(s string) (int, int, error) {
+	lo, hi := s, s
+	for i := len(s) - 1; i > 0; i-- {
+		before := strings.TrimRight(s[:i], " ")
+		if s[i] == '-' && before != "" && !strings.HasSuffix(before, "-") {
+			lo, hi = s[:i], s[i+1:]
+			break
+		}
+	}
+	first, err1 := t.parseRoll(lo)
+	last, err2 := t.parseRoll(hi)
+	if err := errors.Join(err1, err2); err != nil {
+		return 0, 0, fmt.Errorf("range %q: %w", s, err)
+	}
+	return first, last, nil
+}
+
+func (t Table) parseRoll(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if t.IsCard() {
+		if r, ok := parseRank(strings.ToLower(s)); ok {
+			return int(r), nil
+		}
+		return 0, fmt.Errorf("invalid rank %q", s)
+	}
+	return strconv.Atoi(s)
+}
+
+func (t Table) rollName(n int) string {
+	if t.IsCard() {
+		return Rank(n).String()
+	}
+	return strconv.Itoa(n)
+}
+
+// TableDirs returns the folders tables are loaded from: the global one in
+// ~/.config/opse/tables, then one beside the journal, named after it.
+func TableDirs(journalPath string) []string {
+	dir, _ := os.UserConfigDir()
+	dirs := []string{filepath.Join(dir, "opse", "tables")}
+	if journalPath != "" {
+		dirs = append(dirs, strings.TrimSuffix(journalPath, filepath.Ext(journalPath))+".tables")
+	}
+	return dirs
+}
+
+// LoadTables reads every table file in dirs, skipping folders that don't
+// exist. A table in a later folder replaces one with the same name. Tables
+// that fail to load are reported together, and the rest are still returned.
+func LoadTables(dirs ...string) ([]Table, error) {
+	byName := map[string]Table{}
+	var errs []error
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			toJSON, ok := tableFormats[strings.ToLower(filepath.Ext(e.Name()))]
+			if !ok {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if data, err = toJSON(data); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+			t, err := ParseTable(data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+			t.Source = path
+			byName[t.Slug()] = t
+		}
+	}
+	tables := make([]Table, 0, len(byName))
+	for _, t := range byName {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].category() != tables[j].category() {
+			return tables[i].category() < tables[j].category()
+		}
+		return tables[i].Name < tables[j].Name
+	})
+	return tables, errors.Join(errs...)
+}
+
+func (t Table) category() string {
+	if t.Category == "" {
+		return TableCategory
+	}
+	return strings.ToUpper(t.Category)
+}
+
+// RollTable rolls the table's dice, or draws a card for a card table, and
+// looks up the row. A Joker drawn for a card table reshuffles the deck and
+// calls a Random Event, as it would for any other draw.
+func RollTable(t Table, deck *Deck, rng *Randomizer) TableResult {
+	rng.beginTrace(t.Name)
+	res := TableResult{TableName: t.Name, Roll: t.Roll}
+	if t.IsCard() {
+		draw := deck.Draw(func() *RandomEventResult {
+			evt := RandomEvent(deck, rng)
+			return &evt
+		})
+		res.Draw = &draw
+		res.Total = int(draw.Card.Rank)
+	} else {
+		for range t.dice.Count {
+			var v int
+			if t.dice.Sides == 6 {
+				v = rng.RollD6()
+			} else {
+				v = rng.RollDN(t.dice.Sides)
+			}
+			res.Rolls = append(res.Rolls, v)
+			res.Total += v
+		}
+		res.Total += t.modifier
+	}
+	for _, r := range t.Rows {
+		if res.Total >= r.Min && res.Total <= r.Max {
+			res.Entry = r.Result
+			break
+		}
+	}
+	res.Trace = rng.endTrace()
+	return res
+}
@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"plain d6", `{"name": "T", "roll": "d6", "rows": ["a", "b", "c", "d", "e", "f"]}`, ""},
+		{"ranges 2d6", `{"name": "T", "roll": "2d6", "rows": [{"range": "2-6", "result": "a"}, {"range": "7", "result": "b"}, {"range": "8-12", "result": "c"}]}`, ""},
+		{"mixed", `{"name": "T", "roll": "d4", "rows": [{"range": "1-2", "result": "a"}, "b", "c"]}`, ""},
+		{"card ranks", `{"name": "T", "roll": "card", "rows": [{"range": "2-10", "result": "a"}, {"range": "J-K", "result": "b"}, {"range": "A", "result": "c"}]}`, ""},
+		{"negative ranges", `{"name": "T", "roll": "d6-3", "rows": [{"range": "-2--1", "result": "a"}, {"range": "0", "result": "b"}, {"range": "1 - 3", "result": "c"}]}`, ""},
+		{"negative single", `{"name": "T", "roll": "d4-2", "rows": [{"range": "-1", "result": "a"}, {"range": "0-2", "result": "b"}]}`, ""},
+		{"too few", `{"name": "T", "roll": "d6", "rows": ["a", "b"]}`, "rows stop at 2, want 6"},
+		{"gap", `{"name": "T", "roll": "d6", "rows": [{"range": "1-2", "result": "a"}, {"range": "4-6", "result": "b"}]}`, "row 2 starts at 4, want 3"},
+		{"past the end", `{"name": "T", "roll": "d4", "rows": [{"range": "1-5", "result": "a"}]}`, "outside 1-4"},
+		{"no name", `{"roll": "d6", "rows": []}`, "no name"},
+		{"exploding", `{"name": "T", "roll": "d6!", "rows": []}`, "plain dice"},
+		{"bad rank", `{"name": "T", "roll": "card", "rows": [{"range": "2-Z", "result": "a"}]}`, "invalid rank"},
+	}
+	for _, tt := range tests {
+		_, err := ParseTable([]byte(tt.json))
+		if tt.err == "" && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestRollTable(t *testing.T) {
+	rng := NewSeededRandomizer(110, 0)
+	tbl, err := ParseTable([]byte(`{"name": "Totals", "roll": "2d6", "rows": ["2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 50 {
+		r := RollTable(tbl, NewDeck(rng), rng)
+		if len(r.Rolls) != 2 || r.Total != r.Rolls[0]+r.Rolls[1] || r.Entry != strconv.Itoa(r.Total) {
+			t.Fatalf("result = %+v", r)
+		}
+		if len(r.Trace) != 2 {
+			t.Errorf("trace = %v", r.Trace)
+		}
+	}
+}
+
+func TestRollCardTable(t *testing.T) {
+	rng := NewSeededRandomizer(111, 0)
+	deck := RestoreDeck(rng, []Card{{Rank: RankQueen, Suit: Hearts}})
+	tbl, err := ParseTable([]byte(`{"name": "Court", "roll": "card", "rows": [{"range": "2-10", "result": "Commoner"}, {"range": "J-K", "result": "Noble"}, {"range": "A", "result": "Monarch"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := RollTable(tbl, deck, rng)
+	if r.Draw == nil || r.Draw.Card.Rank != RankQueen || r.Entry != "Noble" {
+		t.Errorf("result = %+v", r)
+	}
+}
+
+func TestRollCardTableJoker(t *testing.T) {
+	rng := NewSeededRandomizer(111, 0)
+	deck := RestoreDeck(rng, []Card{{Rank: RankJoker}})
+	tbl, err := ParseTable([]byte(`{"name": "Court", "roll": "card", "rows": [{"range": "2-10", "result": "Commoner"}, {"range": "J-K", "result": "Noble"}, {"range": "A", "result": "Monarch"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := RollTable(tbl, deck, rng)
+	if r.Draw == nil || !r.Draw.JokerDrawn || r.Draw.JokerEvent == nil || r.Draw.Card.IsJoker() {
+		t.Errorf("result = %+v, want a Joker with its Random Event, then a card", r)
+	}
+}
+
+func TestLoadTables(t *testing.T) {
+	global, local := t.TempDir(), t.TempDir()
+	write := func(dir, name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(global, "inns.json", `{"name": "Inns", "roll": "d2", "rows": ["Global A", "Global B"]}`)
+	write(global, "coins.json", `{"name": "Coins", "category": "loot", "roll": "d2", "rows": ["Copper", "Silver"]}`)
+	write(local, "inns.json", `{"name": "Inns", "roll": "d2", "rows": ["Local A", "Local B"]}`)
+	write(local, "broken.json", `{"name": "Broken", "roll": "d6", "rows": ["a"]}`)
+	write(local, "notes.yaml", "name: [Notes")
+	write(local, "README.txt", "not a table")
+
+	tables, err := LoadTables(global, local, filepath.Join(local, "missing"))
+	if err == nil || !strings.Contains(err.Error(), "broken.json") || !strings.Contains(err.Error(), "notes.yaml") {
+		t.Errorf("err = %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("loaded %d tables, want 2", len(tables))
+	}
+	if tables[0].Name != "Coins" || tables[0].category() != "LOOT" {
+		t.Errorf("tables should sort by category then name: %+v", tables)
+	}
+	if tables[1].Rows[0].Result != "Local A" {
+		t.Error("an adventure's table should replace the global one")
+	}
+}
+
+func TestLoadTablesYAMLAndTOML(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"weather.yaml": `name: Weather
+roll: d6
+rows:
+  - range: 1-3
+    result: Clear
+  - range: 4
+    result: Rain
+  - range: 5-6
+    result: Storm
+`,
+		"loot.yml": "name: Loot\nroll: d2\nrows: [Rope, 12]\n",
+		"court.toml": `name = "Court"
+roll = "card"
+
+[[rows]]
+range = "2-10"
+result = "Commoner"
+
+[[rows]]
+range = "J-A"
+result = "Noble"
+`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tables, err := LoadTables(dir)
+	if err != nil || len(tables) != 3 {
+		t.Fatalf("loaded %d tables: %v", len(tables), err)
+	}
+	byName := map[string]Table{}
+	for _, tbl := range tables {
+		byName[tbl.Name] = tbl
+	}
+	if rows := byName["Weather"].Rows; len(rows) != 3 || rows[1].Min != 4 || rows[1].Result != "Rain" {
+		t.Errorf("Weather rows = %+v", rows)
+	}
+	if rows := byName["Loot"].Rows; len(rows) != 2 || rows[1].Result != "12" {
+		t.Errorf("Loot rows = %+v", rows)
+	}
+	if rows := byName["Court"].Rows; len(rows) != 2 || rows[1].Min != int(RankJack) {
+		t.Errorf("Court rows = %+v", rows)
+	}
+}
+
+func TestSetTables(t *testing.T) {
+	defer SetTables(nil)
+	tbl, _ := ParseTable([]byte(`{"name": "Tavern Names", "roll": "d2", "rows": ["The Red Lion", "The Grey Goose"]}`))
+	tbl.Source = "tavern.json"
+	SetTables([]Table{tbl})
+	g, _, err := LookupCommand(TableCommand, []string{"tavern-names", "3"})
+	if err != nil || g.Name != "Tavern Names" || g.Category != TableCategory || g.Entry != EntryGenerator {
+		t.Fatalf("LookupCommand(table tavern-names) = %+v, %v", g, err)
+	}
+	rng := NewSeededRandomizer(112, 0)
+	res, err := g.Run(&Session{Rng: rng, Deck: NewDeck(rng)}, nil)
+	if r, ok := res.(TableResult); err != nil || !ok || r.Entry == "" {
+		t.Errorf("Run = %+v, %v", res, err)
+	}
+	SetLikelihoods(DefaultLikelihoods())
+	if _, ok := LookupGenerator(g.ID); !ok {
+		t.Error("rebuilding the ladder should keep the tables")
+	}
+}
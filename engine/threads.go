@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Thread is a plot thread the adventure follows, until it is closed.
+type Thread struct {
+	Name   string `json:"name"`
+	Closed bool   `json:"closed,omitempty"`
+}
+
+// plotArcRows are the OPSE rows that point at a plot arc: Topic Focus 10,
+// Pacing Move 5 and Plot Hook reward 5.
+var plotArcRows = []tableRow{{"Topic Focus", int(RankTen)}, {"Pacing Moves", 5}, {"Rewards", 5}}
+
+// OpenThreads lists the names of the threads not yet closed.
+func OpenThreads(threads []Thread) []string {
+	var open []string
+	for _, t := range threads {
+		if !t.Closed {
+			open = append(open, t.Name)
+		}
+	}
+	return open
+}
+
+// FindThread returns the index of the thread called name, ignoring case,
+// or -1.
+func FindThread(threads []Thread, name string) int {
+	return slices.IndexFunc(threads, func(t Thread) bool { return strings.EqualFold(t.Name, name) })
+}
+
+// AddThread opens a thread, or reopens a closed one of the same name.
+func AddThread(threads []Thread, name string) ([]Thread, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return threads, fmt.Errorf("a thread needs a name")
+	}
+	i := FindThread(threads, name)
+	if i < 0 {
+		return append(threads, Thread{Name: name}), nil
+	}
+	if !threads[i].Closed {
+		return threads, fmt.Errorf("%q is already open", threads[i].Name)
+	}
+	threads[i].Closed = false
+	return threads, nil
+}
+
+// CloseThread closes the thread called name.
+func CloseThread(threads []Thread, name string) error {
+	i := FindThread(threads, strings.TrimSpace(name))
+	if i < 0 || threads[i].Closed {
+		return fmt.Errorf("no open thread %q", name)
+	}
+	threads[i].Closed = true
+	return nil
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThreadList(t *testing.T) {
+	threads, _ := AddThread(nil, "The Missing Miller")
+	threads, _ = AddThread(threads, "Cult of the Eye")
+	if _, err := AddThread(threads, "the missing miller"); err == nil {
+		t.Error("adding an open thread twice should be an error")
+	}
+	if err := CloseThread(threads, "cult of the eye"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CloseThread(threads, "Cult of the Eye"); err == nil {
+		t.Error("closing a closed thread should be an error")
+	}
+	if open := OpenThreads(threads); len(open) != 1 || open[0] != "The Missing Miller" {
+		t.Errorf("open = %q", open)
+	}
+	threads, err := AddThread(threads, "Cult of the Eye")
+	if err != nil || len(threads) != 2 || threads[1].Closed {
+		t.Errorf("re-adding a closed thread should reopen it: %v, %+v", err, threads)
+	}
+}
+
+func TestPacingMoveNamesThread(t *testing.T) {
+	rng := NewSeededRandomizer(23, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Threads: []string{"The Missing Miller"}}
+	g, _ := LookupGenerator("pacing_move")
+	named := false
+	for range 200 {
+		res, err := g.Generate(s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := res.(PacingMoveResult)
+		if r.Roll == 5 {
+			if r.Result != "Advance a Plot → The Missing Miller" {
+				t.Fatalf("result = %q, want the thread named", r.Result)
+			}
+			named = true
+		} else if strings.Contains(r.Result, "→") {
+			t.Errorf("roll %d named a thread: %q", r.Roll, r.Result)
+		}
+	}
+	if !named {
+		t.Error("never rolled Advance a Plot")
+	}
+}
+
+func TestThreadNamedByRowNotText(t *testing.T) {
+	if err := SetOverrides(map[string][]string{"pacing-moves": {
+		"Advance a Plot", "Reveal a New Detail", "An NPC Takes Action", "Advance a Threat", "Push the Story", "Add a RANDOM EVENT to the scene",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetOverrides(nil) })
+	rng := NewSeededRandomizer(26, 0)
+	s := &Session{Rng: rng, Deck: NewDeck(rng), Threads: []string{"The Missing Miller"}}
+	g, _ := LookupGenerator("pacing_move")
+	seen := map[int]bool{}
+	for range 200 {
+		res, err := g.Generate(s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := res.(PacingMoveResult)
+		seen[r.Roll] = true
+		want := map[int]string{1: "Advance a Plot", 5: "Push the Story → The Missing Miller"}[r.Roll]
+		if want != "" && r.Result != want {
+			t.Errorf("roll %d = %q, want %q", r.Roll, r.Result, want)
+		}
+	}
+	if !seen[1] || !seen[5] {
+		t.Errorf("rolled %v", seen)
+	}
+}
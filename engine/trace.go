@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+type StepKind int
+
+const (
+	StepDie     StepKind = iota // a die roll: Sides and Value; no Sides for a Fudge die
+	StepCoin                    // a coin flip: Value 1 for heads, 0 for tails
+	StepCard                    // a card drawn or rolled: Card
+	StepShuffle                 // the deck was reshuffled: Label says why
+	StepScope                   // a nested generator: Label names it
+)
+
+// TraceStep is one die, coin or card consumed while producing a result.
+// Steps nested under a scope, or under a d6 made from cards or a card made
+// from dice, have a Depth one greater than their parent.
+type TraceStep struct {
+	Kind  StepKind
+	Depth int
+	Sides int
+	Value int
+	Card  Card
+	Label string
+}
+
+func (s TraceStep) String() string {
+	switch s.Kind {
+	case StepDie:
+		if s.Sides == 0 {
+			return "dF " + FudgeFace(s.Value)
+		}
+		return fmt.Sprintf("d%d %d", s.Sides, s.Value)
+	case StepCoin:
+		if s.Value == 1 {
+			return "coin Heads"
+		}
+		return "coin Tails"
+	case StepCard:
+		return s.Card.String()
+	case StepShuffle:
+		if s.Label != "" {
+			return "shuffle (" + s.Label + ")"
+		}
+		return "shuffle"
+	default:
+		return s.Label
+	}
+}
+
+// Trace is every step behind a result, in the order it was consumed.
+type Trace []TraceStep
+
+// String renders the trace on one line, with nested steps in brackets:
+// "d6 5, d6 6, Random Event [Q♠, 7♦]".
+func (t Trace) String() string {
+	var b strings.Builder
+	depth := 0
+	for i, s := range t {
+		for ; depth > s.Depth; depth-- {
+			b.WriteString("]")
+		}
+		if s.Depth > depth {
+			b.WriteString(" [")
+			depth = s.Depth
+		} else if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(s.String())
+	}
+	b.WriteString(strings.Repeat("]", depth))
+	return b.String()
+}
+
+// Lines renders one step per line, indented two spaces per level.
+func (t Trace) Lines() []string {
+	lines := make([]string, len(t))
+	for i, s := range t {
+		lines[i] = strings.Repeat("  ", s.Depth) + s.String()
+	}
+	return lines
+}
+
+// tracer collects steps while at least one trace is open. Each open trace
+// remembers where its steps start, so nested generators get their own
+// trace and also appear, as a scope, in the trace that called them.
+type tracer struct {
+	steps []TraceStep
+	marks []int
+}
+
+// beginTrace opens a trace. When another trace is already open, label is
+// recorded there as a scope holding the new trace's steps.
+func (r *Randomizer) beginTrace(label string) {
+	if len(r.trace.marks) > 0 && label != "" {
+		r.record(TraceStep{Kind: StepScope, Label: label})
+	}
+	r.trace.marks = append(r.trace.marks, len(r.trace.steps))
+}
+
+// endTrace closes the innermost trace and returns its steps.
+func (r *Randomizer) endTrace() Trace {
+	t := &r.trace
+	mark := t.marks[len(t.marks)-1]
+	t.marks = t.marks[:len(t.marks)-1]
+	out := append(Trace(nil), t.steps[mark:]...)
+	for i := range out {
+		out[i].Depth -= len(t.marks)
+	}
+	if len(t.marks) == 0 {
+		t.steps = t.steps[:0]
+	}
+	return out
+}
+
+func (r *Randomizer) record(s TraceStep) {
+	if len(r.trace.marks) == 0 {
+		return
+	}
+	s.Depth = len(r.trace.marks) - 1
+	r.trace.steps = append(r.trace.steps, s)
+}
+
+// recordSourced runs fn and records step ahead of anything fn consumed,
+// which is nested one level below it.
+func (r *Randomizer) recordSourced(fn func() TraceStep) {
+	if len(r.trace.marks) == 0 {
+		fn()
+		return
+	}
+	at := len(r.trace.steps)
+	r.beginTrace("")
+	s := fn()
+	r.endTrace()
+	s.Depth = len(r.trace.marks) - 1
+	r.trace.steps = append(r.trace.steps, TraceStep{})
+	copy(r.trace.steps[at+1:], r.trace.steps[at:])
+	r.trace.steps[at] = s
+}
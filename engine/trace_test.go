@@ -0,0 +1,122 @@
+package engine
+
+import "testing"
+
+func TestTraceOracleYesNo(t *testing.T) {
+	rng := NewSeededRandomizer(90, 0)
+	r := OracleYesNo(rng, rung("Even"), 0)
+	want := Trace{
+		{Kind: StepDie, Sides: 6, Value: r.AnswerRoll},
+		{Kind: StepDie, Sides: 6, Value: r.ModRoll},
+	}
+	if len(r.Trace) != len(want) {
+		t.Fatalf("trace = %v, want %v", r.Trace, want)
+	}
+	for i := range want {
+		if r.Trace[i] != want[i] {
+			t.Errorf("step %d = %+v, want %+v", i, r.Trace[i], want[i])
+		}
+	}
+}
+
+func TestTraceNestsGenerators(t *testing.T) {
+	rng := NewSeededRandomizer(91, 0)
+	r := GenericGenerator(NewDeck(rng), rng)
+	if got := len(r.Trace); got != 6 {
+		t.Fatalf("trace has %d steps, want 6: %v", got, r.Trace)
+	}
+	want := []struct {
+		kind  StepKind
+		depth int
+	}{
+		{StepScope, 0}, {StepCard, 1},
+		{StepScope, 0}, {StepCard, 1},
+		{StepScope, 0}, {StepDie, 1},
+	}
+	for i, w := range want {
+		if s := r.Trace[i]; s.Kind != w.kind || s.Depth != w.depth {
+			t.Errorf("step %d = %+v, want kind %d depth %d", i, s, w.kind, w.depth)
+		}
+	}
+	if r.Trace[1].Card != r.Action.Draw.Card {
+		t.Errorf("traced %s, drew %s", r.Trace[1].Card, r.Action.Draw.Card)
+	}
+	if len(r.Action.Trace) != 1 || r.Action.Trace[0].Depth != 0 {
+		t.Errorf("nested result trace = %+v", r.Action.Trace)
+	}
+}
+
+func TestTraceJoker(t *testing.T) {
+	rng := NewSeededRandomizer(92, 0)
+	deck := RestoreDeck(rng, []Card{{Rank: RankJoker}})
+	r := ActionFocus(deck)
+	if len(r.Trace) != 3 {
+		t.Fatalf("trace = %v", r.Trace)
+	}
+	if !r.Trace[0].Card.IsJoker() || r.Trace[1].Kind != StepShuffle || r.Trace[2].Card != r.Draw.Card {
+		t.Errorf("trace = %v", r.Trace)
+	}
+}
+
+func TestTraceJokerEvent(t *testing.T) {
+	rng := NewSeededRandomizer(93, 0)
+	deck := RestoreDeck(rng, []Card{{Rank: RankJoker}})
+	r := RandomEvent(deck, rng)
+	if r.Trace[2].Kind != StepScope || r.Trace[2].Label != "Random Event" {
+		t.Fatalf("Joker should trace a nested random event: %v", r.Trace)
+	}
+	if r.Trace[3].Depth != 1 {
+		t.Errorf("nested event steps should be indented: %+v", r.Trace[3])
+	}
+}
+
+func TestTraceCardD6(t *testing.T) {
+	rng := NewSeededRandomizer(94, 0)
+	deck := RestoreDeck(rng, []Card{{Rank: RankAce, Suit: Spades}, {Rank: RankNine, Suit: Hearts}})
+	rng.SetD6Source(NewCardD6(deck))
+	r := FailureMove(rng)
+	want := "d6 4 [A♠, 9♥]"
+	if got := r.Trace.String(); got != want {
+		t.Errorf("trace = %q, want %q", got, want)
+	}
+}
+
+func TestTraceDiceCards(t *testing.T) {
+	rng := NewSeededRandomizer(95, 0)
+	deck := NewDeck(rng)
+	deck.SetSource(NewDiceCards(rng))
+	r := TopicFocus(deck)
+	if r.Trace[0].Card != r.Draw.Card {
+		t.Fatalf("trace = %v", r.Trace)
+	}
+	if r.Trace[1].Sides != 12 || r.Trace[1].Depth != 1 {
+		t.Errorf("rank die should be nested under the card: %+v", r.Trace[1])
+	}
+}
+
+func TestTraceOnlyWhileGenerating(t *testing.T) {
+	rng := NewSeededRandomizer(96, 0)
+	rng.RollD6()
+	r := OracleHow(rng)
+	if len(r.Trace) != 1 {
+		t.Errorf("trace = %v", r.Trace)
+	}
+	if len(rng.trace.steps) != 0 || len(rng.trace.marks) != 0 {
+		t.Error("tracer should be empty between generators")
+	}
+}
+
+func TestTraceString(t *testing.T) {
+	tr := Trace{
+		{Kind: StepDie, Sides: 6, Value: 6},
+		{Kind: StepScope, Label: "Random Event"},
+		{Kind: StepCard, Depth: 1, Card: Card{Rank: RankJoker}},
+		{Kind: StepShuffle, Depth: 1, Label: "Joker"},
+		{Kind: StepCard, Depth: 1, Card: Card{Rank: RankQueen, Suit: Spades}},
+		{Kind: StepCoin, Value: 1},
+	}
+	want := "d6 6, Random Event [Joker, shuffle (Joker), Q♠], coin Heads"
+	if got := tr.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -12,6 +12,11 @@ func NewUtilityDeck(rng *Randomizer, jokers bool) *UtilityDeck {
 	return d
 }
 
+// RestoreUtilityDeck rebuilds a utility deck with the given cards remaining.
+func RestoreUtilityDeck(rng *Randomizer, jokers bool, cards []Card) *UtilityDeck {
+	return &UtilityDeck{rng: rng, includeJokers: jokers, cards: append([]Card(nil), cards...)}
+}
+
 func (d *UtilityDeck) Shuffle() {
 	d.cards = make([]Card, 0, 54)
 	for _, s := range []Suit{Clubs, Diamonds, Spades, Hearts} {
@@ -38,7 +43,17 @@ func (d *UtilityDeck) Draw(count int) CardDrawResult {
 	drawn := make([]Card, count)
 	copy(drawn, d.cards[:count])
 	d.cards = d.cards[count:]
-	return CardDrawResult{Cards: drawn, Remaining: len(d.cards)}
+	d.rng.beginTrace("Card Draw")
+	for _, c := range drawn {
+		d.rng.record(TraceStep{Kind: StepCard, Card: c})
+	}
+	return CardDrawResult{Cards: drawn, Remaining: len(d.cards), Trace: d.rng.endTrace()}
 }
 
 func (d *UtilityDeck) Remaining() int { return len(d.cards) }
+
+// Cards returns a copy of the remaining cards in draw order.
+func (d *UtilityDeck) Cards() []Card { return append([]Card(nil), d.cards...) }
+
+// Jokers reports whether the deck is shuffled with Jokers included.
+func (d *UtilityDeck) Jokers() bool { return d.includeJokers }
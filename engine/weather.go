@@ -15,9 +15,11 @@ var winds = [7]string{
 }
 
 func RandomWeather(rng *Randomizer) WeatherResult {
+	rng.beginTrace("Weather")
 	return WeatherResult{
-		Condition:   conditions[rng.Intn(len(conditions))],
+		Condition:   conditions[rng.RollDN(len(conditions))-1],
 		Temperature: temps[rng.RollD6()],
 		Wind:        winds[rng.RollD6()],
+		Trace:       rng.endTrace(),
 	}
 }
@@ -2,7 +2,10 @@ package journal
 
 import (
 	"os"
+	"slices"
 	"time"
+
+	"opse/engine"
 )
 
 type EntryType string
@@ -20,6 +23,16 @@ type Entry struct {
 	Type      EntryType
 	Label     string
 	Markdown  string
+	Question  string // the question put to the oracle, for oracle entries
+	Scene     *Scene // the scene a Set the Scene entry opens
+}
+
+// Scene is a numbered scene of the adventure, with an optional title and
+// goal.
+type Scene struct {
+	Number int
+	Title  string
+	Goal   string
 }
 
 type Journal struct {
@@ -27,7 +40,34 @@ type Journal struct {
 	CreatedAt time.Time
 	Entries   []Entry
 	FilePath  string
-	dirty     bool
+	// State holds the deck and RNG snapshot written with the journal so a
+	// resumed adventure continues the same shuffle. Nil for a new journal.
+	State *engine.EngineState
+	// HouseRules names the built-in tables replaced by house rules while
+	// the adventure was played.
+	HouseRules []string
+	// System is the ID of the game system preset pinned to the adventure,
+	// or empty to use the configured one.
+	System string
+	// Sheets are the characters' stats, read by references such as @str.
+	Sheets []engine.CharacterSheet
+	// Threads are the adventure's plot threads, open and closed.
+	Threads []engine.Thread
+	// Roster holds the NPCs kept as characters.
+	Roster []engine.RosterNPC
+	// Factions are the groups that advance off-screen.
+	Factions []engine.Faction
+	// Start is the Adventure Start section, or nil if the adventure began
+	// without one.
+	Start *Start
+	dirty bool
+}
+
+// Start opens an adventure, as the rules' How to Play has it: the starting
+// characters, then a Plot Hook, a Random Event and the first scene.
+type Start struct {
+	Characters []string
+	Entries    []Entry
 }
 
 func New(title, filePath string) *Journal {
@@ -46,6 +86,177 @@ func (j *Journal) AddEntry(e Entry) {
 	j.dirty = true
 }
 
+// AmendEntry replaces the markdown of the entry at i, such as a check
+// that has since taken its Failure Move.
+func (j *Journal) AmendEntry(i int, md string) {
+	j.Entries[i].Markdown = md
+	j.dirty = true
+}
+
+// StampHouseRules adds the overridden tables to the journal's house rules.
+// It marks the journal dirty only when one is new.
+func (j *Journal) StampHouseRules(overrides []engine.TableOverride) {
+	for _, o := range overrides {
+		if !slices.Contains(j.HouseRules, o.Name) {
+			j.HouseRules = append(j.HouseRules, o.Name)
+			j.dirty = true
+		}
+	}
+}
+
+// PinSystem pins a game system preset to the journal, or unpins it for an
+// empty id.
+func (j *Journal) PinSystem(id string) {
+	if j.System != id {
+		j.System = id
+		j.dirty = true
+	}
+}
+
+// Character is the speaker of the latest character entry, whose sheet a
+// bare @stat reads, or "" if there is none.
+func (j *Journal) Character() string {
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		if e := j.Entries[i]; e.Type == EntryNarrative && e.Label != "" {
+			return e.Label
+		}
+	}
+	return ""
+}
+
+// SetStart writes the Adventure Start section, replacing any before it.
+func (j *Journal) SetStart(s Start) {
+	j.Start = &s
+	j.dirty = true
+}
+
+// HasScene reports whether the start set the adventure's first scene.
+func (s *Start) HasScene() bool {
+	return slices.ContainsFunc(s.Entries, func(e Entry) bool { return e.Type == EntryScene })
+}
+
+// Scenes lists the adventure's scenes in order. The scene set by the
+// Adventure Start is scene 1.
+func (j *Journal) Scenes() []Scene {
+	var scenes []Scene
+	if j.Start != nil && j.Start.HasScene() {
+		scenes = append(scenes, Scene{Number: 1, Title: "Adventure Start"})
+	}
+	for _, e := range j.Entries {
+		if e.Scene != nil {
+			scenes = append(scenes, *e.Scene)
+		}
+	}
+	return scenes
+}
+
+// NextScene is the number the next scene opened gets.
+func (j *Journal) NextScene() int {
+	scenes := j.Scenes()
+	if len(scenes) == 0 {
+		return 1
+	}
+	return scenes[len(scenes)-1].Number + 1
+}
+
+// AddThread opens a plot thread, or reopens a closed one.
+func (j *Journal) AddThread(name string) error {
+	threads, err := engine.AddThread(j.Threads, name)
+	if err != nil {
+		return err
+	}
+	j.Threads = threads
+	j.dirty = true
+	return nil
+}
+
+// CloseThread closes an open plot thread.
+func (j *Journal) CloseThread(name string) error {
+	if err := engine.CloseThread(j.Threads, name); err != nil {
+		return err
+	}
+	j.dirty = true
+	return nil
+}
+
+// AddNPC puts an NPC on the roster.
+func (j *Journal) AddNPC(n engine.RosterNPC) error {
+	roster, err := engine.AddNPC(j.Roster, n)
+	if err != nil {
+		return err
+	}
+	j.Roster = roster
+	j.dirty = true
+	return nil
+}
+
+// RemoveNPC takes an NPC off the roster.
+func (j *Journal) RemoveNPC(name string) error {
+	roster, err := engine.RemoveNPC(j.Roster, name)
+	if err != nil {
+		return err
+	}
+	j.Roster = roster
+	j.dirty = true
+	return nil
+}
+
+// AddFaction adds a faction with an empty clock of the given size.
+func (j *Journal) AddFaction(name, goal string, clock int) error {
+	factions, err := engine.AddFaction(j.Factions, name, goal, clock)
+	if err != nil {
+		return err
+	}
+	j.Factions = factions
+	j.dirty = true
+	return nil
+}
+
+// RemoveFaction takes a faction off the list.
+func (j *Journal) RemoveFaction(name string) error {
+	factions, err := engine.RemoveFaction(j.Factions, name)
+	if err != nil {
+		return err
+	}
+	j.Factions = factions
+	j.dirty = true
+	return nil
+}
+
+// RelateFactions sets the stance between two factions.
+func (j *Journal) RelateFactions(a, b, stance string) error {
+	if err := engine.RelateFactions(j.Factions, a, b, stance); err != nil {
+		return err
+	}
+	j.dirty = true
+	return nil
+}
+
+// AdvanceFaction fills (or empties) segments of a faction's clock.
+func (j *Journal) AdvanceFaction(name string, n int) error {
+	if err := engine.AdvanceFaction(j.Factions, name, n); err != nil {
+		return err
+	}
+	j.dirty = true
+	return nil
+}
+
+// FactionTurn advances the factions off-screen.
+func (j *Journal) FactionTurn(deck *engine.Deck, rng *engine.Randomizer) engine.FactionTurnResult {
+	j.dirty = true
+	return engine.FactionTurn(j.Factions, deck, rng)
+}
+
+// SetSheet adds or replaces the character sheet with the same name.
+func (j *Journal) SetSheet(c engine.CharacterSheet) {
+	if i := engine.FindSheet(j.Sheets, c.Name); i >= 0 {
+		j.Sheets[i] = c
+	} else {
+		j.Sheets = append(j.Sheets, c)
+	}
+	j.dirty = true
+}
+
 func (j *Journal) Save() error {
 	if !j.dirty {
 		return nil
@@ -3,7 +3,11 @@ package journal
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"opse/engine"
 )
 
 func TestNewJournal(t *testing.T) {
@@ -83,6 +87,105 @@ func TestRoundTrip(t *testing.T) {
 	}
 }
 
+func TestRoundTripQuestion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	r := engine.OracleHowResult{Question: "How deep is the pit?", Roll: 6, Result: "Very much"}
+	j.AddEntry(Entry{Type: EntryOracle, Markdown: RenderOracleHow(r), Question: r.Question})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Entries) != 1 {
+		t.Fatalf("loaded %d entries, want 1", len(loaded.Entries))
+	}
+	if e := loaded.Entries[0]; e.Type != EntryOracle || e.Question != r.Question {
+		t.Errorf("entry = %+v, want oracle entry asking %q", e, r.Question)
+	}
+}
+
+func TestRoundTripHouseRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	j.StampHouseRules([]engine.TableOverride{{Name: "Pacing Moves"}, {Name: "Action Focus"}})
+	j.StampHouseRules([]engine.TableOverride{{Name: "Pacing Moves"}})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "*House rules: Pacing Moves, Action Focus*") {
+		t.Errorf("header should name the house rules:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(loaded.HouseRules, ", "); got != "Pacing Moves, Action Focus" {
+		t.Errorf("house rules = %q", got)
+	}
+	loaded.StampHouseRules([]engine.TableOverride{{Name: "Action Focus"}})
+	if loaded.IsDirty() {
+		t.Error("a known house rule should not dirty the journal")
+	}
+}
+
+func TestRoundTripSystem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	j.PinSystem("fitd")
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "*System: Forged in the Dark*") {
+		t.Errorf("header should name the system:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.System != "fitd" {
+		t.Errorf("system = %q, want fitd", loaded.System)
+	}
+	loaded.PinSystem("fitd")
+	if loaded.IsDirty() {
+		t.Error("pinning the same system should not dirty the journal")
+	}
+}
+
+func TestRoundTripSheets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	j.SetSheet(engine.CharacterSheet{Name: "Elara", Stats: []engine.Stat{{Name: "str", Value: 2}}})
+	j.SetSheet(engine.CharacterSheet{Name: "elara", Stats: []engine.Stat{{Name: "str", Value: 3}}})
+	j.AddEntry(Entry{Type: EntryNarrative, Label: "Elara", Markdown: "I lift the gate."})
+	j.AddEntry(Entry{Type: EntryNarrative, Markdown: "It groans."})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Sheets) != 1 || loaded.Sheets[0].Stats[0].Value != 3 {
+		t.Errorf("sheets = %+v, want Elara's replaced sheet", loaded.Sheets)
+	}
+	if got := loaded.Character(); got != "Elara" {
+		t.Errorf("character = %q, want the latest /char speaker", got)
+	}
+}
+
 func TestRoundTripCharacterVoice(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "adventure.md")
@@ -108,3 +211,198 @@ func TestRoundTripCharacterVoice(t *testing.T) {
 		t.Errorf("entry[1] markdown = %q", loaded.Entries[1].Markdown)
 	}
 }
+
+func TestRoundTripEngineState(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "adventure.md")
+
+	rng := engine.NewSeededRandomizer(3, 4)
+	deck := engine.NewDeck(rng)
+	deck.Draw(nil)
+	j := New("State Test", path)
+	j.State = engine.CaptureState(rng, deck, engine.NewUtilityDeck(rng, false))
+	j.AddEntry(Entry{Type: EntryNarrative, Markdown: "Hello"})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.State == nil {
+		t.Fatal("engine state not loaded")
+	}
+	if len(loaded.State.Deck) != deck.Remaining() {
+		t.Errorf("loaded %d deck cards, want %d", len(loaded.State.Deck), deck.Remaining())
+	}
+	if loaded.State.Deck[0] != deck.Cards()[0] {
+		t.Errorf("top card %s, want %s", loaded.State.Deck[0], deck.Cards()[0])
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].Markdown != "Hello" {
+		t.Errorf("entries not preserved: %+v", loaded.Entries)
+	}
+}
+
+func TestRoundTripStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	j.SetStart(Start{
+		Characters: []string{"Elara", "Sir Beans"},
+		Entries: []Entry{
+			{Type: EntryGenerator, Label: "Plot Hook", Markdown: "> **Plot Hook**\n> - **Objective:** Rescue someone"},
+			{Type: EntryScene, Label: "Set the Scene", Markdown: "> **Set the Scene:** An abandoned mill"},
+		},
+	})
+	j.AddEntry(Entry{Type: EntryNarrative, Markdown: "We set out at dawn."})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := loaded.Start
+	if s == nil || len(s.Characters) != 2 || s.Characters[1] != "Sir Beans" {
+		t.Fatalf("start = %+v", s)
+	}
+	if len(s.Entries) != 2 || s.Entries[1].Type != EntryScene || s.Entries[1].Markdown != j.Start.Entries[1].Markdown {
+		t.Errorf("start entries = %+v", s.Entries)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].Markdown != "We set out at dawn." {
+		t.Errorf("entries = %+v, want only the narrative after the start", loaded.Entries)
+	}
+}
+
+func TestRoundTripScenes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	j.SetStart(Start{Entries: []Entry{{Type: EntryScene, Markdown: "> **Set the Scene**"}}})
+	j.AddEntry(Entry{Type: EntryNarrative, Markdown: "We leave the mill."})
+	j.AddEntry(Entry{Type: EntryScene, Markdown: "> **Set the Scene**", Scene: &Scene{Number: j.NextScene(), Title: "The Old Road", Goal: "Reach the pass"}})
+	j.AddEntry(Entry{Type: EntryNarrative, Markdown: "Rain."})
+	j.AddEntry(Entry{Type: EntryScene, Markdown: "> **Set the Scene**", Scene: &Scene{Number: j.NextScene()}})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Scene{{1, "Adventure Start", ""}, {2, "The Old Road", "Reach the pass"}, {3, "", ""}}
+	got := loaded.Scenes()
+	if len(got) != len(want) {
+		t.Fatalf("scenes = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("scene %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(loaded.Entries) != 4 || loaded.Entries[0].Markdown != "We leave the mill." || loaded.Entries[1].Scene == nil {
+		t.Errorf("entries = %+v", loaded.Entries)
+	}
+	if loaded.NextScene() != 4 {
+		t.Errorf("next scene = %d", loaded.NextScene())
+	}
+}
+
+func TestRoundTripThreads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	j.AddThread("The Missing Miller")
+	j.AddThread("Cult of the Eye")
+	if err := j.CloseThread("cult of the eye"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []engine.Thread{{Name: "The Missing Miller"}, {Name: "Cult of the Eye", Closed: true}}
+	if len(loaded.Threads) != 2 || loaded.Threads[0] != want[0] || loaded.Threads[1] != want[1] {
+		t.Errorf("threads = %+v, want %+v", loaded.Threads, want)
+	}
+}
+
+func TestRoundTripRoster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	vex := engine.RosterNPC{Name: "Vex", Identity: "Outlaw", Goal: "Revenge", Feature: "Scar — Old", Attitude: "Hostile",
+		Portrait: engine.PortraitParams{HairStyle: 2}}
+	if err := j.AddNPC(vex); err != nil {
+		t.Fatal(err)
+	}
+	j.AddNPC(engine.RosterNPC{Name: "Bram"})
+	if err := j.RemoveNPC("bram"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Roster) != 1 || loaded.Roster[0] != vex {
+		t.Errorf("roster = %+v, want %+v", loaded.Roster, vex)
+	}
+}
+
+func TestRoundTripFactions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test Adventure", path)
+	j.AddFaction("The Red Hand", "seize the harbor", 0)
+	j.AddFaction("Iron Guild", "", 0)
+	if err := j.RelateFactions("The Red Hand", "Iron Guild", "rivals"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.AdvanceFaction("the red hand", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Factions, j.Factions) {
+		t.Errorf("factions = %+v, want %+v", loaded.Factions, j.Factions)
+	}
+}
+
+func TestRoundTripDashesInHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+
+	j := New("Test --- Adventure", path)
+	j.AddThread("Before --- After")
+	j.AddEntry(Entry{Type: EntryNarrative, Markdown: "We set out."})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Threads) != 1 || loaded.Threads[0].Name != "Before --- After" {
+		t.Errorf("threads = %+v", loaded.Threads)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].Markdown != "We set out." {
+		t.Errorf("entries = %+v, want the one narrative entry", loaded.Entries)
+	}
+}
@@ -1,14 +1,22 @@
 package journal
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
+
+	"opse/engine"
 )
 
-var timestampRe = regexp.MustCompile(`^\*(\d{2}:\d{2}) — (.+)\*$`)
+var (
+	timestampRe    = regexp.MustCompile(`^\*(\d{2}:\d{2}) — (.+)\*$`)
+	sceneHeadingRe = regexp.MustCompile(`^## Scene (\d+)(?:: (.*))?$`)
+	separatorRe    = regexp.MustCompile(`(?m)^---\r?$`)
+)
 
 func Load(filePath string) (*Journal, error) {
 	data, err := os.ReadFile(filePath)
@@ -24,8 +32,18 @@ func Load(filePath string) (*Journal, error) {
 		CreatedAt: createdAt,
 		FilePath:  filePath,
 	}
+	j.HouseRules = extractHouseRules(content)
+	if data := extractData(content); data != nil {
+		j.State = data.Engine
+		j.System = data.System
+		j.Sheets = data.Sheets
+		j.Threads = data.Threads
+		j.Roster = data.Roster
+		j.Factions = data.Factions
+	}
 
 	body := extractBody(content)
+	j.Start, body = extractStart(body)
 	j.Entries = parseEntries(body)
 	return j, nil
 }
@@ -52,12 +70,72 @@ func extractCreatedAt(md string) time.Time {
 	return time.Now()
 }
 
+func extractHouseRules(md string) []string {
+	for _, line := range strings.Split(md, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "---" {
+			break
+		}
+		if names, ok := strings.CutPrefix(line, houseRulesPrefix); ok {
+			return strings.Split(strings.TrimSuffix(names, "*"), ", ")
+		}
+	}
+	return nil
+}
+
+// extractData decodes the opse-data comment written by Render, if present.
+// A damaged comment is ignored so the narrative still loads.
+func extractData(md string) *journalData {
+	for _, line := range strings.Split(md, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "---" {
+			break
+		}
+		raw, ok := strings.CutPrefix(line, dataPrefix)
+		if !ok {
+			continue
+		}
+		raw, ok = strings.CutSuffix(raw, dataSuffix)
+		if !ok {
+			return nil
+		}
+		var data journalData
+		if json.Unmarshal([]byte(raw), &data) != nil {
+			return nil
+		}
+		return &data
+	}
+	return nil
+}
+
+// extractBody returns what follows the header, which ends at the first
+// line that is only "---".
 func extractBody(md string) string {
-	_, after, found := strings.Cut(md, "---")
-	if !found {
+	loc := separatorRe.FindStringIndex(md)
+	if loc == nil {
 		return md
 	}
-	return strings.TrimLeft(after, "\n")
+	return strings.TrimLeft(md[loc[1]:], "\n")
+}
+
+// extractStart reads the Adventure Start section written by renderStart
+// from the top of the body, returning the rest of the body after it.
+func extractStart(body string) (*Start, string) {
+	section, ok := strings.CutPrefix(body, startHeading+"\n")
+	if !ok {
+		return nil, body
+	}
+	section, rest, _ := strings.Cut(section, "\n---\n")
+	s := &Start{}
+	for _, block := range strings.Split(section, "\n\n") {
+		block = strings.TrimSpace(block)
+		if names, ok := strings.CutPrefix(block, startCharacters); ok {
+			s.Characters = strings.Split(names, ", ")
+		} else if block != "" {
+			s.Entries = append(s.Entries, Entry{Type: classifyBlockquote(block), Markdown: block})
+		}
+	}
+	return s, strings.TrimLeft(rest, "\n")
 }
 
 func parseEntries(body string) []Entry {
@@ -66,6 +144,8 @@ func parseEntries(body string) []Entry {
 	var currentTs time.Time
 	var currentSource string
 	var currentLines []string
+	var scene *Scene      // opened by a heading, for the next entry
+	afterHeading := false // a goal may follow the heading
 
 	flush := func() {
 		text := strings.TrimSpace(strings.Join(currentLines, "\n"))
@@ -85,16 +165,37 @@ func parseEntries(body string) []Entry {
 		if entryType == EntryNarrative && label != "" {
 			text = stripCharMarkdown(text, label)
 		}
-		entries = append(entries, Entry{
+		entry := Entry{
 			Timestamp: currentTs,
 			Type:      entryType,
 			Label:     label,
 			Markdown:  text,
-		})
+		}
+		if entryType == EntryOracle {
+			entry.Question = extractQuestion(text)
+		}
+		entry.Scene, scene = scene, nil
+		entries = append(entries, entry)
 	}
 
 	for _, line := range lines {
-		if m := timestampRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+		trimmed := strings.TrimSpace(line)
+		if m := sceneHeadingRe.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			currentLines = nil
+			n, _ := strconv.Atoi(m[1])
+			scene = &Scene{Number: n, Title: m[2]}
+			afterHeading = true
+			continue
+		}
+		if goal, ok := strings.CutPrefix(trimmed, sceneGoalPrefix); ok && afterHeading {
+			scene.Goal = strings.TrimSuffix(goal, "*")
+			continue
+		}
+		if trimmed != "" {
+			afterHeading = false
+		}
+		if m := timestampRe.FindStringSubmatch(trimmed); m != nil {
 			flush()
 			currentLines = nil
 			t, _ := time.Parse("15:04", m[1])
@@ -109,6 +210,16 @@ func parseEntries(body string) []Entry {
 	return entries
 }
 
+// extractQuestion returns the question recorded by renderQA, if any.
+func extractQuestion(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if q, ok := strings.CutPrefix(line, qaQuestionPrefix); ok {
+			return strings.TrimSpace(q)
+		}
+	}
+	return ""
+}
+
 // stripCharMarkdown removes portrait image tags and bold name prefixes
 // added by Render(), so the Markdown field stays as plain dialogue text.
 func stripCharMarkdown(text, label string) string {
@@ -124,19 +235,18 @@ func stripCharMarkdown(text, label string) string {
 	return text
 }
 
+// classifyBlockquote finds the generator whose bold heading appears in a
+// loaded blockquote. Oracle, scene and tool headings are checked in that
+// order, since generator results can quote them (a Pacing Move names its
+// Random Event); anything else is a generator entry.
 func classifyBlockquote(text string) EntryType {
 	lower := strings.ToLower(text)
-	if strings.Contains(lower, "**oracle") {
-		return EntryOracle
-	}
-	if strings.Contains(lower, "**set the scene") {
-		return EntryScene
-	}
-	if strings.Contains(lower, "**dice") || strings.Contains(lower, "**coin flip") ||
-		strings.Contains(lower, "**card draw") || strings.Contains(lower, "**direction") ||
-		strings.Contains(lower, "**weather") || strings.Contains(lower, "**color") ||
-		strings.Contains(lower, "**sound") {
-		return EntryTool
+	for _, t := range []EntryType{EntryOracle, EntryScene, EntryTool} {
+		for _, g := range engine.Generators() {
+			if EntryType(g.Entry) == t && strings.Contains(lower, "**"+strings.ToLower(g.Name)) {
+				return t
+			}
+		}
 	}
 	return EntryGenerator
 }
@@ -1,6 +1,7 @@
 package journal
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -11,9 +12,24 @@ func Render(j *Journal) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "# %s\n\n", j.Title)
 	fmt.Fprintf(&b, "*Started: %s*\n\n", j.CreatedAt.Format("2006-01-02"))
+	if len(j.HouseRules) > 0 {
+		fmt.Fprintf(&b, "%s%s*\n\n", houseRulesPrefix, strings.Join(j.HouseRules, ", "))
+	}
+	if p, err := engine.FindPreset(j.System); j.System != "" && err == nil {
+		fmt.Fprintf(&b, "*System: %s*\n\n", p.Name)
+	}
+	if data := renderData(j); data != "" {
+		b.WriteString(data + "\n\n")
+	}
 	b.WriteString("---\n\n")
+	if j.Start != nil {
+		renderStart(&b, j.Start)
+	}
 
 	for _, e := range j.Entries {
+		if e.Scene != nil {
+			renderSceneHeading(&b, e.Scene)
+		}
 		if !e.Timestamp.IsZero() {
 			source := "Engine"
 			if e.Type == EntryNarrative {
@@ -31,6 +47,65 @@ func Render(j *Journal) string {
 	return b.String()
 }
 
+const houseRulesPrefix = "*House rules: "
+
+const sceneGoalPrefix = "*Goal: "
+
+// renderSceneHeading writes the heading that opens a scene, and its goal.
+func renderSceneHeading(b *strings.Builder, s *Scene) {
+	fmt.Fprintf(b, "## Scene %d", s.Number)
+	if s.Title != "" {
+		b.WriteString(": " + s.Title)
+	}
+	b.WriteString("\n\n")
+	if s.Goal != "" {
+		fmt.Fprintf(b, "%s%s*\n\n", sceneGoalPrefix, s.Goal)
+	}
+}
+
+const startHeading, startCharacters = "## Adventure Start", "**Characters:** "
+
+// renderStart writes the Adventure Start section, closed by a rule of its
+// own so the loader can tell it from the entries.
+func renderStart(b *strings.Builder, s *Start) {
+	b.WriteString(startHeading + "\n\n")
+	if len(s.Characters) > 0 {
+		fmt.Fprintf(b, "%s%s\n\n", startCharacters, strings.Join(s.Characters, ", "))
+	}
+	for _, e := range s.Entries {
+		b.WriteString(e.Markdown + "\n\n")
+	}
+	b.WriteString("---\n\n")
+}
+
+// journalData is the machine-readable part of the journal, kept in an HTML
+// comment in the header so it stays invisible in Markdown viewers.
+type journalData struct {
+	Engine   *engine.EngineState     `json:"engine,omitempty"`
+	System   string                  `json:"system,omitempty"`
+	Sheets   []engine.CharacterSheet `json:"sheets,omitempty"`
+	Threads  []engine.Thread         `json:"threads,omitempty"`
+	Roster   []engine.RosterNPC      `json:"roster,omitempty"`
+	Factions []engine.Faction        `json:"factions,omitempty"`
+}
+
+const dataPrefix, dataSuffix = "<!-- opse-data ", " -->"
+
+func renderData(j *Journal) string {
+	if j.State == nil && j.System == "" && len(j.Sheets) == 0 && len(j.Threads) == 0 && len(j.Roster) == 0 &&
+		len(j.Factions) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(journalData{
+		Engine: j.State, System: j.System, Sheets: j.Sheets, Threads: j.Threads, Roster: j.Roster,
+		Factions: j.Factions,
+	})
+	if err != nil {
+		return ""
+	}
+	return dataPrefix + string(data) + dataSuffix
+}
+
 func suitShort(c engine.Card) string {
 	d := engine.SuitDomains[c.Suit]
 	idx := strings.Index(d, " ")
@@ -40,6 +115,47 @@ func suitShort(c engine.Card) string {
 	return d[:idx]
 }
 
+// RenderCardRolls lists the cards that stood in for d6 rolls under the
+// "use only cards" rule, in the order they were used. It is appended to
+// the blockquote of the result that consumed them.
+func RenderCardRolls(rolls []engine.CardRoll) string {
+	parts := make([]string, len(rolls))
+	for i, r := range rolls {
+		var b strings.Builder
+		if r.Joker {
+			b.WriteString("Joker ↺ ")
+		}
+		if e := r.Event; e != nil {
+			fmt.Fprintf(&b, "*(Random Event: %s / %s)* ", e.Action.Entry, e.Topic.Entry)
+		}
+		for _, c := range r.Discarded {
+			fmt.Fprintf(&b, "~~%s~~ ", c.String())
+		}
+		fmt.Fprintf(&b, "%s → %d", r.Card.String(), r.Value)
+		parts[i] = b.String()
+	}
+	return "> - **Cards:** " + strings.Join(parts, ", ")
+}
+
+// RenderTrace lists every die, coin and card behind a result. It is
+// appended to the result's blockquote when trace_in_journal is set.
+func RenderTrace(t engine.Trace) string {
+	return "> - **Trace:** " + t.String()
+}
+
+// diceNote shows the rolls behind a card made under the "use only dice"
+// rule, or nothing for a card drawn from the deck.
+func diceNote(d engine.DrawResult) string {
+	if d.Dice == nil {
+		return ""
+	}
+	rank := fmt.Sprintf("d12 %d", d.Dice.RankRoll)
+	if d.Dice.Coin != "" {
+		rank += ", coin " + d.Dice.Coin
+	}
+	return fmt.Sprintf(" *[%s, d4 %d]*", rank, d.Dice.SuitRoll)
+}
+
 func RenderOracleYesNo(r engine.OracleYesNoResult) string {
 	answer := "No"
 	if r.Answer {
@@ -49,20 +165,71 @@ func RenderOracleYesNo(r engine.OracleYesNoResult) string {
 	if r.Modifier != "" {
 		result += ", " + r.Modifier
 	}
-	return fmt.Sprintf("> **Oracle (Yes/No, %s):** %s", r.Likelihood, result)
+	if r.Bonus != 0 {
+		result += fmt.Sprintf(" *(%+d)*", r.Bonus)
+	}
+	heading := fmt.Sprintf("Oracle (Yes/No, %s)", r.Likelihood)
+	if r.Question != "" {
+		return renderQA(heading, r.Question, result)
+	}
+	return fmt.Sprintf("> **%s:** %s", heading, result)
+}
+
+const qaQuestionPrefix = "> - **Q:** "
+
+// renderQA renders an oracle result as the question asked and its answer.
+func renderQA(heading, question, answer string) string {
+	return fmt.Sprintf("> **%s**\n%s%s\n> - **A:** %s", heading, qaQuestionPrefix, question, answer)
+}
+
+func RenderOracleHow(r engine.OracleHowResult) string {
+	if r.Question != "" {
+		return renderQA("Oracle (How)", r.Question, r.Result)
+	}
+	return fmt.Sprintf("> **Oracle (How):** %s", r.Result)
+}
+
+func RenderFailureMove(r engine.FailureMoveResult) string {
+	return fmt.Sprintf("> **Failure Move:** %s", r.Result)
 }
 
 func RenderCardTable(r engine.CardTableResult) string {
-	return fmt.Sprintf("> **%s:** %s %s *(%s)*",
-		r.TableName, r.Draw.Card.String(), r.Entry, suitShort(r.Draw.Card))
+	return fmt.Sprintf("> **%s:** %s %s *(%s)*%s",
+		r.TableName, r.Draw.Card.String(), r.Entry, suitShort(r.Draw.Card), diceNote(r.Draw))
 }
 
 func RenderRandomEvent(r engine.RandomEventResult) string {
 	return fmt.Sprintf(`> **Random Event**
-> - **What happens:** %s %s *(%s)*
-> - **Involving:** %s %s *(%s)*`,
-		r.Action.Draw.Card.String(), r.Action.Entry, suitShort(r.Action.Draw.Card),
-		r.Topic.Draw.Card.String(), r.Topic.Entry, suitShort(r.Topic.Draw.Card))
+> - **What happens:** %s %s *(%s)*%s
+> - **Involving:** %s %s *(%s)*%s`,
+		r.Action.Draw.Card.String(), r.Action.Entry, suitShort(r.Action.Draw.Card), diceNote(r.Action.Draw),
+		r.Topic.Draw.Card.String(), r.Topic.Entry, suitShort(r.Topic.Draw.Card), diceNote(r.Topic.Draw))
+}
+
+// RenderFactionTurn lists what each faction did off-screen: the Action
+// Focus of one that advanced and who it was aimed at, and its clock.
+func RenderFactionTurn(r engine.FactionTurnResult) string {
+	var b strings.Builder
+	b.WriteString("> **Faction Turn**")
+	for _, m := range r.Moves {
+		fmt.Fprintf(&b, "\n> - **%s:** ", m.Faction)
+		if a := m.Action; a != nil {
+			fmt.Fprintf(&b, "%s %s *(%s)*%s", a.Draw.Card.String(), a.Entry, suitShort(a.Draw.Card), diceNote(a.Draw))
+			if m.Target != "" {
+				fmt.Fprintf(&b, " → %s *(%s)*", m.Target, m.Stance)
+			}
+		} else {
+			b.WriteString("no progress")
+		}
+		fmt.Fprintf(&b, " — %s, %d/%d", m.How.Result, m.Progress, m.Clock)
+		if m.Done() {
+			b.WriteString(" — **goal reached**")
+			if m.Goal != "" {
+				fmt.Fprintf(&b, ": %s", m.Goal)
+			}
+		}
+	}
+	return b.String()
 }
 
 func RenderSetTheScene(r engine.SetTheSceneResult) string {
@@ -70,20 +237,19 @@ func RenderSetTheScene(r engine.SetTheSceneResult) string {
 	fmt.Fprintf(&b, "> **Set the Scene**\n> - **Complication:** %s", r.Complication.Result)
 	if r.Altered && r.AlteredScene != nil {
 		fmt.Fprintf(&b, "\n> - **Altered Scene:** %s", r.AlteredScene.Result)
-		if r.AlteredScene.Cascade != nil {
-			switch c := r.AlteredScene.Cascade.(type) {
-			case *engine.SceneComplicationResult:
-				fmt.Fprintf(&b, "\n>   - **Cascade:** %s", c.Result)
-			case *engine.PacingMoveResult:
-				fmt.Fprintf(&b, "\n>   - **Cascade (Pacing Move):** %s", c.Result)
-				if c.RandomEvent != nil {
-					fmt.Fprintf(&b, "\n>     - **Random Event:** %s / %s",
-						c.RandomEvent.Action.Entry, c.RandomEvent.Topic.Entry)
-				}
-			case *engine.RandomEventResult:
-				fmt.Fprintf(&b, "\n>   - **Cascade (Random Event):** %s / %s",
-					c.Action.Entry, c.Topic.Entry)
+		alt := r.AlteredScene
+		switch {
+		case alt.Complication != nil:
+			fmt.Fprintf(&b, "\n>   - **Cascade:** %s", alt.Complication.Result)
+		case alt.PacingMove != nil:
+			fmt.Fprintf(&b, "\n>   - **Cascade (Pacing Move):** %s", alt.PacingMove.Result)
+			if evt := alt.PacingMove.RandomEvent; evt != nil {
+				fmt.Fprintf(&b, "\n>     - **Random Event:** %s / %s",
+					evt.Action.Entry, evt.Topic.Entry)
 			}
+		case alt.RandomEvent != nil:
+			fmt.Fprintf(&b, "\n>   - **Cascade (Random Event):** %s / %s",
+				alt.RandomEvent.Action.Entry, alt.RandomEvent.Topic.Entry)
 		}
 	}
 	return b.String()
@@ -101,11 +267,11 @@ func RenderPacingMove(r engine.PacingMoveResult) string {
 
 func RenderGeneric(r engine.GenericGeneratorResult) string {
 	return fmt.Sprintf(`> **Generic Generator**
-> - **What it does:** %s %s *(%s)*
-> - **How it looks:** %s %s *(%s)*
+> - **What it does:** %s %s *(%s)*%s
+> - **How it looks:** %s %s *(%s)*%s
 > - **How significant:** %s`,
-		r.Action.Draw.Card.String(), r.Action.Entry, suitShort(r.Action.Draw.Card),
-		r.Detail.Draw.Card.String(), r.Detail.Entry, suitShort(r.Detail.Draw.Card),
+		r.Action.Draw.Card.String(), r.Action.Entry, suitShort(r.Action.Draw.Card), diceNote(r.Action.Draw),
+		r.Detail.Draw.Card.String(), r.Detail.Entry, suitShort(r.Detail.Draw.Card), diceNote(r.Detail.Draw),
 		r.Significance.Result)
 }
 
@@ -119,16 +285,16 @@ func RenderPlotHook(r engine.PlotHookResult) string {
 
 func RenderNPC(r engine.NPCResult) string {
 	return fmt.Sprintf(`> **NPC**
-> - **Identity:** %s *(%s)*
-> - **Goal:** %s *(%s)*
-> - **Feature:** %s — %s *(%s)*
+> - **Identity:** %s *(%s)*%s
+> - **Goal:** %s *(%s)*%s
+> - **Feature:** %s — %s *(%s)*%s
 > - **Attitude:** %s
-> - **Topic:** %s *(%s)*`,
-		r.Identity.Entry, suitShort(r.Identity.Draw.Card),
-		r.Goal.Entry, suitShort(r.Goal.Draw.Card),
-		r.Feature, r.FeatureDetail.Entry, suitShort(r.FeatureDetail.Draw.Card),
+> - **Topic:** %s *(%s)*%s`,
+		r.Identity.Entry, suitShort(r.Identity.Draw.Card), diceNote(r.Identity.Draw),
+		r.Goal.Entry, suitShort(r.Goal.Draw.Card), diceNote(r.Goal.Draw),
+		r.Feature, r.FeatureDetail.Entry, suitShort(r.FeatureDetail.Draw.Card), diceNote(r.FeatureDetail.Draw),
 		r.Attitude.Result,
-		r.Topic.Entry, suitShort(r.Topic.Draw.Card))
+		r.Topic.Entry, suitShort(r.Topic.Draw.Card), diceNote(r.Topic.Draw))
 }
 
 func RenderDungeonRoom(r engine.DungeonRoomResult) string {
@@ -157,22 +323,40 @@ func RenderHex(r engine.HexResult) string {
 }
 
 func RenderDiceRoll(r engine.DiceRollResult) string {
-	parts := make([]string, len(r.Rolls))
-	for i, v := range r.Rolls {
-		if r.Kept[i] {
-			parts[i] = fmt.Sprintf("[%d]", v)
-		} else {
-			parts[i] = fmt.Sprintf("~~%d~~", v)
-		}
+	return fmt.Sprintf("> **Dice (%s):** %s = **%s**",
+		escapeDice(r.Expression.Raw), r.Breakdown(markDie), r.Outcome())
+}
+
+// markDie shows a die as [4], a dropped one struck through and a pool's
+// successes and failures ticked and crossed.
+func markDie(face string, mark engine.DieMark) string {
+	switch mark {
+	case engine.DieDropped:
+		return "~~" + face + "~~"
+	case engine.DieSuccess:
+		return "[" + face + "✓]"
+	case engine.DieFailure:
+		return "[" + face + "✗]"
 	}
-	mod := ""
-	if r.Expression.Modifier > 0 {
-		mod = fmt.Sprintf(" + %d", r.Expression.Modifier)
-	} else if r.Expression.Modifier < 0 {
-		mod = fmt.Sprintf(" - %d", -r.Expression.Modifier)
+	return "[" + face + "]"
+}
+
+func escapeDice(raw string) string { return strings.ReplaceAll(raw, "*", `\*`) }
+
+func RenderCheck(r engine.CheckResult) string {
+	title := escapeDice(r.Roll.Expression.Raw)
+	if r.HasTarget {
+		title += fmt.Sprintf(" vs %d", r.Target)
+	}
+	roll := fmt.Sprintf("%s = **%s**", r.Roll.Breakdown(markDie), r.Roll.Outcome())
+	if r.Challenge != nil {
+		roll += " vs " + r.Challenge.Breakdown(markDie)
+	}
+	s := fmt.Sprintf("> **Check (%s):** %s — **%s**", title, roll, r.Outcome.Label)
+	if r.FailureMove != nil {
+		s += fmt.Sprintf("\n> - **Failure Move:** %s", r.FailureMove.Result)
 	}
-	return fmt.Sprintf("> **Dice (%s):** %s%s = **%d**",
-		r.Expression.Raw, strings.Join(parts, " "), mod, r.Total)
+	return s
 }
 
 func RenderCoinFlip(r engine.CoinFlipResult) string {
@@ -222,9 +406,20 @@ func RenderSound(r engine.SoundResult) string {
 
 func RenderDungeonTheme(r engine.DungeonThemeResult) string {
 	return fmt.Sprintf(`> **Dungeon Theme**
-> - **How it looks:** %s %s *(%s)*
-> - **How it's used:** %s %s *(%s)*`,
-		r.Looks.Draw.Card.String(), r.Looks.Entry, suitShort(r.Looks.Draw.Card),
-		r.Used.Draw.Card.String(), r.Used.Entry, suitShort(r.Used.Draw.Card))
+> - **How it looks:** %s %s *(%s)*%s
+> - **How it's used:** %s %s *(%s)*%s`,
+		r.Looks.Draw.Card.String(), r.Looks.Entry, suitShort(r.Looks.Draw.Card), diceNote(r.Looks.Draw),
+		r.Used.Draw.Card.String(), r.Used.Entry, suitShort(r.Used.Draw.Card), diceNote(r.Used.Draw))
 }
 
+func RenderTable(r engine.TableResult) string {
+	if r.Draw != nil {
+		s := fmt.Sprintf("> **%s:** %s %s%s", r.TableName, r.Draw.Card.String(), r.Entry, diceNote(*r.Draw))
+		if evt := r.Draw.JokerEvent; evt != nil {
+			s += fmt.Sprintf("\n> - **Joker ↺ Random Event:** %s %s / %s %s",
+				evt.Action.Draw.Card.String(), evt.Action.Entry, evt.Topic.Draw.Card.String(), evt.Topic.Entry)
+		}
+		return s
+	}
+	return fmt.Sprintf("> **%s:** %s *(%s: %d)*", r.TableName, r.Entry, r.Roll, r.Total)
+}
@@ -21,12 +21,30 @@ func TestRenderOracleYesNo(t *testing.T) {
 	}
 }
 
+func TestRenderOracleYesNo_WithQuestion(t *testing.T) {
+	r := engine.OracleYesNoResult{
+		Question: "Is the door locked?", Likelihood: "Likely",
+		AnswerRoll: 2, ModRoll: 3,
+	}
+	want := "> **Oracle (Yes/No, Likely)**\n> - **Q:** Is the door locked?\n> - **A:** No"
+	if got := RenderOracleYesNo(r); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderOracleYesNo_WithBonus(t *testing.T) {
+	r := engine.OracleYesNoResult{Likelihood: "Very Unlikely", AnswerRoll: 5, Bonus: 1, Answer: true, ModRoll: 3}
+	want := "> **Oracle (Yes/No, Very Unlikely):** Yes *(+1)*"
+	if got := RenderOracleYesNo(r); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
 func TestRenderDiceRoll_WithDropped(t *testing.T) {
+	expr, _ := engine.ParseDice("4d6kh3")
 	r := engine.DiceRollResult{
-		Expression: engine.DiceExpression{Raw: "4d6kh3", Modifier: 0},
-		Rolls:      []int{6, 4, 3, 2},
-		Kept:       []bool{true, true, true, false},
-		Subtotal:   13,
+		Expression: expr,
+		Terms:      []engine.DiceTermResult{{Rolls: []int{6, 4, 3, 2}, Kept: []bool{true, true, true, false}, Total: 13}},
 		Total:      13,
 	}
 	md := RenderDiceRoll(r)
@@ -38,12 +56,105 @@ func TestRenderDiceRoll_WithDropped(t *testing.T) {
 	}
 }
 
+func TestRenderDiceRoll_MultipleTerms(t *testing.T) {
+	expr, _ := engine.ParseDice("(1d8+2)*2+1d4")
+	r := engine.DiceRollResult{
+		Expression: expr,
+		Terms: []engine.DiceTermResult{
+			{Rolls: []int{5}, Kept: []bool{true}, Total: 5},
+			{Rolls: []int{3}, Kept: []bool{true}, Total: 3},
+		},
+		Total: 17,
+	}
+	want := `> **Dice ((1d8+2)\*2+1d4):** (d8 [5] + 2) × 2 + d4 [3] = **17**`
+	if got := RenderDiceRoll(r); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderDiceRoll_Pool(t *testing.T) {
+	expr, _ := engine.ParseDice("5d10>=7f1")
+	r := engine.DiceRollResult{
+		Expression: expr,
+		Terms: []engine.DiceTermResult{{
+			Rolls:   []int{8, 3, 1, 10, 7},
+			Kept:    []bool{true, true, true, true, true},
+			Success: []bool{true, false, false, true, true},
+			Failure: []bool{false, false, true, false, false},
+		}},
+		Total: 3, Pool: true, Failures: 1,
+	}
+	want := "> **Dice (5d10>=7f1):** [8✓] [3] [1✗] [10✓] [7✓] = **3 successes, 1 failure**"
+	if got := RenderDiceRoll(r); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderCheck(t *testing.T) {
+	expr, _ := engine.ParseDice("2d6+2")
+	r := engine.CheckResult{
+		Roll: engine.DiceRollResult{
+			Expression: expr,
+			Terms:      []engine.DiceTermResult{{Rolls: []int{1, 3}, Kept: []bool{true, true}, Total: 4}},
+			Total:      6,
+		},
+		Target: 10, HasTarget: true,
+		Outcome:     engine.CheckBand{Label: "Failure", Failure: true},
+		FailureMove: &engine.FailureMoveResult{Roll: 3, Result: "Offer a Choice"},
+	}
+	want := "> **Check (2d6+2 vs 10):** [1] [3] + 2 = **6** — **Failure**\n> - **Failure Move:** Offer a Choice"
+	if got := RenderCheck(r); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := classifyBlockquote(want); got != EntryTool {
+		t.Errorf("reloads as %q, want a single tool entry", got)
+	}
+}
+
+func TestRenderCheck_Challenge(t *testing.T) {
+	expr, _ := engine.ParseDice("1d6+2")
+	challenge, _ := engine.ParseDice("2d10")
+	r := engine.CheckResult{
+		Roll: engine.DiceRollResult{
+			Expression: expr,
+			Terms:      []engine.DiceTermResult{{Rolls: []int{4}, Kept: []bool{true}, Total: 4}},
+			Total:      6,
+		},
+		Challenge: &engine.DiceRollResult{
+			Expression: challenge,
+			Terms:      []engine.DiceTermResult{{Rolls: []int{3, 9}, Kept: []bool{true, true}, Total: 12}},
+			Total:      12,
+		},
+		Outcome: engine.CheckBand{Min: 1, Label: "Weak Hit"},
+	}
+	want := "> **Check (1d6+2):** [4] + 2 = **6** vs [3] [9] — **Weak Hit**"
+	if got := RenderCheck(r); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderDiceRoll_StatReference(t *testing.T) {
+	sheets := []engine.CharacterSheet{{Name: "Elara", Stats: []engine.Stat{{Name: "str", Value: 3}}}}
+	expr, err := engine.ParseDiceWith("d20+@str", engine.Stats(sheets, "Elara"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := engine.DiceRollResult{
+		Expression: expr,
+		Terms:      []engine.DiceTermResult{{Rolls: []int{14}, Kept: []bool{true}, Total: 14}},
+		Total:      17,
+	}
+	want := "> **Dice (d20+@str):** [14] + @str (3) = **17**"
+	if got := RenderDiceRoll(r); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
 func TestRenderDiceRoll_WithModifier(t *testing.T) {
+	expr, _ := engine.ParseDice("2d6+5")
 	r := engine.DiceRollResult{
-		Expression: engine.DiceExpression{Raw: "2d6+5", Modifier: 5},
-		Rolls:      []int{3, 4},
-		Kept:       []bool{true, true},
-		Subtotal:   7,
+		Expression: expr,
+		Terms:      []engine.DiceTermResult{{Rolls: []int{3, 4}, Kept: []bool{true, true}, Total: 7}},
 		Total:      12,
 	}
 	md := RenderDiceRoll(r)
@@ -102,3 +213,73 @@ func TestStripCharMarkdown_PlainText(t *testing.T) {
 		t.Errorf("expected unchanged text, got %q", got)
 	}
 }
+
+func TestRenderCardRolls(t *testing.T) {
+	rolls := []engine.CardRoll{
+		{Card: engine.Card{Rank: engine.RankSeven, Suit: engine.Clubs}, Value: 3},
+		{
+			Card:      engine.Card{Rank: engine.RankFour, Suit: engine.Hearts},
+			Value:     2,
+			Discarded: []engine.Card{{Rank: engine.RankAce, Suit: engine.Spades}},
+		},
+	}
+	md := RenderCardRolls(rolls)
+	if !strings.Contains(md, "7♣ → 3") {
+		t.Errorf("should show card and value, got %q", md)
+	}
+	if !strings.Contains(md, "~~A♠~~ 4♥ → 2") {
+		t.Errorf("discarded Ace should be struck through, got %q", md)
+	}
+}
+
+func TestRenderCardTable_DiceOnly(t *testing.T) {
+	r := engine.CardTableResult{
+		TableName: "Action Focus",
+		Entry:     "Assist",
+		Draw: engine.DrawResult{
+			Card: engine.Card{Rank: engine.RankQueen, Suit: engine.Spades},
+			Dice: &engine.CardDice{RankRoll: 12, Coin: "Heads", SuitRoll: 3},
+		},
+	}
+	md := RenderCardTable(r)
+	if !strings.Contains(md, "Q♠ Assist") {
+		t.Errorf("should contain card and entry, got %q", md)
+	}
+	if !strings.Contains(md, "d12 12, coin Heads, d4 3") {
+		t.Errorf("should record the underlying rolls, got %q", md)
+	}
+}
+
+func TestRenderTrace_KeepsEntryType(t *testing.T) {
+	trace := engine.Trace{
+		{Kind: engine.StepDie, Sides: 6, Value: 6},
+		{Kind: engine.StepScope, Label: "Random Event"},
+		{Kind: engine.StepCard, Depth: 1, Card: engine.Card{Rank: engine.RankSeven, Suit: engine.Clubs}},
+	}
+	md := "> **Pacing Move:** Add a RANDOM EVENT to the scene\n" + RenderTrace(trace)
+	if want := "> - **Trace:** d6 6, Random Event [7♣]"; !strings.HasSuffix(md, want) {
+		t.Errorf("got %q, want suffix %q", md, want)
+	}
+	if got := classifyBlockquote(md); got != EntryGenerator {
+		t.Errorf("traced pacing move classified as %v", got)
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	r := engine.TableResult{TableName: "Tavern Names", Roll: "2d6", Rolls: []int{3, 4}, Total: 7, Entry: "The Red Lion"}
+	if got, want := RenderTable(r), "> **Tavern Names:** The Red Lion *(2d6: 7)*"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	r = engine.TableResult{TableName: "Court", Roll: "card", Draw: &engine.DrawResult{Card: engine.Card{Rank: engine.RankQueen, Suit: engine.Hearts}}, Entry: "Noble"}
+	if got, want := RenderTable(r), "> **Court:** Q♥ Noble"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	r.Draw.JokerDrawn = true
+	r.Draw.JokerEvent = &engine.RandomEventResult{
+		Action: engine.CardTableResult{Draw: engine.DrawResult{Card: engine.Card{Rank: 2, Suit: engine.Spades}}, Entry: "Seek"},
+		Topic:  engine.CardTableResult{Draw: engine.DrawResult{Card: engine.Card{Rank: 5, Suit: engine.Clubs}}, Entry: "Wealth"},
+	}
+	if got, want := RenderTable(r), "> **Court:** Q♥ Noble\n> - **Joker ↺ Random Event:** 2♠ Seek / 5♣ Wealth"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,29 @@
+package journal
+
+import "opse/engine"
+
+func init() {
+	engine.RegisterMarkdown(RenderOracleYesNo)
+	engine.RegisterMarkdown(RenderOracleHow)
+	engine.RegisterMarkdown(RenderCardTable)
+	engine.RegisterMarkdown(RenderRandomEvent)
+	engine.RegisterMarkdown(RenderSetTheScene)
+	engine.RegisterMarkdown(RenderPacingMove)
+	engine.RegisterMarkdown(RenderFailureMove)
+	engine.RegisterMarkdown(RenderGeneric)
+	engine.RegisterMarkdown(RenderPlotHook)
+	engine.RegisterMarkdown(RenderNPC)
+	engine.RegisterMarkdown(RenderFactionTurn)
+	engine.RegisterMarkdown(RenderDungeonTheme)
+	engine.RegisterMarkdown(RenderDungeonRoom)
+	engine.RegisterMarkdown(RenderHex)
+	engine.RegisterMarkdown(RenderDiceRoll)
+	engine.RegisterMarkdown(RenderCheck)
+	engine.RegisterMarkdown(RenderCoinFlip)
+	engine.RegisterMarkdown(RenderCardDraw)
+	engine.RegisterMarkdown(RenderDirection)
+	engine.RegisterMarkdown(RenderWeather)
+	engine.RegisterMarkdown(RenderColor)
+	engine.RegisterMarkdown(RenderSound)
+	engine.RegisterMarkdown(RenderTable)
+}
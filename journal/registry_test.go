@@ -0,0 +1,32 @@
+package journal
+
+import (
+	"testing"
+
+	"opse/engine"
+)
+
+var sampleArgs = map[string][]string{
+	"dice_roller": {"2d6"},
+	"check":       {"2d6", "vs", "8"},
+	"ask":         {"--", "Is the door locked?"},
+}
+
+func TestGeneratorsRenderAndClassify(t *testing.T) {
+	rng := engine.NewSeededRandomizer(101, 0)
+	s := &engine.Session{Rng: rng, Deck: engine.NewDeck(rng), Utility: engine.NewUtilityDeck(rng, false)}
+	for _, g := range engine.Generators() {
+		res, err := g.Run(s, sampleArgs[g.ID])
+		if err != nil {
+			t.Fatalf("%s: %v", g.ID, err)
+		}
+		md, ok := engine.RenderMarkdown(res)
+		if !ok {
+			t.Errorf("%s: no markdown renderer for %T", g.ID, res)
+			continue
+		}
+		if got := classifyBlockquote(md); got != EntryType(g.Entry) {
+			t.Errorf("%s: reloads as %q, want %q\n%s", g.ID, got, g.Entry, md)
+		}
+	}
+}
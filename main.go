@@ -21,7 +21,7 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error loading %s: %v\n", path, err)
 			os.Exit(1)
 		}
-		runApp(loaded)
+		runApp(loaded, false)
 		return
 	}
 
@@ -42,19 +42,22 @@ func main() {
 		name := sanitizeFilename(h.Title)
 		path := filepath.Join(".", name+".md")
 		j := journal.New(h.Title, path)
-		runApp(j)
+		runApp(j, h.Kickoff)
 	case "open":
 		loaded, err := journal.Load(h.Path)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading %s: %v\n", h.Path, err)
 			os.Exit(1)
 		}
-		runApp(loaded)
+		runApp(loaded, false)
 	}
 }
 
-func runApp(j *journal.Journal) {
+func runApp(j *journal.Journal, kickoff bool) {
 	app := ui.NewApp(j)
+	if kickoff {
+		app.StartKickoff()
+	}
 	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
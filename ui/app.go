@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,13 +40,30 @@ type AppModel struct {
 	savedRolls      *engine.SavedRollsConfig
 	savedPortraits  *engine.SavedPortraitsConfig
 	sessionConfig   *engine.SessionConfig
+	defaultSystem       string           // the configured system, used when the journal pins none
+	cardD6              *engine.CardD6   // non-nil when playing with only cards
+	physical            *physicalSource  // non-nil when rolling real dice and cards
+	prompt              *physicalRequest // the die or card being asked for
+	promptAction        string
+	promptValues        []string
+	promptErr           string
+	physicalBusy        bool
+	quitting            bool         // quit once the physical action in flight is cancelled
+	lastTrace           engine.Trace // trace of the latest result, for /trace
+	lastTraceLabel      string
+	lastNPC             *engine.NPCResult // the latest NPC rolled, for /roster add
+	failureOffer        *failureOffer     // the failed check offering a Failure Move, while it is the latest result
 	portraitBrowser     PortraitBrowserModel
+	kickoff             KickoffModel
+	sceneIndex          SceneIndexModel
 	keys                KeyMap
 	width               int
 	height              int
 	showHelp            bool
 	showSavedRolls      bool
 	showPortraitBrowser bool
+	showKickoff         bool
+	showSceneIndex      bool
 	showSaveConfirm bool
 	statusMsg       string
 	statusExpiry    time.Time
@@ -51,9 +71,39 @@ type AppModel struct {
 
 func NewApp(j *journal.Journal) AppModel {
 	rng := engine.NewRandomizer()
+	deck := engine.NewDeck(rng)
+	utilityDeck := engine.NewUtilityDeck(rng, false)
+	var stateErr error
+	if j.State != nil {
+		r, d, u, err := engine.RestoreState(j.State)
+		if err != nil {
+			stateErr = fmt.Errorf("can't restore the deck (%w), starting a new shuffle", err)
+		} else {
+			rng, deck, utilityDeck = r, d, u
+		}
+	}
 	savedRolls, _ := engine.LoadSavedRolls()
 	savedPortraits, _ := engine.LoadSavedPortraits()
-	sessionConfig, _ := engine.LoadSessionConfig()
+	sessionConfig, configErr := engine.LoadSessionConfig()
+	engine.SetLikelihoods(sessionConfig.Ladder())
+	defaultSystem := sessionConfig.System
+	var systemErr error
+	if j.System != "" {
+		if _, systemErr = engine.FindPreset(j.System); systemErr == nil {
+			sessionConfig.System = j.System
+		}
+	}
+	tables, tablesErr := engine.LoadTables(engine.TableDirs(j.FilePath)...)
+	engine.SetTables(tables)
+	allCommands = commandNames()
+	var rulesErr error
+	if sessionConfig.RulesFile != "" {
+		var rules engine.Rules
+		if rules, rulesErr = engine.LoadRules(sessionConfig.RulesFile); rulesErr == nil {
+			rulesErr = engine.SetRules(rules)
+		}
+	}
+	overridesErr := engine.SetOverrides(sessionConfig.TableOverrides)
 	m := AppModel{
 		sidebar:         NewSidebar(),
 		logview:         NewLogView(),
@@ -64,16 +114,93 @@ func NewApp(j *journal.Journal) AppModel {
 		focus:           FocusInput,
 		journal:         j,
 		rng:             rng,
-		deck:            engine.NewDeck(rng),
-		utilityDeck:     engine.NewUtilityDeck(rng, false),
+		deck:            deck,
+		utilityDeck:     utilityDeck,
 		savedRolls:      savedRolls,
 		savedPortraits:  savedPortraits,
 		sessionConfig:   sessionConfig,
+		defaultSystem:   defaultSystem,
 		keys:            DefaultKeys,
 	}
+	m.savedRollsModal.SetSystem(m.system())
+	m.sidebar.Threads = engine.OpenThreads(j.Threads)
+	m.sidebar.Factions = j.Factions
+	setCharNames(j)
+	setMacroNames(savedRolls)
+	if !m.setRandomness(sessionConfig.Randomness) {
+		m.setRandomness(engine.RandomnessStandard)
+	}
+	if configErr != nil {
+		m.showError(fmt.Errorf("config: %s", strings.ReplaceAll(configErr.Error(), "\n", "; ")))
+	}
+	if err := errors.Join(tablesErr, rulesErr, overridesErr); err != nil {
+		m.showError(fmt.Errorf("tables: %s", strings.ReplaceAll(err.Error(), "\n", "; ")))
+	}
+	if err := errors.Join(stateErr, systemErr); err != nil {
+		m.showError(fmt.Errorf("journal: %s", strings.ReplaceAll(err.Error(), "\n", "; ")))
+	}
 	return m
 }
 
+// system returns the game system in play, or nil for none.
+func (m *AppModel) system() *engine.Preset {
+	if p, ok := m.sessionConfig.Preset(); ok {
+		return &p
+	}
+	return nil
+}
+
+// setSystem switches to the named game system, or back to the configured
+// one for "off", and pins the choice to the journal.
+func (m *AppModel) setSystem(name string) error {
+	if strings.EqualFold(name, "off") {
+		m.journal.PinSystem("")
+		m.sessionConfig.System = m.defaultSystem
+	} else {
+		p, err := engine.FindPreset(name)
+		if err != nil {
+			return err
+		}
+		m.journal.PinSystem(p.ID)
+		m.sessionConfig.System = p.ID
+	}
+	m.savedRollsModal.SetSystem(m.system())
+	m.saveJournal()
+	return nil
+}
+
+// setRandomness switches how the OPSE tables get their d6 values and
+// cards. It returns false for an unknown mode.
+func (m *AppModel) setRandomness(mode string) bool {
+	switch mode {
+	case engine.RandomnessStandard, "":
+		m.cardD6 = nil
+		m.physical = nil
+		m.rng.SetD6Source(nil)
+		m.deck.SetSource(nil)
+		mode = engine.RandomnessStandard
+	case engine.RandomnessCards:
+		m.cardD6 = engine.NewCardD6(m.deck)
+		m.physical = nil
+		m.rng.SetD6Source(m.cardD6)
+		m.deck.SetSource(nil)
+	case engine.RandomnessDice:
+		m.cardD6 = nil
+		m.physical = nil
+		m.rng.SetD6Source(nil)
+		m.deck.SetSource(engine.NewDiceCards(m.rng))
+	case engine.RandomnessPhysical:
+		m.cardD6 = nil
+		m.physical = newPhysicalSource(engine.NewRandomizer())
+		m.rng.SetD6Source(m.physical)
+		m.deck.SetSource(m.physical)
+	default:
+		return false
+	}
+	m.sessionConfig.Randomness = mode
+	return true
+}
+
 func (m AppModel) Init() tea.Cmd { return nil }
 
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -88,11 +215,30 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case physicalPromptMsg:
+		if m.quitting {
+			msg.reply <- physicalAnswer{cancel: true}
+			return m, m.physical.wait()
+		}
+		m.showPrompt(physicalRequest(msg))
+		return m, nil
+
+	case physicalDoneMsg:
+		m.finishPhysical(msg)
+		if m.quitting {
+			m.saveJournal()
+			return m, tea.Quit
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.physicalBusy {
+			return m.updatePrompt(msg)
+		}
 		if m.showSaveConfirm {
 			switch msg.String() {
 			case "y":
-				m.journal.Save()
+				m.saveJournal()
 				m.showSaveConfirm = false
 				m.statusMsg = "Saved!"
 				m.statusExpiry = time.Now().Add(3 * time.Second)
@@ -109,7 +255,37 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			rollID, cmd := m.savedRollsModal.Update(msg)
 			if rollID != "" {
 				m.showSavedRolls = false
-				m.runSavedRoll(rollID)
+				if mac, ok := m.savedRolls.Macro(rollID); ok {
+					return m, tea.Batch(cmd, m.runMacro(mac, nil))
+				}
+				cmd = tea.Batch(cmd, m.runSavedRoll(rollID))
+			}
+			setMacroNames(m.savedRolls)
+			return m, cmd
+		}
+		if m.showSceneIndex {
+			if key.Matches(msg, m.keys.Escape) {
+				m.showSceneIndex = false
+				return m, nil
+			}
+			if i := m.sceneIndex.Update(msg); i >= 0 {
+				m.showSceneIndex = false
+				m.logview.GotoScene(i)
+				m.setFocus(FocusLog)
+			}
+			return m, nil
+		}
+		if m.showKickoff {
+			if key.Matches(msg, m.keys.Escape) {
+				m.showKickoff = false
+				return m, nil
+			}
+			done, cmd := m.kickoff.Update(msg)
+			if done {
+				m.showKickoff = false
+				m.finishKickoff()
+			} else {
+				m.rollKickoff()
 			}
 			return m, cmd
 		}
@@ -130,7 +306,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if key.Matches(msg, m.keys.Quit) {
-			m.journal.Save()
+			m.saveJournal()
 			return m, tea.Quit
 		}
 		if key.Matches(msg, m.keys.Tab) {
@@ -149,7 +325,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if _, err := os.Stat(m.journal.FilePath); err == nil {
 				m.showSaveConfirm = true
 			} else {
-				m.journal.Save()
+				m.saveJournal()
 				m.statusMsg = "Saved!"
 				m.statusExpiry = time.Now().Add(3 * time.Second)
 			}
@@ -173,20 +349,24 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if m.focus != FocusInput {
 			if action := m.matchShortcut(msg); action != "" {
-				m.runAction(action)
-				return m, nil
+				return m, m.runAction(action, nil)
 			}
 		}
 
 		return m.routeToFocused(msg)
 
 	case NarrativeMsg:
+		if m.physicalBusy {
+			return m, nil
+		}
 		m.addNarrative(msg.Text)
 		return m, nil
 
 	case CommandMsg:
-		m.runCommand(msg)
-		return m, nil
+		if m.physicalBusy {
+			return m, nil
+		}
+		return m, m.runCommand(msg)
 	}
 
 	return m, nil
@@ -229,10 +409,21 @@ func (m *AppModel) routeToFocused(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else if key.Matches(msg, m.keys.Down) {
 			m.sidebar.MoveDown()
 		} else if key.Matches(msg, m.keys.Enter) {
-			m.runAction(m.sidebar.Selected().Action)
+			return m, m.runAction(m.sidebar.Selected().Action, nil)
 		}
 		return m, nil
 	case FocusLog:
+		switch {
+		case key.Matches(msg, m.keys.Scenes):
+			m.openSceneIndex()
+			return m, nil
+		case key.Matches(msg, m.keys.PrevScene):
+			m.logview.JumpScene(-1)
+			return m, nil
+		case key.Matches(msg, m.keys.NextScene):
+			m.logview.JumpScene(1)
+			return m, nil
+		}
 		_, cmd := m.logview.Update(msg)
 		return m, cmd
 	}
@@ -240,326 +431,707 @@ func (m *AppModel) routeToFocused(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *AppModel) matchShortcut(msg tea.KeyMsg) string {
-	shortcuts := []struct {
-		binding key.Binding
-		action  string
-	}{
-		{m.keys.OracleLikely, "oracle_likely"},
-		{m.keys.OracleEven, "oracle_even"},
-		{m.keys.OracleUnlikely, "oracle_unlikely"},
-		{m.keys.OracleHow, "oracle_how"},
-		{m.keys.ActionFocus, "focus_action"},
-		{m.keys.DetailFocus, "focus_detail"},
-		{m.keys.TopicFocus, "focus_topic"},
-		{m.keys.SetScene, "set_scene"},
-		{m.keys.RandomEvent, "random_event"},
-		{m.keys.PacingMove, "pacing_move"},
-		{m.keys.FailureMove, "failure_move"},
-		{m.keys.Generic, "generic"},
-	}
-	for _, s := range shortcuts {
-		if key.Matches(msg, s.binding) {
-			return s.action
+	for _, g := range engine.Generators() {
+		if g.Key != "" && msg.String() == g.Key {
+			return g.ID
 		}
 	}
 	return ""
 }
 
-func (m *AppModel) runAction(action string) {
-	now := time.Now()
-	var label, md, tuiStr string
-	var entryType journal.EntryType
-
-	switch action {
-	case "oracle_likely":
-		r := engine.OracleYesNo(m.rng, "Likely")
-		label = "Oracle (Yes/No, Likely)"
-		md = journal.RenderOracleYesNo(r)
-		tuiStr = RenderOracleYesNoTUI(r)
-		entryType = journal.EntryOracle
-	case "oracle_even":
-		r := engine.OracleYesNo(m.rng, "Even")
-		label = "Oracle (Yes/No, Even)"
-		md = journal.RenderOracleYesNo(r)
-		tuiStr = RenderOracleYesNoTUI(r)
-		entryType = journal.EntryOracle
-	case "oracle_unlikely":
-		r := engine.OracleYesNo(m.rng, "Unlikely")
-		label = "Oracle (Yes/No, Unlikely)"
-		md = journal.RenderOracleYesNo(r)
-		tuiStr = RenderOracleYesNoTUI(r)
-		entryType = journal.EntryOracle
-	case "oracle_how":
-		r := engine.OracleHow(m.rng)
-		label = "Oracle (How)"
-		md = fmt.Sprintf("> **Oracle (How):** %s", r.Result)
-		tuiStr = RenderOracleHowTUI(r)
-		entryType = journal.EntryOracle
-	case "focus_action":
-		r := engine.ActionFocus(m.deck)
-		label, md = r.TableName, journal.RenderCardTable(r)
-		tuiStr = RenderCardTableTUI(r)
-		entryType = journal.EntryGenerator
-	case "focus_detail":
-		r := engine.DetailFocus(m.deck)
-		label, md = r.TableName, journal.RenderCardTable(r)
-		tuiStr = RenderCardTableTUI(r)
-		entryType = journal.EntryGenerator
-	case "focus_topic":
-		r := engine.TopicFocus(m.deck)
-		label, md = r.TableName, journal.RenderCardTable(r)
-		tuiStr = RenderCardTableTUI(r)
-		entryType = journal.EntryGenerator
-	case "random_event":
-		r := engine.RandomEvent(m.deck, m.rng)
-		label = "Random Event"
-		md = journal.RenderRandomEvent(r)
-		tuiStr = RenderRandomEventTUI(r)
-		entryType = journal.EntryGenerator
-	case "set_scene":
-		r := engine.SetTheScene(m.rng, m.deck)
-		label = "Set the Scene"
-		md = journal.RenderSetTheScene(r)
-		tuiStr = RenderSetTheSceneTUI(r)
-		entryType = journal.EntryScene
-	case "pacing_move":
-		r := engine.PacingMove(m.rng, m.deck)
-		label = "Pacing Move"
-		md = journal.RenderPacingMove(r)
-		tuiStr = RenderPacingMoveTUI(r)
-		entryType = journal.EntryGenerator
-	case "failure_move":
-		r := engine.FailureMove(m.rng)
-		label = "Failure Move"
-		md = fmt.Sprintf("> **Failure Move:** %s", r.Result)
-		tuiStr = RenderFailureMoveTUI(r)
-		entryType = journal.EntryGenerator
-	case "generic":
-		r := engine.GenericGenerator(m.deck, m.rng)
-		label = "Generic Generator"
-		md = journal.RenderGeneric(r)
-		tuiStr = RenderGenericTUI(r)
-		entryType = journal.EntryGenerator
-	case "plot_hook":
-		r := engine.PlotHook(m.rng)
-		label = "Plot Hook"
-		md = journal.RenderPlotHook(r)
-		tuiStr = RenderPlotHookTUI(r)
-		entryType = journal.EntryGenerator
-	case "npc":
-		r := engine.NPCGenerator(m.deck, m.rng)
-		label = "NPC"
-		md = journal.RenderNPC(r)
-		tuiStr = RenderNPCTUI(r)
-		entryType = journal.EntryGenerator
-	case "dungeon_theme":
-		r := engine.DungeonTheme(m.deck)
-		label = "Dungeon Theme"
-		md = journal.RenderDungeonTheme(r)
-		tuiStr = RenderDungeonThemeTUI(r)
-		entryType = journal.EntryGenerator
-	case "dungeon_room":
-		r := engine.DungeonRoom(m.rng)
-		label = "Dungeon Room"
-		md = journal.RenderDungeonRoom(r)
-		tuiStr = RenderDungeonRoomTUI(r)
-		entryType = journal.EntryGenerator
-	case "hex":
-		r := engine.HexCrawl(m.rng, m.deck)
-		label = "Hex"
-		md = journal.RenderHex(r)
-		tuiStr = RenderHexTUI(r)
-		entryType = journal.EntryGenerator
-	case "coin_flip":
-		r := engine.FlipCoins(m.rng, 1)
-		label = "Coin Flip"
-		md = journal.RenderCoinFlip(r)
-		tuiStr = RenderCoinFlipTUI(r)
-		entryType = journal.EntryTool
-	case "card_draw":
-		r := m.utilityDeck.Draw(1)
-		label = "Card Draw"
-		md = journal.RenderCardDraw(r)
-		tuiStr = RenderCardDrawTUI(r)
-		entryType = journal.EntryTool
-	case "direction":
-		r := engine.RandomDirection(m.rng, 8)
-		label = "Direction"
-		md = journal.RenderDirection(r)
-		tuiStr = RenderDirectionTUI(r)
-		entryType = journal.EntryTool
-	case "weather":
-		r := engine.RandomWeather(m.rng)
-		label = "Weather"
-		md = journal.RenderWeather(r)
-		tuiStr = RenderWeatherTUI(r)
-		entryType = journal.EntryTool
-	case "color":
-		r := engine.RandomColor(m.rng)
-		label = "Color"
-		md = journal.RenderColor(r)
-		tuiStr = RenderColorTUI(r)
-		entryType = journal.EntryTool
-	case "sound":
-		r := engine.RandomSound(m.rng, "")
-		label = "Sound"
-		md = journal.RenderSound(r)
-		tuiStr = RenderSoundTUI(r)
-		entryType = journal.EntryTool
-	case "dice_roller":
-		// When selected from sidebar, do nothing — dice roller needs expression from input
-		return
-	default:
-		return
+// generated is one engine result, rendered and ready to be journaled.
+type generated struct {
+	label     string
+	md        string
+	tui       string
+	entryType journal.EntryType
+	trace     engine.Trace
+	question  string
+	scene     *journal.Scene // the scene a Set the Scene result opens, unnumbered
+	npc       *engine.NPCResult
+	check     *engine.CheckResult
+	failure   *engine.FailureMoveResult
+}
+
+// runAction runs the generator behind a sidebar item, shortcut or slash
+// command. In physical mode the generator runs in the background and the
+// returned command drives the prompts for each die and card it needs.
+func (m *AppModel) runAction(action string, args []string) tea.Cmd {
+	g, ok := engine.LookupGenerator(action)
+	if !ok {
+		return nil
+	}
+	if g.NeedsArgs() && len(args) == 0 {
+		// Required arguments: start the command in the input instead.
+		cmd := "/" + g.Command
+		if g.Sub != "" {
+			cmd += " " + g.Sub
+		}
+		m.input.textarea.SetValue(cmd + " ")
+		m.input.textarea.CursorEnd()
+		m.setFocus(FocusInput)
+		return nil
 	}
+	n, args := g.SplitRepeat(args)
+	if m.physical != nil {
+		return m.startPhysical(g.Label, func(gen *AppModel) ([]generated, error) {
+			return gen.generateN(g, args, n)
+		})
+	}
+	results, err := m.generateN(g, args, n)
+	for _, res := range results {
+		m.record(res)
+	}
+	if err != nil {
+		m.showError(err)
+	}
+	return nil
+}
+
+func (m *AppModel) showError(err error) {
+	m.statusMsg = err.Error()
+	m.statusExpiry = time.Now().Add(3 * time.Second)
+}
+
+func (m *AppModel) session() *engine.Session {
+	return &engine.Session{
+		Rng: m.rng, Deck: m.deck, Utility: m.utilityDeck, Config: m.sessionConfig,
+		Sheets: m.journal.Sheets, Character: m.journal.Character(),
+		Threads: engine.OpenThreads(m.journal.Threads), NPCs: rosterNames(m.journal.Roster),
+		Factions: engine.ActiveFactions(m.journal.Factions),
+	}
+}
+
+// generate runs g and renders its result. It only touches the engine
+// state, so it is safe to run off the UI goroutine.
+func (m *AppModel) generate(g engine.Generator, args []string) (generated, error) {
+	res, err := g.Generate(m.session(), args)
+	cardsMD, cardsTUI := m.takeCardRolls()
+	if err != nil {
+		return generated{}, err
+	}
+	md, _ := engine.RenderMarkdown(res)
+	tuiStr, _ := engine.RenderTUI(res)
+	md, tuiStr = md+cardsMD, tuiStr+cardsTUI
+	var question string
+	var scene *journal.Scene
+	var npc *engine.NPCResult
+	var check *engine.CheckResult
+	var failure *engine.FailureMoveResult
+	switch r := res.(type) {
+	case engine.OracleYesNoResult:
+		question = r.Question
+	case engine.OracleHowResult:
+		question = r.Question
+	case engine.SetTheSceneResult:
+		scene = &journal.Scene{Title: r.Title, Goal: r.Goal}
+	case engine.NPCResult:
+		npc = &r
+	case engine.CheckResult:
+		check = &r
+	case engine.FailureMoveResult:
+		failure = &r
+	}
+	return generated{
+		label: g.Name, md: md, tui: tuiStr, question: question, scene: scene, npc: npc,
+		check: check, failure: failure,
+		entryType: journal.EntryType(g.Entry),
+		trace:     engine.TraceOf(res),
+	}, nil
+}
 
+// takeCardRolls renders the d6s drawn as cards since the last call, to
+// follow the result they were drawn for. It is empty unless playing with
+// only cards.
+func (m *AppModel) takeCardRolls() (md, tui string) {
+	if m.cardD6 == nil {
+		return "", ""
+	}
+	rolls := m.cardD6.TakeRolls()
+	if len(rolls) == 0 {
+		return "", ""
+	}
+	return "\n" + journal.RenderCardRolls(rolls), "\n" + RenderCardRollsTUI(rolls)
+}
+
+// generateN runs g n times, stopping at the first error.
+func (m *AppModel) generateN(g engine.Generator, args []string, n int) ([]generated, error) {
+	var results []generated
+	for range n {
+		res, err := m.generate(g, args)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (m *AppModel) record(g generated) {
+	if g.failure != nil && m.failureOffer != nil {
+		m.acceptFailureMove(g)
+		return
+	}
+	now := time.Now()
+	m.journal.StampHouseRules(engine.Overrides())
+	if g.npc != nil {
+		m.lastNPC = g.npc
+	}
+	tui := g.tui
+	if g.scene != nil {
+		g.scene.Number = m.journal.NextScene()
+		tui = sceneBanner(*g.scene) + "\n" + tui
+	}
 	m.journal.AddEntry(journal.Entry{
-		Timestamp: now, Type: entryType, Label: label, Markdown: md,
+		Timestamp: now, Type: g.entryType, Label: g.label, Markdown: m.traced(g.label, g.md, g.trace),
+		Question: g.question, Scene: g.scene,
 	})
-	m.refreshLog(tuiStr, now, "Engine")
-	m.journal.Save()
+	m.refreshLog(tui, now, "Engine")
+	if c := g.check; c != nil && c.Outcome.Failure && c.FailureMove == nil {
+		// Offer the Failure Move in the log, where - rolls it.
+		m.failureOffer = &failureOffer{entry: len(m.journal.Entries) - 1, check: *c, tui: tui}
+		m.setFocus(FocusLog)
+	}
+	m.saveJournal()
+}
+
+// failureOffer is a failed check that offers a Failure Move: its journal
+// entry, and the block it put at the end of the log.
+type failureOffer struct {
+	entry int
+	check engine.CheckResult
+	tui   string
+}
+
+// acceptFailureMove adds the Failure Move g to the check that offered it,
+// in its journal entry and in its block in the log, as if the check had
+// rolled it.
+func (m *AppModel) acceptFailureMove(g generated) {
+	o := m.failureOffer
+	m.failureOffer = nil
+	move := *g.failure
+	// Whatever follows the results themselves, such as cards drawn for d6s.
+	checkExtra := strings.TrimPrefix(o.tui, RenderCheckTUI(o.check))
+	moveExtra := strings.TrimPrefix(g.tui, RenderFailureMoveTUI(move))
+	o.check.FailureMove = &move
+
+	// The entry starts with the check's one line; keep what follows it.
+	md := journal.RenderCheck(o.check)
+	if _, rest, ok := strings.Cut(m.journal.Entries[o.entry].Markdown, "\n"); ok {
+		md += "\n" + rest
+	}
+	md += m.traced(g.label, strings.TrimPrefix(g.md, journal.RenderFailureMove(move)), g.trace)
+	m.journal.AmendEntry(o.entry, md)
+
+	content := strings.TrimSuffix(m.logview.content, o.tui)
+	m.logview.SetContent(content + RenderCheckTUI(o.check) + checkExtra + moveExtra)
+	m.logview.ScrollToBottom()
+	m.setFocus(FocusInput)
+	m.saveJournal()
 }
 
-func (m *AppModel) runSavedRoll(id string) {
-	for _, r := range m.savedRolls.Rolls {
+// runSavedRoll rolls a saved roll. In physical mode it runs in the
+// background like a generator.
+func (m *AppModel) runSavedRoll(id string) tea.Cmd {
+	rolls := m.savedRolls.Rolls
+	if p := m.system(); p != nil {
+		rolls = append(slices.Clip(rolls), p.Rolls...)
+	}
+	for _, r := range rolls {
 		if r.ID == id {
-			expr, err := engine.ParseDice(r.Expression)
+			expr, err := engine.ParseDiceWith(r.Expression, m.session().Stats())
 			if err != nil {
-				return
+				m.showError(err)
+				return nil
 			}
-			now := time.Now()
-			result := engine.RollDice(m.rng, expr)
-			md := journal.RenderDiceRoll(result)
-			tuiStr := RenderDiceRollTUI(result)
-			m.journal.AddEntry(journal.Entry{
-				Timestamp: now, Type: journal.EntryTool, Label: r.Name, Markdown: md,
-			})
-			m.refreshLog(tuiStr, now, "Engine")
-			m.journal.Save()
-			return
+			if m.physical != nil {
+				return m.startPhysical(r.Name, func(gen *AppModel) ([]generated, error) {
+					return []generated{gen.rollSaved(r, expr)}, nil
+				})
+			}
+			m.record(m.rollSaved(r, expr))
+			return nil
+		}
+	}
+	return nil
+}
+
+// rollSaved rolls expr, the parsed expression of r, and renders it.
+func (m *AppModel) rollSaved(r engine.SavedRoll, expr engine.DiceExpression) generated {
+	g := generated{label: r.Name, entryType: journal.EntryTool}
+	if r.Check {
+		result := engine.Check(m.rng, expr, 0, false, m.sessionConfig)
+		g.md, g.tui, g.trace = journal.RenderCheck(result), RenderCheckTUI(result), result.Trace
+	} else {
+		result := engine.RollDice(m.rng, expr)
+		g.md, g.tui, g.trace = journal.RenderDiceRoll(result), RenderDiceRollTUI(result), result.Trace
+	}
+	cardsMD, cardsTUI := m.takeCardRolls()
+	g.md, g.tui = g.md+cardsMD, g.tui+cardsTUI
+	return g
+}
+
+// runMacro runs a macro's steps in order, as if each were typed. With
+// parameters still to fill in, it starts /macro in the input instead.
+func (m *AppModel) runMacro(mac engine.Macro, args []string) tea.Cmd {
+	if m.physical != nil {
+		m.showError(fmt.Errorf("macros can't run in physical mode"))
+		return nil
+	}
+	steps, missing := mac.Expand(args)
+	if len(missing) > 0 {
+		line := strings.Join(append([]string{"/macro", quoteName(mac.Name)}, args...), " ")
+		m.input.textarea.SetValue(line + " ")
+		m.input.textarea.CursorEnd()
+		m.setFocus(FocusInput)
+		m.statusMsg = fmt.Sprintf("%s needs $%s", mac.Name, strings.Join(missing, ", $"))
+		m.statusExpiry = time.Now().Add(5 * time.Second)
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, step := range steps {
+		switch msg := parseInput(step).(type) {
+		case NarrativeMsg:
+			m.addNarrative(msg.Text)
+		case CommandMsg:
+			if msg.Command == "macro" || msg.Command == "macros" {
+				continue
+			}
+			cmds = append(cmds, m.runCommand(msg))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// findMacro finds the macro /macro's arguments start with: a quoted name,
+// or the longest run of leading words naming one. It returns the rest of
+// the arguments.
+func (m *AppModel) findMacro(args []string) (engine.Macro, []string, bool) {
+	if strings.HasPrefix(strings.Join(args, " "), "\"") {
+		name, rest := splitName(args)
+		mac, ok := m.savedRolls.FindMacro(name)
+		return mac, strings.Fields(rest), ok
+	}
+	for n := len(args); n > 0; n-- {
+		if mac, ok := m.savedRolls.FindMacro(strings.Join(args[:n], " ")); ok {
+			return mac, args[n:], true
+		}
+	}
+	return engine.Macro{}, nil, false
+}
+
+// quoteName quotes a name with spaces, as commands such as /char take it.
+func quoteName(name string) string {
+	if strings.Contains(name, " ") {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// factionTurn advances the active factions off-screen and journals what
+// they did.
+func (m *AppModel) factionTurn() {
+	if m.physical != nil {
+		m.showError(fmt.Errorf("the faction turn can't be rolled in physical mode"))
+		return
+	}
+	if len(engine.ActiveFactions(m.journal.Factions)) == 0 {
+		m.showError(fmt.Errorf("no factions to advance (/faction add NAME)"))
+		return
+	}
+	res := m.journal.FactionTurn(m.deck, m.rng)
+	md, _ := engine.RenderMarkdown(res)
+	tui, _ := engine.RenderTUI(res)
+	cardsMD, cardsTUI := m.takeCardRolls()
+	md, tui = md+cardsMD, tui+cardsTUI
+	m.record(generated{label: "Faction Turn", md: md, tui: tui, entryType: journal.EntryGenerator, trace: res.Trace})
+	m.sidebar.Factions = m.journal.Factions
+}
+
+// promoteNPC puts the latest NPC rolled on the roster as name, with the
+// portrait saved under that name or else a new one.
+func (m *AppModel) promoteNPC(name string) error {
+	if m.lastNPC == nil {
+		return fmt.Errorf("roll an NPC first (/npc), then /roster add NAME")
+	}
+	portrait := engine.GenerateRandomPortrait(m.rng)
+	if saved := m.savedPortraits.FindByName(name); saved != nil {
+		portrait = saved.Params
+	}
+	if err := m.journal.AddNPC(engine.NewRosterNPC(name, *m.lastNPC, portrait)); err != nil {
+		return err
+	}
+	m.lastNPC = nil
+	return nil
+}
+
+func rosterNames(roster []engine.RosterNPC) []string {
+	names := make([]string, len(roster))
+	for i, n := range roster {
+		names[i] = n.Name
+	}
+	return names
+}
+
+func (m *AppModel) openSceneIndex() {
+	m.sceneIndex.SetScenes(m.journal.Scenes())
+	m.showSceneIndex = true
+}
+
+// StartKickoff opens the wizard that writes the Adventure Start section.
+// It needs the results at once, so it can't run in physical mode.
+func (m *AppModel) StartKickoff() {
+	if m.physical != nil {
+		m.showError(fmt.Errorf("the kickoff wizard can't run in physical mode"))
+		return
+	}
+	m.kickoff = NewKickoff(m.savedPortraits, m.rng)
+	m.showKickoff = true
+}
+
+// rollKickoff rolls the wizard's current step, if it is waiting on one.
+func (m *AppModel) rollKickoff() {
+	id, ok := m.kickoff.Pending()
+	if !ok {
+		return
+	}
+	g, _ := engine.LookupGenerator(id)
+	res, err := m.generate(g, nil)
+	if err != nil {
+		m.showKickoff = false
+		m.showError(err)
+		return
+	}
+	m.kickoff.SetPiece(res)
+}
+
+// finishKickoff writes what the wizard kept as the Adventure Start, and
+// saves the characters' new portraits.
+func (m *AppModel) finishKickoff() {
+	var start journal.Start
+	changed := false
+	for _, c := range m.kickoff.characters {
+		start.Characters = append(start.Characters, c.name)
+		if c.changed {
+			m.savedPortraits.Delete(c.name)
+			m.savedPortraits.Add(engine.SavedPortrait{Name: c.name, Params: c.portrait})
+			changed = true
 		}
 	}
+	if changed {
+		engine.SaveSavedPortraits(m.savedPortraits)
+	}
+	for _, g := range m.kickoff.pieces {
+		start.Entries = append(start.Entries, journal.Entry{
+			Type: g.entryType, Label: g.label, Markdown: m.traced(g.label, g.md, g.trace),
+		})
+	}
+	m.journal.StampHouseRules(engine.Overrides())
+	m.journal.SetStart(start)
+	m.saveJournal()
+	m.logview.SetContent("")
+	m.loadExistingEntries()
+	m.logview.ScrollToBottom()
 }
 
-func (m *AppModel) runCommand(cmd CommandMsg) {
+func (m *AppModel) runCommand(cmd CommandMsg) tea.Cmd {
+	g, args, err := engine.LookupCommand(cmd.Command, cmd.Args)
+	if err == nil {
+		return m.runAction(g.ID, args)
+	}
+	if !isAppCommand(cmd.Command) {
+		m.showError(err)
+		return nil
+	}
 	now := time.Now()
 	switch cmd.Command {
-	case "roll", "r":
-		if len(cmd.Args) == 0 {
-			return
+	case "shuffle":
+		m.utilityDeck.Shuffle()
+
+	case "trace":
+		if m.lastTraceLabel == "" {
+			m.statusMsg = "Nothing to trace yet"
+			m.statusExpiry = now.Add(3 * time.Second)
+			return nil
+		}
+		m.refreshLog(RenderTraceTUI(m.lastTraceLabel, m.lastTrace), now, "Engine")
+		return nil
+
+	case "odds":
+		if len(cmd.Args) == 0 || strings.EqualFold(cmd.Args[0], engine.OracleCommand) {
+			bonus := 0
+			if len(cmd.Args) > 1 {
+				b, err := strconv.Atoi(cmd.Args[1])
+				if err != nil {
+					m.showError(fmt.Errorf("usage: /odds oracle [+N|-N]"))
+					return nil
+				}
+				bonus = b
+			}
+			m.refreshLog(RenderOracleOddsTUI(engine.OracleOddsFor(m.sessionConfig.Ladder(), bonus), bonus), now, "Engine")
+			return nil
 		}
-		expr, err := engine.ParseDice(cmd.Args[0])
+		text, target, hasTarget, err := engine.SplitTarget(strings.Join(cmd.Args, " "))
 		if err != nil {
-			return
+			m.showError(fmt.Errorf("usage: /odds EXPR [vs N]: %w", err))
+			return nil
 		}
-		r := engine.RollDice(m.rng, expr)
-		md := journal.RenderDiceRoll(r)
-		tuiStr := RenderDiceRollTUI(r)
-		m.journal.AddEntry(journal.Entry{
-			Timestamp: now, Type: journal.EntryTool, Label: "Dice", Markdown: md,
-		})
-		m.refreshLog(tuiStr, now, "Engine")
+		expr, err := engine.ParseDiceWith(text, m.session().Stats())
+		if err != nil {
+			m.showError(err)
+			return nil
+		}
+		m.refreshLog(RenderOddsTUI(expr, engine.DiceOdds(expr), target, hasTarget), now, "Engine")
+		return nil
 
-	case "flip", "f":
-		count := 1
+	case "system":
 		if len(cmd.Args) > 0 {
-			fmt.Sscanf(cmd.Args[0], "%d", &count)
+			if err := m.setSystem(strings.Join(cmd.Args, " ")); err != nil {
+				m.showError(err)
+				return nil
+			}
 		}
-		r := engine.FlipCoins(m.rng, count)
-		md := journal.RenderCoinFlip(r)
-		tuiStr := RenderCoinFlipTUI(r)
-		m.journal.AddEntry(journal.Entry{
-			Timestamp: now, Type: journal.EntryTool, Label: "Coin Flip", Markdown: md,
-		})
-		m.refreshLog(tuiStr, now, "Engine")
+		m.refreshLog(RenderSystemsTUI(m.system(), m.journal.System != ""), now, "Engine")
+		return nil
 
-	case "draw", "card":
-		count := 1
-		if len(cmd.Args) > 0 {
-			fmt.Sscanf(cmd.Args[0], "%d", &count)
+	case "macro", "macros":
+		if len(cmd.Args) == 0 {
+			m.refreshLog(RenderMacrosTUI(m.savedRolls.Macros), now, "Engine")
+			return nil
 		}
-		r := m.utilityDeck.Draw(count)
-		md := journal.RenderCardDraw(r)
-		tuiStr := RenderCardDrawTUI(r)
-		m.journal.AddEntry(journal.Entry{
-			Timestamp: now, Type: journal.EntryTool, Label: "Card Draw", Markdown: md,
-		})
-		m.refreshLog(tuiStr, now, "Engine")
+		mac, args, ok := m.findMacro(cmd.Args)
+		if !ok {
+			m.showError(fmt.Errorf("no macro named %s", strings.Join(cmd.Args, " ")))
+			return nil
+		}
+		return m.runMacro(mac, args)
 
-	case "shuffle":
-		m.utilityDeck.Shuffle()
+	case "sheet", "sheets":
+		name, changes := splitName(cmd.Args)
+		sheets := m.journal.Sheets
+		if name != "" {
+			sheet := engine.CharacterSheet{Name: name}
+			if i := engine.FindSheet(sheets, name); i >= 0 {
+				sheet = sheets[i]
+				sheet.Stats = slices.Clone(sheet.Stats)
+			}
+			if changes != "" {
+				if err := sheet.Edit(strings.Fields(changes)); err != nil {
+					m.showError(err)
+					return nil
+				}
+				m.journal.SetSheet(sheet)
+				setCharNames(m.journal)
+				m.saveJournal()
+			}
+			sheets = []engine.CharacterSheet{sheet}
+		}
+		m.refreshLog(RenderSheetsTUI(sheets, m.journal.Character()), now, "Engine")
+		return nil
 
-	case "dir", "direction":
-		points := 8
-		if len(cmd.Args) > 0 {
-			fmt.Sscanf(cmd.Args[0], "%d", &points)
+	case "rules":
+		if len(cmd.Args) == 0 || !strings.EqualFold(cmd.Args[0], "diff") {
+			m.statusMsg = "Tables: compiled OPSE (/rules diff [FILE] to compare)"
+			if m.sessionConfig.RulesFile != "" {
+				m.statusMsg = "Tables: " + m.sessionConfig.RulesFile
+			}
+			m.statusExpiry = now.Add(3 * time.Second)
+			return nil
 		}
-		r := engine.RandomDirection(m.rng, points)
-		md := journal.RenderDirection(r)
-		tuiStr := RenderDirectionTUI(r)
-		m.journal.AddEntry(journal.Entry{
-			Timestamp: now, Type: journal.EntryTool, Label: "Direction", Markdown: md,
-		})
-		m.refreshLog(tuiStr, now, "Engine")
+		path := m.sessionConfig.RulesFile
+		if len(cmd.Args) > 1 {
+			path = strings.Join(cmd.Args[1:], " ")
+		} else if path == "" {
+			path = defaultRulesFile
+		}
+		rules, err := engine.LoadRules(path)
+		if err != nil {
+			m.showError(err)
+			return nil
+		}
+		m.refreshLog(RenderRulesDiffTUI(path, rules, engine.DiffRules(rules)), now, "Engine")
+		return nil
 
-	case "weather", "w":
-		m.runAction("weather")
-		return
+	case "mode":
+		if len(cmd.Args) > 0 && !m.setRandomness(strings.ToLower(cmd.Args[0])) {
+			m.statusMsg = fmt.Sprintf("Unknown mode %q (standard, cards, dice, physical)", cmd.Args[0])
+		} else {
+			m.statusMsg = "Randomness: " + m.sessionConfig.Randomness
+		}
+		m.statusExpiry = now.Add(3 * time.Second)
+		return nil
 
-	case "color":
-		m.runAction("color")
-		return
+	case "thread", "threads":
+		sub, name := "list", ""
+		if len(cmd.Args) > 0 {
+			sub, name = strings.ToLower(cmd.Args[0]), strings.Join(cmd.Args[1:], " ")
+		}
+		var err error
+		switch sub {
+		case "add":
+			err = m.journal.AddThread(name)
+		case "close":
+			err = m.journal.CloseThread(name)
+		case "list":
+		default:
+			err = fmt.Errorf("usage: /thread add NAME, /thread close NAME or /thread list")
+		}
+		if err != nil {
+			m.showError(err)
+			return nil
+		}
+		m.sidebar.Threads = engine.OpenThreads(m.journal.Threads)
+		m.saveJournal()
+		m.refreshLog(RenderThreadsTUI(m.journal.Threads), now, "Engine")
+		return nil
 
-	case "sound":
-		cat := ""
+	case "roster":
+		sub, name := "list", ""
 		if len(cmd.Args) > 0 {
-			cat = cmd.Args[0]
+			sub, name = strings.ToLower(cmd.Args[0]), strings.TrimSpace(strings.Join(cmd.Args[1:], " "))
 		}
-		r := engine.RandomSound(m.rng, cat)
-		md := journal.RenderSound(r)
-		tuiStr := RenderSoundTUI(r)
-		m.journal.AddEntry(journal.Entry{
-			Timestamp: now, Type: journal.EntryTool, Label: "Sound", Markdown: md,
-		})
-		m.refreshLog(tuiStr, now, "Engine")
+		var err error
+		switch sub {
+		case "add":
+			err = m.promoteNPC(name)
+		case "remove":
+			err = m.journal.RemoveNPC(name)
+		case "list":
+		default:
+			err = fmt.Errorf("usage: /roster add NAME, /roster remove NAME or /roster list")
+		}
+		if err != nil {
+			m.showError(err)
+			return nil
+		}
+		setCharNames(m.journal)
+		m.saveJournal()
+		m.refreshLog(RenderRosterTUI(m.journal.Roster), now, "Engine")
+		return nil
 
-	case "scene":
-		m.runAction("set_scene")
-		return
+	case "faction", "factions":
+		sub, rest := "list", ""
+		if len(cmd.Args) > 0 {
+			sub, rest = strings.ToLower(cmd.Args[0]), strings.Join(cmd.Args[1:], " ")
+		}
+		if sub == "turn" {
+			m.factionTurn()
+			return nil
+		}
+		var err error
+		switch sub {
+		case "add":
+			var name, goal string
+			var clock int
+			if name, goal, clock, err = engine.SplitFactionAdd(rest); err == nil {
+				if clock == 0 {
+					clock = m.sessionConfig.FactionClock
+				}
+				err = m.journal.AddFaction(name, goal, clock)
+			}
+		case "remove":
+			var name, more string
+			if name, more, err = engine.SplitFactionName(m.journal.Factions, rest); err == nil && more != "" {
+				err = fmt.Errorf("usage: /faction remove NAME")
+			}
+			if err == nil {
+				err = m.journal.RemoveFaction(name)
+			}
+		case "tick":
+			var name, n string
+			ticks := 1
+			if name, n, err = engine.SplitFactionName(m.journal.Factions, rest); err == nil && n != "" {
+				ticks, err = strconv.Atoi(n)
+			}
+			if err == nil {
+				err = m.journal.AdvanceFaction(name, ticks)
+			}
+		case "relate":
+			var a, b, more, stance string
+			if a, more, err = engine.SplitFactionName(m.journal.Factions, rest); err == nil {
+				b, stance, err = engine.SplitFactionName(m.journal.Factions, more)
+			}
+			if err == nil {
+				err = m.journal.RelateFactions(a, b, stance)
+			}
+		case "list":
+		default:
+			err = fmt.Errorf("usage: /faction add NAME [clock: N] [goal: GOAL], remove NAME, tick NAME [N], relate A B STANCE, turn or list")
+		}
+		if err != nil {
+			m.showError(err)
+			return nil
+		}
+		m.sidebar.Factions = m.journal.Factions
+		m.saveJournal()
+		m.refreshLog(RenderFactionsTUI(m.journal.Factions), now, "Engine")
+		return nil
+
+	case "scenes":
+		m.openSceneIndex()
+		return nil
+
+	case "kickoff":
+		if m.journal.Start != nil {
+			m.showError(fmt.Errorf("this adventure has already started"))
+			return nil
+		}
+		m.StartKickoff()
+		return nil
 
 	case "portrait", "portraits":
 		m.showPortraitBrowser = true
 		m.portraitBrowser.SetConfig(m.savedPortraits)
-		return
+		return nil
 
 	case "char":
-		if len(cmd.Args) < 2 {
-			return
-		}
-		raw := strings.Join(cmd.Args, " ")
-		var name, text string
-		if strings.HasPrefix(raw, "\"") {
-			end := strings.Index(raw[1:], "\"")
-			if end < 0 {
-				return
-			}
-			name = raw[1 : end+1]
-			text = strings.TrimSpace(raw[end+2:])
-		} else {
-			name = cmd.Args[0]
-			text = strings.Join(cmd.Args[1:], " ")
-		}
+		name, text := splitName(cmd.Args)
 		if name == "" || text == "" {
-			return
+			return nil
 		}
 		m.journal.AddEntry(journal.Entry{
 			Timestamp: now, Type: journal.EntryNarrative, Label: name, Markdown: text,
 		})
 		m.refreshLog(m.renderCharDialogue(name, text), now, name)
-		return
+		return nil
+
+	}
+	m.saveJournal()
+	return nil
+}
+
+// splitName splits a character name, quoted if it has spaces, from the
+// rest of a command's arguments.
+func splitName(args []string) (name, rest string) {
+	raw := strings.Join(args, " ")
+	if strings.HasPrefix(raw, "\"") {
+		end := strings.Index(raw[1:], "\"")
+		if end < 0 {
+			return "", ""
+		}
+		return raw[1 : end+1], strings.TrimSpace(raw[end+2:])
+	}
+	if len(args) == 0 {
+		return "", ""
+	}
+	return args[0], strings.Join(args[1:], " ")
+}
 
+// traced keeps t for /trace and returns md with the trace appended when
+// the session writes traces to the journal.
+func (m *AppModel) traced(label, md string, t engine.Trace) string {
+	m.lastTraceLabel, m.lastTrace = label, t
+	if m.sessionConfig.TraceInJournal {
+		md += "\n" + journal.RenderTrace(t)
 	}
-	m.journal.Save()
+	return md
+}
+
+// saveJournal snapshots the deck and RNG state into the journal before
+// writing it, so reopening the adventure continues the same shuffle.
+func (m *AppModel) saveJournal() error {
+	m.journal.State = engine.CaptureState(m.rng, m.deck, m.utilityDeck)
+	return m.journal.Save()
 }
 
 func (m *AppModel) addNarrative(text string) {
@@ -568,7 +1140,7 @@ func (m *AppModel) addNarrative(text string) {
 		Timestamp: now, Type: journal.EntryNarrative, Markdown: text,
 	})
 	m.refreshLog(text, now, "User")
-	m.journal.Save()
+	m.saveJournal()
 }
 
 func (m *AppModel) renderCharDialogue(name, text string) string {
@@ -580,6 +1152,9 @@ func (m *AppModel) renderCharDialogue(name, text string) string {
 	if saved := m.savedPortraits.FindByName(name); saved != nil {
 		img := engine.RenderPortraitImage(saved.Params)
 		portrait = PortraitBorderStyle.Render(RenderPortraitArt(img))
+	} else if i := engine.FindNPC(m.journal.Roster, name); i >= 0 {
+		img := engine.RenderPortraitImage(m.journal.Roster[i].Portrait)
+		portrait = PortraitBorderStyle.Render(RenderPortraitArt(img))
 	} else {
 		portrait = RenderEmptyPortraitBox()
 	}
@@ -593,6 +1168,7 @@ func (m *AppModel) renderCharDialogue(name, text string) string {
 }
 
 func (m *AppModel) refreshLog(newTUIEntry string, ts time.Time, source string) {
+	m.failureOffer = nil
 	current := m.logview.content
 	if current != "" {
 		current += "\n\n"
@@ -607,10 +1183,13 @@ func (m *AppModel) refreshLog(newTUIEntry string, ts time.Time, source string) {
 }
 
 func (m *AppModel) loadExistingEntries() {
-	if len(m.journal.Entries) == 0 {
+	if len(m.journal.Entries) == 0 && m.journal.Start == nil {
 		return
 	}
 	var parts []string
+	if m.journal.Start != nil {
+		parts = append(parts, m.renderStart(m.journal.Start))
+	}
 	for _, e := range m.journal.Entries {
 		source := "Engine"
 		if e.Type == journal.EntryNarrative {
@@ -625,6 +1204,9 @@ func (m *AppModel) loadExistingEntries() {
 		if header != "" {
 			entry = header + "\n"
 		}
+		if e.Scene != nil {
+			entry += sceneBanner(*e.Scene) + "\n"
+		}
 		if e.Type == journal.EntryNarrative {
 			if e.Label != "" {
 				entry += m.renderCharDialogue(e.Label, e.Markdown)
@@ -639,6 +1221,34 @@ func (m *AppModel) loadExistingEntries() {
 	m.logview.SetContent(strings.Join(parts, "\n\n"))
 }
 
+// renderStart shows the Adventure Start section: the characters, with
+// their portraits when shown, then each result as loaded.
+func (m *AppModel) renderStart(s *journal.Start) string {
+	lines := []string{ResultLabelStyle.Render("Adventure Start")}
+	if s.HasScene() {
+		lines[0] = sceneBanner(m.journal.Scenes()[0])
+	}
+	if len(s.Characters) > 0 {
+		chars := "Characters: " + strings.Join(s.Characters, ", ")
+		if m.sessionConfig.PortraitsEnabled && SupportsPortraits() {
+			var cols []string
+			for _, name := range s.Characters {
+				portrait := RenderEmptyPortraitBox()
+				if saved := m.savedPortraits.FindByName(name); saved != nil {
+					portrait = PortraitBorderStyle.Render(RenderPortraitArt(engine.RenderPortraitImage(saved.Params)))
+				}
+				cols = append(cols, lipgloss.JoinVertical(lipgloss.Center, portrait, name), " ")
+			}
+			chars = lipgloss.JoinHorizontal(lipgloss.Top, cols...)
+		}
+		lines = append(lines, chars)
+	}
+	for _, e := range s.Entries {
+		lines = append(lines, renderLoadedBlockquote(e.Markdown))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func renderLoadedBlockquote(md string) string {
 	lines := strings.Split(md, "\n")
 	var stripped []string
@@ -702,6 +1312,12 @@ func (m AppModel) View() string {
 	if m.showPortraitBrowser {
 		return m.portraitBrowser.View(m.width, m.height)
 	}
+	if m.showSceneIndex {
+		return m.sceneIndex.View(m.width, m.height)
+	}
+	if m.showKickoff {
+		return m.kickoff.View(m.width, m.height, m.sessionConfig.PortraitsEnabled && SupportsPortraits())
+	}
 	if m.showHelp {
 		return m.help.View(m.width, m.height)
 	}
@@ -742,11 +1358,24 @@ func (m AppModel) View() string {
 	if m.showSaveConfirm {
 		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
 		helpBar = warnStyle.Render(fmt.Sprintf("Overwrite \"%s\"? (y/n)", m.journal.FilePath))
+	} else if m.prompt != nil && m.promptErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+		helpBar = errStyle.Render(m.promptErr + " | Esc: cancel")
+	} else if m.prompt != nil {
+		helpBar = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true).Render(m.promptLine())
 	} else if m.statusMsg != "" && time.Now().Before(m.statusExpiry) {
 		helpBar = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true).Render(m.statusMsg)
 	} else {
 		m.statusMsg = ""
-		helpBar = HelpStyle.Render("Tab: switch | 1-9: generators | /: commands | Ctrl+R: rolls | Ctrl+P: portraits | ?: help | Ctrl+Q: quit")
+		if scenes := m.journal.Scenes(); len(scenes) > 0 {
+			s := scenes[len(scenes)-1]
+			label := fmt.Sprintf("Scene %d", s.Number)
+			if s.Title != "" {
+				label += ": " + s.Title
+			}
+			helpBar = CategoryStyle.Render(label) + " "
+		}
+		helpBar += HelpStyle.Render("Tab: switch | 1-9: generators | /: commands | Ctrl+R: rolls | Ctrl+P: portraits | ?: help | Ctrl+Q: quit")
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, body, helpBar)
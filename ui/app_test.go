@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"opse/engine"
+	"opse/journal"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestApp opens j with the user's config kept in a temporary directory.
+func newTestApp(t *testing.T, j *journal.Journal) AppModel {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	return NewApp(j)
+}
+
+// asApp returns the AppModel behind m, which Update returns by value or,
+// from some paths, by pointer.
+func asApp(m tea.Model) AppModel {
+	if p, ok := m.(*AppModel); ok {
+		return *p
+	}
+	return m.(AppModel)
+}
+
+func TestNewAppReportsBadState(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	j.State = &engine.EngineState{RNG: []byte("not a generator")}
+	m := newTestApp(t, j)
+	if !strings.Contains(m.statusMsg, "can't restore the deck") {
+		t.Errorf("status = %q, want the restore error", m.statusMsg)
+	}
+}
+
+func TestFactionTurnLogsCardRolls(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	m := newTestApp(t, j)
+	m.setRandomness(engine.RandomnessCards)
+	j.AddFaction("The Red Hand", "", 0)
+	m.factionTurn()
+	last := j.Entries[len(j.Entries)-1]
+	if !strings.Contains(last.Markdown, "**Cards:**") {
+		t.Errorf("faction turn = %q, want its card rolls", last.Markdown)
+	}
+	if rolls := m.cardD6.TakeRolls(); len(rolls) != 0 {
+		t.Errorf("%d card rolls left for the next result", len(rolls))
+	}
+}
+
+func TestQuitDuringPhysicalPrompt(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	m := newTestApp(t, j)
+	m.setRandomness(engine.RandomnessPhysical)
+	cmd := m.runAction("oracle_how", nil)
+	model, _ := m.Update(cmd())
+	if m = asApp(model); m.prompt == nil {
+		t.Fatal("no prompt for the d6")
+	}
+	model, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	m = asApp(model)
+	if cmd == nil {
+		t.Fatal("quitting should wait for the cancelled action")
+	}
+	model, cmd = m.Update(cmd())
+	if m = asApp(model); m.physicalBusy || cmd == nil {
+		t.Fatalf("busy = %v after the action finished", m.physicalBusy)
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("the app should quit once the action is cancelled")
+	}
+	if len(j.Entries) != 0 {
+		t.Errorf("the cancelled action was journaled: %+v", j.Entries)
+	}
+}
+
+func TestSavedRollAsksInPhysicalMode(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	m := newTestApp(t, j)
+	m.setRandomness(engine.RandomnessPhysical)
+	m.savedRolls.Add(engine.SavedRoll{ID: "r1", Name: "Sword", Expression: "1d8"})
+	model, _ := m.Update(m.runSavedRoll("r1")())
+	m = asApp(model)
+	if m.prompt == nil || m.prompt.what() != "d8" {
+		t.Fatalf("prompt = %+v, want a d8", m.prompt)
+	}
+	m.input.textarea.SetValue("7")
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = asApp(model)
+	model, _ = m.Update(cmd())
+	m = asApp(model)
+	if len(j.Entries) != 1 || !strings.Contains(j.Entries[0].Markdown, "7") {
+		t.Errorf("entries = %+v, want the d8 of 7", j.Entries)
+	}
+}
+
+func TestReloadKeepsSceneBanners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adventure.md")
+	j := journal.New("Test", path)
+	now := time.Now()
+	j.AddEntry(journal.Entry{Timestamp: now, Type: journal.EntryGenerator, Label: "Set the Scene",
+		Markdown: "> **Set the Scene:** The docks", Scene: &journal.Scene{Number: 1, Title: "The Docks"}})
+	j.AddEntry(journal.Entry{Timestamp: now, Type: journal.EntryNarrative, Markdown: "Fog rolls in."})
+	j.AddEntry(journal.Entry{Timestamp: now, Type: journal.EntryGenerator, Label: "Set the Scene",
+		Markdown: "> **Set the Scene:** The tower", Scene: &journal.Scene{Number: 2, Title: "The Tower", Goal: "Find the key"}})
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := journal.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, _ := newTestApp(t, loaded).Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m := asApp(model)
+	for _, banner := range []string{"━━ Scene 1: The Docks ━━", "━━ Scene 2: The Tower ━━", "Goal: Find the key"} {
+		if !strings.Contains(m.logview.content, banner) {
+			t.Errorf("reloaded log is missing %q", banner)
+		}
+	}
+	if len(m.logview.sceneLines) != 2 {
+		t.Errorf("scene lines = %v, want 2", m.logview.sceneLines)
+	}
+}
+
+func TestSceneTitleEndingInNumber(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	m := newTestApp(t, j)
+	m.runCommand(CommandMsg{Command: "scene", Args: []string{"Chapter", "2"}})
+	scenes := j.Scenes()
+	if len(scenes) != 1 || scenes[0].Title != "Chapter 2" {
+		t.Errorf("scenes = %+v, want one titled Chapter 2", scenes)
+	}
+}
+
+func TestFactionMultiWordNames(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	m := newTestApp(t, j)
+	run := func(args string) {
+		t.Helper()
+		m.runCommand(CommandMsg{Command: "faction", Args: strings.Fields(args)})
+	}
+	run("add Iron Circle goal: Hold the pass")
+	run(`add "The Red Hand"`)
+	run("add Grey Wardens")
+	if len(j.Factions) != 3 || j.Factions[0].Name != "Iron Circle" || j.Factions[0].Goal != "Hold the pass" {
+		t.Fatalf("factions = %+v, want Iron Circle, The Red Hand and Grey Wardens", j.Factions)
+	}
+	run("tick Iron Circle 2")
+	run(`tick "The Red Hand"`)
+	if j.Factions[0].Progress != 2 || j.Factions[1].Progress != 1 {
+		t.Errorf("progress = %d, %d, want 2, 1", j.Factions[0].Progress, j.Factions[1].Progress)
+	}
+	run("relate Iron Circle The Red Hand at war")
+	if got := j.Factions[0].Relations["The Red Hand"]; got != "at war" {
+		t.Errorf("Iron Circle toward The Red Hand = %q, want at war", got)
+	}
+	run(`remove "Grey Wardens"`)
+	run("remove The Red Hand")
+	if len(j.Factions) != 1 || j.Factions[0].Name != "Iron Circle" {
+		t.Errorf("factions = %+v, want only Iron Circle", j.Factions)
+	}
+}
+
+func TestFactionClockSize(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	m := newTestApp(t, j)
+	m.sessionConfig.FactionClock = 4
+	m.runCommand(CommandMsg{Command: "faction", Args: strings.Fields("add Iron Circle")})
+	m.runCommand(CommandMsg{Command: "faction", Args: strings.Fields("add The Red Hand clock: 10 goal: Seize the docks")})
+	if len(j.Factions) != 2 || j.Factions[0].Clock != 4 || j.Factions[1].Clock != 10 {
+		t.Errorf("factions = %+v, want clocks of 4 and 10", j.Factions)
+	}
+}
+
+func TestNewAppShowsConfigError(t *testing.T) {
+	dir := t.TempDir()
+	orig, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(orig)
+	os.WriteFile(".opserc", []byte(`{"trace_in_journal": true, "faction_clock": -1}`), 0644)
+	j := journal.New("Test", filepath.Join(dir, "adventure.md"))
+	m := newTestApp(t, j)
+	if !strings.Contains(m.statusMsg, "faction_clock") {
+		t.Errorf("status = %q, want the config error", m.statusMsg)
+	}
+	if !m.sessionConfig.TraceInJournal {
+		t.Error("the fields that validated should still apply")
+	}
+}
+
+func TestFailureMoveOfferKey(t *testing.T) {
+	j := journal.New("Test", filepath.Join(t.TempDir(), "adventure.md"))
+	m := newTestApp(t, j)
+	m.sessionConfig.CheckBands = []engine.CheckBand{{Label: "Botch", Failure: true}}
+	m.sessionConfig.CheckFailureMove = engine.CheckFailureOffer
+	m.runCommand(CommandMsg{Command: "check"})
+	if m.focus != FocusLog || !strings.Contains(m.logview.content, "Press - ") {
+		t.Fatalf("focus = %v, want the log offering a Failure Move", m.focus)
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+	m = asApp(model)
+	if len(j.Entries) != 1 || !strings.Contains(j.Entries[0].Markdown, "\n> - **Failure Move:** ") {
+		t.Fatalf("entries = %+v, want the Failure Move in the check's entry", j.Entries)
+	}
+	if strings.Contains(m.logview.content, "Press - ") || !strings.Contains(m.logview.content, "Failure Move: ") {
+		t.Errorf("log = %q, want the check block to show its Failure Move", m.logview.content)
+	}
+	m.runCommand(CommandMsg{Command: "failure"})
+	if len(j.Entries) != 2 {
+		t.Errorf("entries = %d, want a second Failure Move as its own entry", len(j.Entries))
+	}
+}
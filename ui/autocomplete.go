@@ -1,15 +1,100 @@
 package ui
 
 import (
+	"slices"
 	"strings"
 
+	"opse/engine"
+	"opse/journal"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
-var allCommands = []string{
-	"roll", "r", "flip", "f", "draw", "card", "shuffle",
-	"dir", "direction", "weather", "w", "color", "sound", "scene",
-	"char", "portrait",
+// appCommand is a slash command handled by the app itself rather than by
+// a registered generator.
+type appCommand struct {
+	name    string
+	aliases []string
+	summary string
+}
+
+var appCommands = []appCommand{
+	{"shuffle", nil, "Reshuffle deck"},
+	{"portrait", []string{"portraits"}, "Portrait browser"},
+	{"char", nil, "Character voice"},
+	{"sheet", []string{"sheets"}, "Character sheets"},
+	{"mode", nil, "Randomness mode"},
+	{"trace", nil, "Trace last result"},
+	{"rules", nil, "Compare rules text"},
+	{"odds", nil, "Dice and oracle odds"},
+	{"system", nil, "Game system preset"},
+	{"macro", []string{"macros"}, "Run a macro"},
+	{"kickoff", nil, "Adventure start wizard"},
+	{"scenes", nil, "Scene index"},
+	{"thread", []string{"threads"}, "Plot threads"},
+	{"roster", nil, "NPC roster"},
+	{"faction", []string{"factions"}, "Faction tracker"},
+}
+
+// macroNames are the saved macros' names, offered after /macro.
+var macroNames []string
+
+func setMacroNames(cfg *engine.SavedRollsConfig) {
+	macroNames = macroNames[:0]
+	for _, mac := range cfg.Macros {
+		macroNames = append(macroNames, mac.Name)
+	}
+}
+
+// charNames are the journal's characters, its sheets' and then its
+// roster's, offered after /char.
+var charNames []string
+
+func setCharNames(j *journal.Journal) {
+	charNames = charNames[:0]
+	for _, c := range j.Sheets {
+		charNames = append(charNames, c.Name)
+	}
+	for _, n := range j.Roster {
+		if !slices.ContainsFunc(charNames, func(name string) bool { return strings.EqualFold(name, n.Name) }) {
+			charNames = append(charNames, n.Name)
+		}
+	}
+}
+
+// defaultRulesFile is the rules text /rules diff reads when none is
+// configured or given.
+const defaultRulesFile = "opse_rules.txt"
+
+var allCommands = commandNames()
+
+// commandNames lists every slash command and alias: the generators' first,
+// then the app's own.
+func commandNames() []string {
+	var names []string
+	for _, g := range engine.Generators() {
+		for _, c := range g.Commands() {
+			if !slices.Contains(names, c) {
+				names = append(names, c)
+			}
+		}
+	}
+	for _, c := range appCommands {
+		names = append(names, c.name)
+		names = append(names, c.aliases...)
+	}
+	return names
+}
+
+func isCommand(name string) bool { return slices.Contains(allCommands, name) }
+
+func isAppCommand(name string) bool {
+	for _, c := range appCommands {
+		if c.name == name || slices.Contains(c.aliases, name) {
+			return true
+		}
+	}
+	return false
 }
 
 type AutocompleteModel struct {
@@ -31,6 +116,16 @@ func fuzzyMatch(s, pattern string) bool {
 }
 
 func (a *AutocompleteModel) Update(text string) {
+	if cmd, rest, ok := strings.Cut(text, " "); ok {
+		switch cmd {
+		case "/macro", "/macros":
+			a.updateNames(cmd, rest, macroNames)
+			return
+		case "/char":
+			a.updateNames(cmd, rest, charNames)
+			return
+		}
+	}
 	if !strings.HasPrefix(text, "/") || strings.Contains(text, " ") {
 		a.visible = false
 		a.suggestions = nil
@@ -72,6 +167,31 @@ func (a *AutocompleteModel) Update(text string) {
 	}
 }
 
+// updateNames suggests the names that start with what follows cmd, such
+// as the macros after /macro, quoted if they have spaces. It hides once a
+// quoted name is closed or a name is typed in full.
+func (a *AutocompleteModel) updateNames(cmd, rest string, names []string) {
+	query := strings.ToLower(strings.TrimPrefix(rest, `"`))
+	var matches []string
+	if !strings.Contains(query, `"`) {
+		for _, name := range names {
+			if strings.HasPrefix(strings.ToLower(name), query) && !strings.EqualFold(name, query) {
+				matches = append(matches, cmd[1:]+" "+quoteName(name))
+			}
+		}
+	}
+	if len(matches) == 0 {
+		a.Hide()
+		return
+	}
+	a.suggestions = matches
+	a.visible = true
+	a.query = cmd + " " + query
+	if a.cursor >= len(matches) {
+		a.cursor = 0
+	}
+}
+
 func (a *AutocompleteModel) MoveUp() {
 	if a.cursor > 0 {
 		a.cursor--
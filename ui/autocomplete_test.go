@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+
+	"opse/engine"
+	"opse/journal"
+)
+
+func TestAutocompleteMacroNames(t *testing.T) {
+	macroNames = []string{"New Scene", "Loot"}
+	defer func() { macroNames = nil }()
+
+	var a AutocompleteModel
+	a.Update("/macro n")
+	if got := a.Complete(); got != `/macro "New Scene" ` {
+		t.Errorf("complete = %q", got)
+	}
+	a.Update("/macro ")
+	if len(a.suggestions) != 2 {
+		t.Errorf("suggestions = %q, want every macro", a.suggestions)
+	}
+	for _, text := range []string{`/macro "New Scene" x`, "/macro loot", "/macro zzz"} {
+		a.Update(text)
+		if a.visible {
+			t.Errorf("%q: suggestions = %q, want none", text, a.suggestions)
+		}
+	}
+}
+
+func TestAutocompleteCharNames(t *testing.T) {
+	j := journal.New("test", "")
+	j.SetSheet(engine.CharacterSheet{Name: "Sir Beans"})
+	if err := j.AddNPC(engine.RosterNPC{Name: "Mara Vell"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.AddNPC(engine.RosterNPC{Name: "sir beans"}); err != nil {
+		t.Fatal(err)
+	}
+	setCharNames(j)
+	defer func() { charNames = nil }()
+
+	if len(charNames) != 2 {
+		t.Fatalf("charNames = %q, want a name once", charNames)
+	}
+	var a AutocompleteModel
+	a.Update("/char m")
+	if got := a.Complete(); got != `/char "Mara Vell" ` {
+		t.Errorf("complete = %q", got)
+	}
+}
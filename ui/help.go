@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"opse/engine"
+
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -106,46 +108,116 @@ func (h *HelpModel) View(width, height int) string {
 }
 
 func buildHelpPages() []helpPage {
-	return []helpPage{
-		{title: "Quick Reference", content: pageQuickRef},
+	pages := []helpPage{
+		{title: "Quick Reference", content: quickRefPage()},
 		{title: "How to Play", content: pageHowToPlay},
 		{title: "The Oracle", content: pageOracle},
 		{title: "Suit Domains", content: pageSuitDomains},
 		{title: "GM Moves", content: pageGMMoves},
 		{title: "Scene Management", content: pageScenes},
 		{title: "Generators", content: pageGenerators},
-		{title: "Commands", content: pageCommands},
+		{title: "Commands", content: commandsPage()},
 		{title: "Tips & About", content: pageTips},
 	}
+	if rules := houseRulesPage(); rules != "" {
+		pages = append(pages[:len(pages)-1], helpPage{title: "House Rules", content: rules}, pages[len(pages)-1])
+	}
+	return pages
+}
+
+// houseRulesPage lists the built-in tables replaced in .opserc, or returns
+// "" when there are none.
+func houseRulesPage() string {
+	overrides := engine.Overrides()
+	if len(overrides) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("HOUSE RULES\n\nThese tables replace the OPSE ones, set by\ntable_overrides in .opserc.\n")
+	for _, o := range overrides {
+		b.WriteString("\n" + strings.ToUpper(o.Name) + "\n")
+		for i, row := range o.Rows {
+			fmt.Fprintf(&b, "  %-3s %s\n", o.Rolls[i], row)
+		}
+	}
+	return b.String()
 }
 
-var pageQuickRef = `KEYBOARD SHORTCUTS
-
-  ORACLE                     SCENE
-  [1] Yes/No (Likely)        [8] Set the Scene
-  [2] Yes/No (Even)          [9] Random Event
-  [3] Yes/No (Unlikely)
-  [4] How                    GM MOVES
-                             [0] Pacing Move
-  FOCUS                      [-] Failure Move
-  [5] Action Focus
-  [6] Detail Focus           GENERATORS
-  [7] Topic Focus            [=] Generic
-
-  NAVIGATION                 COMMANDS
-  Tab    Switch panel         /roll NdS   Roll dice
-  Esc    Back to input        /flip [N]   Flip coins
-  j/k    Scroll up/down       /draw [N]   Draw cards
-  ?      Toggle this help     /weather    Weather
-  Ctrl+S Save journal         /color      Random color
-  Ctrl+R Saved rolls          /sound      Random sound
-  Ctrl+P Portraits            /dir        Direction
-  Ctrl+Q Quit                 /shuffle    Reshuffle deck
-                              /portrait   Portrait browser
-  Number shortcuts work       /scene      Set the Scene
-  from the sidebar or
-  log view (not while
-  typing in the input).`
+var quickRefNavigation = []string{
+	"NAVIGATION",
+	"Tab    Switch panel",
+	"Esc    Back to input",
+	"j/k    Scroll up/down",
+	"?      Toggle this help",
+	"Ctrl+S Save journal",
+	"Ctrl+R Saved rolls",
+	"Ctrl+P Portraits",
+	"Ctrl+Q Quit",
+	"",
+	"Shortcut keys work",
+	"from the sidebar or",
+	"log view (not while",
+	"typing in the input).",
+}
+
+// quickRefPage lays out every generator shortcut and slash command from
+// the registry in two columns.
+func quickRefPage() string {
+	var blocks [][]string
+	lines := -1
+	for _, g := range engine.Generators() {
+		if g.Key == "" {
+			continue
+		}
+		if len(blocks) == 0 || blocks[len(blocks)-1][0] != g.Category {
+			blocks = append(blocks, []string{g.Category})
+			lines += 2
+		}
+		b := &blocks[len(blocks)-1]
+		*b = append(*b, fmt.Sprintf("[%s] %s", g.Key, g.Label))
+		lines++
+	}
+	// Fill the left column up to about half the lines, the right with the rest.
+	var left, right []string
+	for _, b := range blocks {
+		col := &left
+		if len(right) > 0 || len(left) > 0 && len(left)+1+len(b) > (lines+1)/2 {
+			col = &right
+		}
+		if len(*col) > 0 {
+			*col = append(*col, "")
+		}
+		*col = append(*col, b...)
+	}
+
+	commands := []string{"COMMANDS", fmt.Sprintf("%-16s %s", "/"+engine.OracleCommand+" LEVEL", "Yes/No oracle")}
+	for _, g := range engine.Generators() {
+		if g.Command != "" && g.Command != engine.OracleCommand {
+			commands = append(commands, fmt.Sprintf("%-16s %s", strings.TrimSpace("/"+g.Command+" "+g.Sub), g.Label))
+		}
+	}
+	for _, c := range appCommands {
+		commands = append(commands, fmt.Sprintf("%-16s %s", "/"+c.name, c.summary))
+	}
+
+	return "KEYBOARD SHORTCUTS\n\n" + twoColumns(left, right) + "\n" +
+		twoColumns(quickRefNavigation, commands)
+}
+
+func twoColumns(left, right []string) string {
+	var b strings.Builder
+	for i := 0; i < max(len(left), len(right)); i++ {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		b.WriteString(strings.TrimRight(fmt.Sprintf("  %-27s%s", l, r), " ") + "\n")
+	}
+	return b.String()
+}
 
 var pageHowToPlay = `HOW TO PLAY
 
@@ -159,6 +231,8 @@ GETTING STARTED
 3. Run a Random Event [9] for an opening twist.
 4. Set the Scene [8] to establish your first situation.
 5. Start asking the Oracle [1-3] yes/no questions.
+A new adventure can do steps 1-4 for you: say yes to the
+kickoff wizard, or type /kickoff in an empty journal.
 
 BASIC GAMEPLAY LOOP
 • Describe what your character wants to do in the input area.
@@ -329,7 +403,12 @@ surprising event that makes sense in your story's context.
 SCENE FLOW
 1. Describe the situation → Set the Scene [8]
 2. Play through the scene → Oracle, GM Moves as needed
-3. Scene resolves → Set the Scene for next situation`
+3. Scene resolves → Set the Scene for next situation
+
+Each Set the Scene opens the next numbered scene:
+  /scene The Old Road goal: reach the pass
+gives it a title and goal. In the log, [ and ] jump between
+scenes and s (or /scenes) opens the scene index.`
 
 var pageGenerators = `GENERATORS
 
@@ -366,44 +445,144 @@ Generates a hex for wilderness exploration.
   Terrain (d6), Contents (d6), Feature (d6), Event (d6)
   Events may trigger a Random Event and scene.`
 
-var pageCommands = `SLASH COMMANDS
-
-Type these in the input area and press Enter.
-
-DICE ROLLER
-  /roll NdS       Roll N dice with S sides.
-  /roll NdS+M     Add a modifier (+ or -).
-  /roll NdSkH     Roll N, keep highest H.
-  /r NdS          Shorthand for /roll.
-
-  Examples: /roll 2d6, /roll 4d6k3, /r 1d20+5
+// commandsPage lists the registry's slash commands by category, then the
+// app's own commands.
+func commandsPage() string {
+	var b strings.Builder
+	b.WriteString("SLASH COMMANDS\n\nType these in the input area and press Enter.\n")
+	fmt.Fprintf(&b, "A trailing N runs a generator N times (up to %d).\n", engine.MaxRepeat)
+	category := ""
+	for _, g := range engine.Generators() {
+		if g.Command == "" {
+			continue
+		}
+		if g.Category != category {
+			category = g.Category
+			b.WriteString("\n" + category + "\n")
+		}
+		usage := g.Usage()
+		var help []string
+		for _, para := range strings.Split(g.Help, "\n") {
+			help = append(help, wrapWords(para, 44)...)
+		}
+		for _, a := range g.Aliases {
+			help = append(help, "Also /"+a+".")
+		}
+		if len(usage) > 16 {
+			b.WriteString("  " + usage + "\n")
+		} else {
+			fmt.Fprintf(&b, "  %-16s %s\n", usage, help[0])
+			help = help[1:]
+		}
+		for _, h := range help {
+			b.WriteString(strings.Repeat(" ", 19) + h + "\n")
+		}
+	}
+	return b.String() + "\n" + pageAppCommands
+}
 
-COINS
-  /flip            Flip one coin.
-  /flip N          Flip N coins.
-  /f               Shorthand for /flip.
+func wrapWords(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, w := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(w) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	return append(lines, line)
+}
 
-CARDS
-  /draw            Draw one card from the utility deck.
-  /draw N          Draw N cards.
-  /card            Same as /draw.
+var pageAppCommands = `DECK
   /shuffle         Reshuffle the utility deck.
 
-DIRECTION
-  /dir             Random 8-point compass direction.
-  /dir N           N-point compass (4, 8, or 16).
-
-ATMOSPHERE
-  /weather         Random weather (condition, temp, wind).
-  /color           Random color.
-  /sound           Random sound effect.
-  /sound CATEGORY  Sound from a category.
-                   Categories: nature, urban, combat,
-                   social, mechanical, animal, weather,
-                   supernatural, domestic, musical
-
-SCENE
-  /scene           Same as Set the Scene [8].
+RANDOMNESS
+  /mode            Show the current randomness mode.
+  /mode cards      Use only cards: every d6 is a card draw
+                   (rank ÷ 2, round down; Aces discarded).
+                   The cards used are listed under each result.
+  /mode dice       Use only dice: every card is a d12 for rank
+                   (1 = A, 11 = J, 12 = coin: Heads Q, Tails K)
+                   and a d4 for suit (♣ ♦ ♠ ♥). No Jokers.
+  /mode physical   Roll your own dice and draw your own cards.
+                   Each generator prompts for every die,
+                   coin and card it needs (e.g. 4, h, Q♠,
+                   Joker). Only d4-d20 and d100 are asked
+                   for: picks from other lists (colors,
+                   names) stay pseudo-random.
+                   Esc cancels and discards the result.
+  /mode standard   Roll dice and draw cards as normal.
+
+TRACE
+  /trace           Show every die, coin and card behind the
+                   latest result, including Joker reshuffles
+                   and nested random events. Set
+                   "trace_in_journal": true in .opserc to
+                   write each trace into the journal too.
+
+ODDS
+  /odds EXPR       Chart the chance of every total of a dice
+                   expression, with its mean and percentiles.
+                   Add "vs N" for the chance of N or more,
+                   e.g. /odds 4d6kh3 vs 15.
+  /odds oracle     Yes/No oracle chances on each likelihood,
+                   with and... and but...; add +N or -N
+                   for a modifier.
+
+GAME SYSTEMS
+  /system          List the game system presets: osr, pbta,
+                   year-zero, ironsworn and fitd.
+  /system NAME     Switch system and pin it to the journal.
+                   It sets what a bare /check rolls (also
+                   /check +2, which adds dice in year-zero
+                   and fitd), the outcome bands, and adds the
+                   system's rolls to the saved rolls (Ctrl+R).
+  /system off      Unpin; the "system" in .opserc applies.
+
+PLOT THREADS
+  /thread add NAME  Open a plot thread; /thread close NAME
+                   closes it, /thread list (or /threads)
+                   lists them. Open threads show in the
+                   sidebar, and a result that points at a
+                   plot arc (A Plot Arc, Advance a Plot)
+                   names one of them at random.
+
+NPC ROSTER
+  /roster add NAME  Keep the last /npc roll as NAME, with a
+                   portrait; /roster remove NAME drops it,
+                   /roster lists them. A result that calls
+                   on an NPC (An NPC acts suddenly, An NPC
+                   Takes Action) names one at random.
+
+FACTIONS
+  /faction add NAME [clock: N] [goal: GOAL]
+                   Add a faction with an N-segment clock
+                   (default six, or faction_clock in
+                   .opserc); /faction remove NAME drops it.
+  /faction tick NAME [N]
+                   Fill N segments of its clock (or empty
+                   them, if N is negative).
+  /faction relate A B STANCE
+                   Set how two factions stand, both ways.
+                   Names with spaces need no quotes.
+  /faction turn    Advance the factions off-screen: Oracle
+                   (How) gives 0-3 segments, and each one
+                   that advances draws an Action Focus,
+                   aimed at a faction it has a stance to.
+  /faction         List the factions. A result that points
+                   at a faction (A Faction, A powerful
+                   organization, Advance a Threat) names
+                   one at random.
+
+RULES TEXT
+  /rules           Show where the OPSE tables come from.
+  /rules diff      Compare opse_rules.txt (or FILE, or the
+                   configured "rules_file") with the tables
+                   built into the app, row by row.
 
 CHARACTER VOICE & PORTRAITS
   /char NAME TEXT   Add a log entry attributed to NAME.
@@ -413,11 +592,24 @@ CHARACTER VOICE & PORTRAITS
                    Generate random portraits, save favorites
                    with a name. Saved portraits display next
                    to /char dialogue matching that name.
+  /sheet NAME STAT=N  Set stats on NAME's character sheet:
+                   /sheet Elara str=2 skills stealth=3
+                   resources hp=10, then hp-=4. STAT= removes.
+                   /sheet alone lists every sheet.
+                   Roll with them as @str (the last /char
+                   speaker), @elara.str or @"Sir Beans".wits,
+                   e.g. /roll d20+@str.
 
 SAVED ROLLS
   Ctrl+R           Open the saved rolls manager.
                    Create, organize, and execute saved
-                   dice expressions from a modal dialog.`
+                   dice expressions from a modal dialog.
+                   Press m there to make a macro.
+  /macro NAME [ARGS]
+                   Run a macro: its steps, separated by ;
+                   run in order. $name is a parameter,
+                   filled from ARGS. /macro alone lists
+                   them.`
 
 var pageTips = `TIPS FOR BEST RESULTS
 
@@ -15,6 +15,7 @@ type homeState int
 const (
 	homeMenu homeState = iota
 	homeNaming
+	homeKickoff
 	homeBrowsing
 	homeConfirmDelete
 )
@@ -31,6 +32,9 @@ type HomeModel struct {
 	Choice string
 	Title  string
 	Path   string
+	// Kickoff is set when a new adventure should open with the kickoff
+	// wizard.
+	Kickoff bool
 }
 
 func NewHome(files []string) HomeModel {
@@ -61,6 +65,8 @@ func (m HomeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateMenu(msg)
 		case homeNaming:
 			return m.updateNaming(msg)
+		case homeKickoff:
+			return m.updateKickoff(msg)
 		case homeBrowsing:
 			return m.updateBrowsing(msg)
 		case homeConfirmDelete:
@@ -108,9 +114,9 @@ func (m HomeModel) updateNaming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if title == "" {
 			title = "New Adventure"
 		}
-		m.Choice = "new"
 		m.Title = title
-		return m, tea.Quit
+		m.state = homeKickoff
+		return m, nil
 	case "esc":
 		m.state = homeMenu
 		m.textInput.Reset()
@@ -121,6 +127,22 @@ func (m HomeModel) updateNaming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m HomeModel) updateKickoff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		m.Choice = "new"
+		m.Kickoff = true
+		return m, tea.Quit
+	case "n":
+		m.Choice = "new"
+		return m, tea.Quit
+	case "esc":
+		m.state = homeNaming
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m HomeModel) updateBrowsing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "j", "down":
@@ -180,6 +202,8 @@ func (m HomeModel) View() string {
 		content = m.viewMenu()
 	case homeNaming:
 		content = m.viewNaming()
+	case homeKickoff:
+		content = m.viewKickoff()
 	case homeBrowsing, homeConfirmDelete:
 		content = m.viewBrowsing()
 	}
@@ -246,6 +270,26 @@ func (m HomeModel) viewNaming() string {
 		Render(body)
 }
 
+func (m HomeModel) viewKickoff() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("3"))
+
+	title := titleStyle.Render("NEW ADVENTURE")
+	prompt := fmt.Sprintf("Kick off \"%s\" the way the rules begin one?", m.Title)
+	detail := DimStyle.Render("Name the starting characters, then roll a Plot Hook,\na Random Event and Set the Scene, keeping or re-rolling each.")
+
+	help := DimStyle.Render("[y] Kickoff wizard  [n] Empty journal  [Esc] Back")
+
+	body := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s", title, prompt, detail, help)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(2, 4).
+		Render(body)
+}
+
 func (m HomeModel) viewBrowsing() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
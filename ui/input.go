@@ -17,6 +17,8 @@ type NarrativeMsg struct {
 	Text string
 }
 
+const inputPlaceholder = "Write your story... (or /roll 2d6, /flip, /weather)"
+
 type InputModel struct {
 	textarea     textarea.Model
 	autocomplete AutocompleteModel
@@ -25,7 +27,7 @@ type InputModel struct {
 
 func NewInput() InputModel {
 	ta := textarea.New()
-	ta.Placeholder = "Write your story... (or /roll 2d6, /flip, /weather)"
+	ta.Placeholder = inputPlaceholder
 	ta.CharLimit = 10000
 	ta.ShowLineNumbers = false
 	ta.SetHeight(3)
@@ -73,20 +75,16 @@ func (m *InputModel) Submit() tea.Msg {
 	if text == "" {
 		return nil
 	}
+	return parseInput(text)
+}
 
+// parseInput reads a line as a slash command, if it names one, or else as
+// narrative.
+func parseInput(text string) tea.Msg {
 	if strings.HasPrefix(text, "/") {
 		parts := strings.Fields(text)
 		cmd := strings.ToLower(strings.TrimPrefix(parts[0], "/"))
-		known := map[string]bool{
-			"roll": true, "r": true, "flip": true, "f": true,
-			"draw": true, "card": true, "shuffle": true,
-			"dir": true, "direction": true,
-			"weather": true, "w": true,
-			"color": true, "sound": true,
-			"scene": true, "char": true,
-			"portrait": true, "portraits": true,
-		}
-		if known[cmd] {
+		if isCommand(cmd) {
 			return CommandMsg{Command: cmd, Args: parts[1:]}
 		}
 	}
@@ -17,19 +17,9 @@ type KeyMap struct {
 	Save          key.Binding
 	SavedRolls    key.Binding
 	Portraits     key.Binding
-
-	OracleLikely   key.Binding
-	OracleEven     key.Binding
-	OracleUnlikely key.Binding
-	OracleHow      key.Binding
-	ActionFocus    key.Binding
-	DetailFocus    key.Binding
-	TopicFocus     key.Binding
-	SetScene       key.Binding
-	RandomEvent    key.Binding
-	PacingMove     key.Binding
-	FailureMove    key.Binding
-	Generic        key.Binding
+	Scenes        key.Binding
+	PrevScene     key.Binding
+	NextScene     key.Binding
 }
 
 var DefaultKeys = KeyMap{
@@ -47,17 +37,7 @@ var DefaultKeys = KeyMap{
 	Save:          key.NewBinding(key.WithKeys("ctrl+s")),
 	SavedRolls:    key.NewBinding(key.WithKeys("ctrl+r")),
 	Portraits:     key.NewBinding(key.WithKeys("ctrl+p")),
-
-	OracleLikely:   key.NewBinding(key.WithKeys("1")),
-	OracleEven:     key.NewBinding(key.WithKeys("2")),
-	OracleUnlikely: key.NewBinding(key.WithKeys("3")),
-	OracleHow:      key.NewBinding(key.WithKeys("4")),
-	ActionFocus:    key.NewBinding(key.WithKeys("5")),
-	DetailFocus:    key.NewBinding(key.WithKeys("6")),
-	TopicFocus:     key.NewBinding(key.WithKeys("7")),
-	SetScene:       key.NewBinding(key.WithKeys("8")),
-	RandomEvent:    key.NewBinding(key.WithKeys("9")),
-	PacingMove:     key.NewBinding(key.WithKeys("0")),
-	FailureMove:    key.NewBinding(key.WithKeys("-")),
-	Generic:        key.NewBinding(key.WithKeys("=")),
+	Scenes:        key.NewBinding(key.WithKeys("s")),
+	PrevScene:     key.NewBinding(key.WithKeys("[")),
+	NextScene:     key.NewBinding(key.WithKeys("]")),
 }
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"opse/engine"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// kickoffRolls are the generators the kickoff wizard rolls after the
+// characters, in the order How to Play gives them.
+var kickoffRolls = []string{"plot_hook", "random_event", "set_scene"}
+
+type kickoffCharacter struct {
+	name     string
+	portrait engine.PortraitParams
+	changed  bool // the portrait is new, or replaces the saved one
+}
+
+// KickoffModel is a full-screen modal that starts an adventure: it takes
+// the starting characters, giving each a portrait, then rolls each of
+// kickoffRolls to be kept or rolled again. The app does the rolling, for
+// whichever step Pending names.
+type KickoffModel struct {
+	step       int // 0 for the characters, then one per kickoffRolls
+	characters []kickoffCharacter
+	pieces     []*generated
+	portraits  *engine.SavedPortraitsConfig
+	rng        *engine.Randomizer
+	input      textinput.Model
+}
+
+func NewKickoff(portraits *engine.SavedPortraitsConfig, rng *engine.Randomizer) KickoffModel {
+	ti := textinput.New()
+	ti.CharLimit = 64
+	ti.Placeholder = "Character name"
+	ti.Focus()
+	return KickoffModel{
+		pieces:    make([]*generated, len(kickoffRolls)),
+		portraits: portraits,
+		rng:       rng,
+		input:     ti,
+	}
+}
+
+// Pending returns the generator to roll for the current step, if it has
+// no result yet.
+func (m *KickoffModel) Pending() (string, bool) {
+	if m.step < 1 || m.step > len(kickoffRolls) || m.pieces[m.step-1] != nil {
+		return "", false
+	}
+	return kickoffRolls[m.step-1], true
+}
+
+// SetPiece keeps g as the result of the current step.
+func (m *KickoffModel) SetPiece(g generated) {
+	if m.step >= 1 && m.step <= len(kickoffRolls) {
+		m.pieces[m.step-1] = &g
+	}
+}
+
+// Update handles a key and reports whether the wizard is finished.
+func (m *KickoffModel) Update(msg tea.Msg) (bool, tea.Cmd) {
+	kmsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return false, nil
+	}
+	if m.step == 0 {
+		return false, m.updateCharacters(kmsg)
+	}
+	switch kmsg.String() {
+	case "r":
+		m.pieces[m.step-1] = nil
+	case "enter":
+		m.step++
+		if m.step > len(kickoffRolls) {
+			return true, nil
+		}
+	case "backspace", "b":
+		m.step--
+		if m.step == 0 {
+			m.input.Focus()
+			return false, m.input.Cursor.BlinkCmd()
+		}
+	}
+	return false, nil
+}
+
+func (m *KickoffModel) updateCharacters(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		name := strings.TrimSpace(m.input.Value())
+		if name == "" {
+			m.input.Blur()
+			m.step++
+			return nil
+		}
+		c := kickoffCharacter{name: name}
+		if saved := m.portraits.FindByName(name); saved != nil {
+			c.portrait = saved.Params
+		} else {
+			c.portrait, c.changed = engine.GenerateRandomPortrait(m.rng), true
+		}
+		m.characters = append(m.characters, c)
+		m.input.SetValue("")
+		return nil
+	case "ctrl+g":
+		if n := len(m.characters); n > 0 {
+			m.characters[n-1].portrait = engine.GenerateRandomPortrait(m.rng)
+			m.characters[n-1].changed = true
+		}
+		return nil
+	case "backspace":
+		if n := len(m.characters); m.input.Value() == "" && n > 0 {
+			m.characters = m.characters[:n-1]
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
+
+func (m *KickoffModel) View(width, height int, showPortraits bool) string {
+	boxW := width - 4
+	if boxW < 50 {
+		boxW = 50
+	}
+	boxH := height - 2
+	if boxH < 16 {
+		boxH = 16
+	}
+
+	steps := append([]string{"Characters"}, make([]string, len(kickoffRolls))...)
+	for i, id := range kickoffRolls {
+		g, _ := engine.LookupGenerator(id)
+		steps[i+1] = g.Name
+	}
+	header := ResultLabelStyle.Render(fmt.Sprintf("Adventure Start — %d/%d %s", m.step+1, len(steps), steps[m.step]))
+
+	var body, footerText string
+	if m.step == 0 {
+		body = m.viewCharacters(showPortraits)
+		footerText = "Enter: add | Enter on empty: next | Ctrl+G: new portrait | Backspace: remove last | Esc: cancel"
+	} else {
+		if g := m.pieces[m.step-1]; g != nil {
+			body = g.tui
+		}
+		footerText = "Enter: keep | r: re-roll | b: back | Esc: cancel"
+		if m.step == len(kickoffRolls) {
+			footerText = "Enter: keep and begin | r: re-roll | b: back | Esc: cancel"
+		}
+	}
+	footer := DimStyle.Render(footerText)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", body, "", footer)
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(boxW).
+		Height(boxH).
+		Render(content)
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m *KickoffModel) viewCharacters(showPortraits bool) string {
+	var lines []string
+	lines = append(lines, DimStyle.Render("Who are the starting characters? Leave the name empty to move on."))
+	for _, c := range m.characters {
+		lines = append(lines, ItemStyle.Render("  "+c.name)+"  "+DimStyle.Render(engine.DescribePortrait(c.portrait)))
+	}
+	lines = append(lines, "", m.input.View())
+	list := strings.Join(lines, "\n")
+	if !showPortraits || len(m.characters) == 0 {
+		return list
+	}
+	last := m.characters[len(m.characters)-1]
+	portrait := PortraitBorderStyle.Render(RenderPortraitArt(engine.RenderPortraitImage(last.portrait)))
+	return lipgloss.JoinHorizontal(lipgloss.Top, portrait, "  ", list)
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"testing"
+
+	"opse/engine"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKickoffSteps(t *testing.T) {
+	m := NewKickoff(&engine.SavedPortraitsConfig{}, engine.NewSeededRandomizer(1, 0))
+	press := func(keys ...string) (done bool) {
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "backspace":
+				msg = tea.KeyMsg{Type: tea.KeyBackspace}
+			}
+			done, _ = m.Update(msg)
+			if id, ok := m.Pending(); ok {
+				m.SetPiece(generated{label: id})
+			}
+		}
+		return done
+	}
+
+	press("E", "l", "a", "r", "a", "enter", "B", "o", "enter", "backspace")
+	if len(m.characters) != 1 || m.characters[0].name != "Elara" || !m.characters[0].changed {
+		t.Fatalf("characters = %+v", m.characters)
+	}
+	press("enter")
+	if m.pieces[0] == nil || m.pieces[0].label != "plot_hook" {
+		t.Fatalf("plot hook = %+v, want it rolled on reaching its step", m.pieces[0])
+	}
+	press("enter", "b", "r")
+	if m.step != 1 || m.pieces[0] == nil {
+		t.Errorf("step %d: going back and re-rolling should roll the step again", m.step)
+	}
+	if press("enter", "enter") {
+		t.Error("finished before the scene was kept")
+	}
+	if !press("enter") {
+		t.Error("keeping the scene should finish the wizard")
+	}
+}
@@ -1,17 +1,38 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
+	"opse/journal"
+
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/ansi"
 )
 
 type LogViewModel struct {
-	viewport viewport.Model
-	content  string
-	ready    bool
+	viewport   viewport.Model
+	content    string
+	ready      bool
+	sceneLines []int // wrapped lines where a sceneBanner opens a scene
+}
+
+// sceneMarker starts the banner that opens each scene in the log, which
+// is how the log view finds its scenes.
+const sceneMarker = "━━ Scene "
+
+// sceneBanner heads a scene in the log, with its goal under it.
+func sceneBanner(s journal.Scene) string {
+	banner := fmt.Sprintf("%s%d", sceneMarker, s.Number)
+	if s.Title != "" {
+		banner += ": " + s.Title
+	}
+	banner = CategoryStyle.Render(banner + " ━━")
+	if s.Goal != "" {
+		banner += "\n" + DimStyle.Render("Goal: "+s.Goal)
+	}
+	return banner
 }
 
 func NewLogView() LogViewModel {
@@ -47,7 +68,11 @@ func (l *LogViewModel) setWrappedContent() {
 	}
 	lines := strings.Split(l.content, "\n")
 	var wrapped []string
+	l.sceneLines = nil
 	for _, line := range lines {
+		if strings.HasPrefix(ansi.Strip(line), sceneMarker) {
+			l.sceneLines = append(l.sceneLines, len(wrapped))
+		}
 		w := ansi.StringWidth(line)
 		if w > l.viewport.Width {
 			wlines := strings.Split(ansi.Wrap(line, l.viewport.Width, ""), "\n")
@@ -65,6 +90,33 @@ func (l *LogViewModel) ScrollToBottom() {
 	}
 }
 
+// GotoScene scrolls scene i, counting from 0, to the top of the log.
+func (l *LogViewModel) GotoScene(i int) {
+	if l.ready && i >= 0 && i < len(l.sceneLines) {
+		l.viewport.SetYOffset(l.sceneLines[i])
+	}
+}
+
+// JumpScene scrolls to the next scene, or with dir < 0 the previous one.
+func (l *LogViewModel) JumpScene(dir int) {
+	top := l.viewport.YOffset
+	if dir > 0 {
+		for i, line := range l.sceneLines {
+			if line > top {
+				l.GotoScene(i)
+				return
+			}
+		}
+		return
+	}
+	for i := len(l.sceneLines) - 1; i >= 0; i-- {
+		if l.sceneLines[i] < top {
+			l.GotoScene(i)
+			return
+		}
+	}
+}
+
 func (l *LogViewModel) Update(msg tea.Msg) (*LogViewModel, tea.Cmd) {
 	var cmd tea.Cmd
 	l.viewport, cmd = l.viewport.Update(msg)
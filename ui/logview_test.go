@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"opse/journal"
+)
+
+func TestLogViewScenes(t *testing.T) {
+	var l LogViewModel
+	l.SetSize(40, 3)
+	filler := strings.Repeat("text\n", 5)
+	l.SetContent(sceneBanner(journal.Scene{Number: 1}) + "\n" + filler +
+		sceneBanner(journal.Scene{Number: 2, Title: "The Old Road", Goal: "Reach the pass"}) + "\n" + filler + "end")
+	if len(l.sceneLines) != 2 || l.sceneLines[0] != 0 || l.sceneLines[1] != 6 {
+		t.Fatalf("scene lines = %v", l.sceneLines)
+	}
+	l.JumpScene(1)
+	if l.viewport.YOffset != 6 {
+		t.Errorf("next scene: offset %d, want 6", l.viewport.YOffset)
+	}
+	l.JumpScene(-1)
+	if l.viewport.YOffset != 0 {
+		t.Errorf("previous scene: offset %d, want 0", l.viewport.YOffset)
+	}
+}
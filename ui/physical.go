@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"opse/engine"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// physicalRequest asks the player for one card, coin flip or die roll.
+type physicalRequest struct {
+	card  bool
+	coin  bool
+	fudge bool
+	sides int // the die to roll otherwise; 0 is a d6
+	reply chan physicalAnswer
+}
+
+// what names what the player is asked for: "card", "coin", "dF", "d6"...
+func (r physicalRequest) what() string {
+	switch {
+	case r.card:
+		return "card"
+	case r.coin:
+		return "coin"
+	case r.fudge:
+		return "dF"
+	}
+	return fmt.Sprintf("d%d", r.dieSides())
+}
+
+// dieSides is the size of die asked for, when not a card, coin or dF.
+func (r physicalRequest) dieSides() int {
+	if r.sides == 0 {
+		return 6
+	}
+	return r.sides
+}
+
+type physicalAnswer struct {
+	value  int // the die, the Fudge die (-1, 0, 1) or the coin (1 for heads)
+	card   engine.Card
+	cancel bool
+}
+
+// String shows an answer to req as the player gave it.
+func (a physicalAnswer) String(req physicalRequest) string {
+	switch {
+	case req.card:
+		return a.card.String()
+	case req.coin && a.value == 1:
+		return "Heads"
+	case req.coin:
+		return "Tails"
+	case req.fudge:
+		return [3]string{"-", "0", "+"}[a.value+1]
+	}
+	return strconv.Itoa(a.value)
+}
+
+type physicalPromptMsg physicalRequest
+
+type physicalDoneMsg struct {
+	results   []generated
+	err       error
+	cancelled bool
+}
+
+// physicalSource is the DieSource and CardSource used when the player rolls
+// real dice and draws real cards. Generators run on their own goroutine and
+// block in RollD6 or DrawCard until the TUI answers the prompt. After a
+// cancel, the rest of the action is filled in pseudo-randomly and the
+// result is thrown away.
+type physicalSource struct {
+	requests  chan physicalRequest
+	done      chan physicalDoneMsg
+	fallback  *engine.Randomizer
+	cancelled bool
+}
+
+func newPhysicalSource(fallback *engine.Randomizer) *physicalSource {
+	return &physicalSource{
+		requests: make(chan physicalRequest),
+		done:     make(chan physicalDoneMsg),
+		fallback: fallback,
+	}
+}
+
+func (p *physicalSource) ask(req physicalRequest) physicalAnswer {
+	if p.cancelled {
+		return physicalAnswer{cancel: true}
+	}
+	req.reply = make(chan physicalAnswer)
+	p.requests <- req
+	a := <-req.reply
+	p.cancelled = a.cancel
+	return a
+}
+
+// The fallbacks use Intn, not the dice: the fallback's dice are these
+// prompts.
+
+func (p *physicalSource) RollD6() int {
+	if a := p.ask(physicalRequest{}); !a.cancel {
+		return a.value
+	}
+	return p.fallback.Intn(6) + 1
+}
+
+func (p *physicalSource) RollDN(n int) int {
+	if a := p.ask(physicalRequest{sides: n}); !a.cancel {
+		return a.value
+	}
+	return p.fallback.Intn(n) + 1
+}
+
+func (p *physicalSource) RollDF() int {
+	if a := p.ask(physicalRequest{fudge: true}); !a.cancel {
+		return a.value
+	}
+	return p.fallback.Intn(3) - 1
+}
+
+func (p *physicalSource) CoinFlip() bool {
+	if a := p.ask(physicalRequest{coin: true}); !a.cancel {
+		return a.value == 1
+	}
+	return p.fallback.Intn(2) == 0
+}
+
+func (p *physicalSource) DrawCard() engine.DrawResult {
+	if a := p.ask(physicalRequest{card: true}); !a.cancel {
+		return engine.DrawResult{Card: a.card}
+	}
+	return engine.DrawResult{Card: engine.Card{
+		Rank: engine.RankTwo + engine.Rank(p.fallback.Intn(13)),
+		Suit: engine.Suit(p.fallback.Intn(4)),
+	}}
+}
+
+// wait delivers the next prompt, or the finished result, to Update.
+func (p *physicalSource) wait() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case req := <-p.requests:
+			return physicalPromptMsg(req)
+		case done := <-p.done:
+			return done
+		}
+	}
+}
+
+// parsePhysical validates a typed card, coin flip or die value for req.
+func parsePhysical(req physicalRequest, text string) (physicalAnswer, error) {
+	switch {
+	case req.card:
+		c, err := engine.ParseCard(text)
+		if err != nil {
+			return physicalAnswer{}, fmt.Errorf("not a card: %q (try Q♠, 10h, TD, Joker)", text)
+		}
+		return physicalAnswer{card: c}, nil
+	case req.coin:
+		switch strings.ToLower(strings.TrimSpace(text)) {
+		case "h", "heads":
+			return physicalAnswer{value: 1}, nil
+		case "t", "tails":
+			return physicalAnswer{value: 0}, nil
+		}
+		return physicalAnswer{}, fmt.Errorf("not a coin flip: %q (enter h or t)", text)
+	case req.fudge:
+		switch strings.ToLower(strings.TrimSpace(text)) {
+		case "-":
+			return physicalAnswer{value: -1}, nil
+		case "0", "blank":
+			return physicalAnswer{value: 0}, nil
+		case "+":
+			return physicalAnswer{value: 1}, nil
+		}
+		return physicalAnswer{}, fmt.Errorf("not a Fudge die: %q (enter +, 0 or -)", text)
+	}
+	sides := req.dieSides()
+	v, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || v < 1 || v > sides {
+		return physicalAnswer{}, fmt.Errorf("not a %s roll: %q (enter 1-%d)", req.what(), text, sides)
+	}
+	return physicalAnswer{value: v}, nil
+}
+
+// startPhysical runs the action labelled label in the background,
+// prompting for every die, coin and card it consumes.
+//
+// run gets a copy of the model that shares its rng, decks and journal, so
+// the goroutine owns that engine state until it sends on done: the go
+// statement and the done channel order the handoff both ways. In between,
+// Update only touches the prompt and the input, with physicalBusy set,
+// and run only reads the journal.
+func (m *AppModel) startPhysical(label string, run func(gen *AppModel) ([]generated, error)) tea.Cmd {
+	src := m.physical
+	src.cancelled = false
+	m.promptAction = label
+	m.promptValues = nil
+	m.promptErr = ""
+	m.physicalBusy = true
+	gen := *m
+	go func() {
+		results, err := run(&gen)
+		src.done <- physicalDoneMsg{results: results, err: err, cancelled: src.cancelled}
+	}()
+	return src.wait()
+}
+
+// updatePrompt handles keys while a physical action is in flight.
+func (m *AppModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Quit) {
+		// Cancel the action and quit when its goroutine is done with the
+		// engine state; a prompt still to come is cancelled as it arrives.
+		m.quitting = true
+		if m.prompt != nil {
+			m.answerPrompt(physicalAnswer{cancel: true})
+			return m, m.physical.wait()
+		}
+		return m, nil
+	}
+	if m.prompt == nil {
+		return m, nil
+	}
+	switch {
+	case msg.String() == "esc":
+		m.answerPrompt(physicalAnswer{cancel: true})
+		return m, m.physical.wait()
+	case msg.String() == "enter":
+		text := strings.TrimSpace(m.input.textarea.Value())
+		m.input.textarea.Reset()
+		a, err := parsePhysical(*m.prompt, text)
+		if err != nil {
+			m.promptErr = err.Error()
+			return m, nil
+		}
+		m.promptValues = append(m.promptValues, a.String(*m.prompt))
+		m.answerPrompt(a)
+		return m, m.physical.wait()
+	}
+	var cmd tea.Cmd
+	m.input.textarea, cmd = m.input.textarea.Update(msg)
+	return m, cmd
+}
+
+func (m *AppModel) answerPrompt(a physicalAnswer) {
+	m.prompt.reply <- a
+	m.prompt = nil
+	m.promptErr = ""
+	m.input.textarea.Placeholder = inputPlaceholder
+}
+
+func (m *AppModel) showPrompt(req physicalRequest) {
+	m.prompt = &req
+	switch {
+	case req.card:
+		m.input.textarea.Placeholder = "Draw a card and enter it (e.g. Q♠, 10h, Joker)"
+	case req.coin:
+		m.input.textarea.Placeholder = "Flip a coin and enter it (h or t)"
+	case req.fudge:
+		m.input.textarea.Placeholder = "Roll a Fudge die and enter it (+, 0 or -)"
+	default:
+		m.input.textarea.Placeholder = fmt.Sprintf("Roll a %s and enter it (1-%d)", req.what(), req.dieSides())
+	}
+	m.setFocus(FocusInput)
+}
+
+func (m *AppModel) finishPhysical(done physicalDoneMsg) {
+	m.physicalBusy = false
+	m.prompt = nil
+	m.input.textarea.Placeholder = inputPlaceholder
+	if done.cancelled {
+		m.statusMsg = m.promptAction + " cancelled"
+		m.statusExpiry = time.Now().Add(3 * time.Second)
+		return
+	}
+	for _, res := range done.results {
+		m.record(res)
+	}
+	if done.err != nil {
+		m.showError(done.err)
+	}
+}
+
+func (m *AppModel) promptLine() string {
+	what := "d6"
+	if m.prompt != nil {
+		what = m.prompt.what()
+	}
+	line := fmt.Sprintf("%s — enter %s #%d", m.promptAction, what, len(m.promptValues)+1)
+	if len(m.promptValues) > 0 {
+		line += " (so far: " + strings.Join(m.promptValues, ", ") + ")"
+	}
+	return line + " | Enter: submit | Esc: cancel"
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	"opse/engine"
+)
+
+func TestParsePhysical(t *testing.T) {
+	d6 := physicalRequest{}
+	for _, in := range []string{"1", " 6 "} {
+		if _, err := parsePhysical(d6, in); err != nil {
+			t.Errorf("d6 %q: %v", in, err)
+		}
+	}
+	for _, in := range []string{"0", "7", "", "x"} {
+		if _, err := parsePhysical(d6, in); err == nil {
+			t.Errorf("d6 %q should be rejected", in)
+		}
+	}
+
+	card := physicalRequest{card: true}
+	a, err := parsePhysical(card, "10h")
+	if err != nil || a.card != (engine.Card{Rank: engine.RankTen, Suit: engine.Hearts}) {
+		t.Errorf("card 10h = %v, %v", a.card, err)
+	}
+	if _, err := parsePhysical(card, "11h"); err == nil {
+		t.Error("card 11h should be rejected")
+	}
+}
+
+func TestParsePhysicalOtherDice(t *testing.T) {
+	d8 := physicalRequest{sides: 8}
+	if a, err := parsePhysical(d8, "8"); err != nil || a.value != 8 {
+		t.Errorf("d8 8 = %v, %v", a.value, err)
+	}
+	if _, err := parsePhysical(d8, "9"); err == nil {
+		t.Error("d8 9 should be rejected")
+	}
+	coin := physicalRequest{coin: true}
+	if a, err := parsePhysical(coin, "Tails"); err != nil || a.String(coin) != "Tails" {
+		t.Errorf("coin tails = %v, %v", a, err)
+	}
+	fudge := physicalRequest{fudge: true}
+	if a, err := parsePhysical(fudge, "-"); err != nil || a.value != -1 {
+		t.Errorf("dF - = %v, %v", a.value, err)
+	}
+}
+
+func TestPhysicalSourceCancel(t *testing.T) {
+	p := newPhysicalSource(engine.NewRandomizer())
+	go func() {
+		req := <-p.requests
+		req.reply <- physicalAnswer{cancel: true}
+	}()
+	for i := 0; i < 3; i++ {
+		if v := p.RollD6(); v < 1 || v > 6 {
+			t.Fatalf("fallback d6 = %d", v)
+		}
+	}
+	if !p.cancelled {
+		t.Error("expected cancelled")
+	}
+}
@@ -0,0 +1,29 @@
+package ui
+
+import "opse/engine"
+
+func init() {
+	engine.RegisterTUI(RenderOracleYesNoTUI)
+	engine.RegisterTUI(RenderOracleHowTUI)
+	engine.RegisterTUI(RenderCardTableTUI)
+	engine.RegisterTUI(RenderRandomEventTUI)
+	engine.RegisterTUI(RenderSetTheSceneTUI)
+	engine.RegisterTUI(RenderPacingMoveTUI)
+	engine.RegisterTUI(RenderFailureMoveTUI)
+	engine.RegisterTUI(RenderGenericTUI)
+	engine.RegisterTUI(RenderPlotHookTUI)
+	engine.RegisterTUI(RenderNPCTUI)
+	engine.RegisterTUI(RenderFactionTurnTUI)
+	engine.RegisterTUI(RenderDungeonThemeTUI)
+	engine.RegisterTUI(RenderDungeonRoomTUI)
+	engine.RegisterTUI(RenderHexTUI)
+	engine.RegisterTUI(RenderDiceRollTUI)
+	engine.RegisterTUI(RenderCheckTUI)
+	engine.RegisterTUI(RenderCoinFlipTUI)
+	engine.RegisterTUI(RenderCardDrawTUI)
+	engine.RegisterTUI(RenderDirectionTUI)
+	engine.RegisterTUI(RenderWeatherTUI)
+	engine.RegisterTUI(RenderColorTUI)
+	engine.RegisterTUI(RenderSoundTUI)
+	engine.RegisterTUI(RenderTableTUI)
+}
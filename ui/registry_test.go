@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+
+	"opse/engine"
+)
+
+var sampleArgs = map[string][]string{
+	"dice_roller": {"2d6"},
+	"check":       {"2d6", "vs", "8"},
+	"ask":         {"--", "Is the door locked?"},
+}
+
+func TestGeneratorsRenderTUI(t *testing.T) {
+	rng := engine.NewSeededRandomizer(102, 0)
+	s := &engine.Session{Rng: rng, Deck: engine.NewDeck(rng), Utility: engine.NewUtilityDeck(rng, false)}
+	for _, g := range engine.Generators() {
+		res, err := g.Run(s, sampleArgs[g.ID])
+		if err != nil {
+			t.Fatalf("%s: %v", g.ID, err)
+		}
+		if _, ok := engine.RenderTUI(res); !ok {
+			t.Errorf("%s: no TUI renderer for %T", g.ID, res)
+		}
+	}
+}
+
+func TestSidebarAndCommandsFromRegistry(t *testing.T) {
+	sb := NewSidebar()
+	if got, want := sb.totalItems(), len(engine.Generators()); got != want {
+		t.Errorf("sidebar has %d items, want %d", got, want)
+	}
+	for _, g := range engine.Generators() {
+		for _, c := range g.Commands() {
+			if !isCommand(c) {
+				t.Errorf("/%s (%s) missing from commands", c, g.ID)
+			}
+		}
+	}
+}
@@ -2,6 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +31,314 @@ func cardDomain(c engine.Card) string {
 	return DimStyle.Render(c.Domain())
 }
 
+// cardDice shows the rolls behind a card made in dice-only mode.
+func cardDice(d engine.DrawResult) string {
+	if d.Dice == nil {
+		return ""
+	}
+	rank := fmt.Sprintf("d12:%d", d.Dice.RankRoll)
+	if d.Dice.Coin != "" {
+		rank += " " + d.Dice.Coin
+	}
+	return " " + DimStyle.Render(fmt.Sprintf("[%s d4:%d]", rank, d.Dice.SuitRoll))
+}
+
+// RenderCardRollsTUI shows which cards produced the d6 values of the result
+// above it when playing with only cards.
+func RenderCardRollsTUI(rolls []engine.CardRoll) string {
+	parts := make([]string, len(rolls))
+	for i, r := range rolls {
+		var b strings.Builder
+		if r.Joker {
+			b.WriteString(DimStyle.Render("Joker↺") + " ")
+		}
+		if e := r.Event; e != nil {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("(Random Event: %s / %s)", e.Action.Entry, e.Topic.Entry)) + " ")
+		}
+		for _, c := range r.Discarded {
+			b.WriteString(DimStyle.Render(c.String()) + " ")
+		}
+		fmt.Fprintf(&b, "%s→%d", RenderCardForTUI(r.Card), r.Value)
+		parts[i] = b.String()
+	}
+	return " " + DimStyle.Render("Cards:") + " " + strings.Join(parts, "  ")
+}
+
+// RenderTraceTUI shows the trace of the result labelled label, one step per
+// line, for /trace.
+func RenderTraceTUI(label string, t engine.Trace) string {
+	lines := t.Lines()
+	if len(lines) == 0 {
+		lines = []string{DimStyle.Render("(no dice or cards)")}
+	}
+	for i, l := range lines {
+		lines[i] = " " + l
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Trace: "+label) + "\n" + strings.Join(lines, "\n"))
+}
+
+// RenderRulesDiffTUI lists where a rules text differs from the compiled
+// tables.
+func RenderRulesDiffTUI(path string, r engine.Rules, diffs []engine.RulesDiff) string {
+	title := "Rules: " + path
+	if r.Version != "" {
+		title += " (v" + r.Version + ")"
+	}
+	var lines []string
+	for _, d := range diffs {
+		switch {
+		case d.Roll != "":
+			lines = append(lines,
+				fmt.Sprintf(" %s %s:", d.Table, d.Roll),
+				"   rules:    "+d.Rules,
+				"   compiled: "+d.Compiled)
+		case d.Rules == "":
+			lines = append(lines, fmt.Sprintf(" %s: no %s table in the rules text", d.Table, d.Compiled))
+		default:
+			lines = append(lines, fmt.Sprintf(" %s: not a built-in table", d.Table))
+		}
+	}
+	if len(lines) == 0 {
+		lines = []string{DimStyle.Render(" (matches the compiled tables)")}
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render(title) + "\n" + strings.Join(lines, "\n"))
+}
+
+// RenderSystemsTUI lists the game system presets, marking the one in play
+// and whether the journal pins it.
+func RenderSystemsTUI(current *engine.Preset, pinned bool) string {
+	lines := make([]string, 0, len(engine.Presets)+2)
+	for _, p := range engine.Presets {
+		mark := "  "
+		if current != nil && current.ID == p.ID {
+			mark = ResultLabelStyle.Render("▸ ")
+		}
+		check := p.Check
+		if p.Challenge != "" {
+			check += " vs " + p.Challenge
+		}
+		bands := make([]string, len(p.Bands))
+		for i, b := range p.Bands {
+			bands[i] = b.Label
+		}
+		lines = append(lines, fmt.Sprintf(" %s%-10s %s  %s", mark, p.ID, p.Name,
+			DimStyle.Render(fmt.Sprintf("/check %s: %s", check, strings.Join(bands, " / ")))))
+	}
+	status := "No system: /check rolls 2d6 against the configured bands."
+	switch {
+	case current != nil && pinned:
+		status = current.Name + ", pinned to this journal."
+	case current != nil:
+		status = current.Name + ", from .opserc."
+	}
+	lines = append(lines, "", " "+status, DimStyle.Render(" /system NAME switches and pins it to the journal; /system off unpins."))
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Game Systems") + "\n" + strings.Join(lines, "\n"))
+}
+
+// RenderSheetsTUI shows character sheets, stats grouped by kind, marking
+// the current character.
+func RenderSheetsTUI(sheets []engine.CharacterSheet, current string) string {
+	if len(sheets) == 0 {
+		return ResultBlockStyle.Render(ResultLabelStyle.Render("Character Sheets") + "\n" +
+			DimStyle.Render(" No sheets yet. /sheet NAME str=2 skills stealth=1 resources hp=10"))
+	}
+	var lines []string
+	for _, c := range sheets {
+		name := "   " + c.Name
+		if strings.EqualFold(c.Name, current) {
+			name = ResultLabelStyle.Render(" ▸ ") + c.Name + DimStyle.Render(" (@stat)")
+		}
+		lines = append(lines, name)
+		for _, kind := range []string{engine.StatAttribute, engine.StatSkill, engine.StatResource} {
+			var stats []string
+			for _, st := range c.Stats {
+				if st.Kind == kind || (kind == engine.StatAttribute && st.Kind == "") {
+					stats = append(stats, fmt.Sprintf("%s %d", st.Name, st.Value))
+				}
+			}
+			if len(stats) > 0 {
+				lines = append(lines, fmt.Sprintf("     %-11s %s", kind+"s:", strings.Join(stats, " · ")))
+			}
+		}
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Character Sheets") + "\n" + strings.Join(lines, "\n"))
+}
+
+// RenderThreadsTUI lists the plot threads, the open ones first.
+func RenderThreadsTUI(threads []engine.Thread) string {
+	if len(threads) == 0 {
+		return ResultBlockStyle.Render(ResultLabelStyle.Render("Threads") + "\n" +
+			DimStyle.Render(" No threads yet. /thread add NAME"))
+	}
+	var open, closed []string
+	for _, t := range threads {
+		if t.Closed {
+			closed = append(closed, DimStyle.Render("   ✓ "+t.Name))
+		} else {
+			open = append(open, "   • "+t.Name)
+		}
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Threads") + "\n" + strings.Join(append(open, closed...), "\n"))
+}
+
+// RenderRosterTUI lists the NPCs on the roster and what was rolled for
+// each.
+func RenderRosterTUI(roster []engine.RosterNPC) string {
+	if len(roster) == 0 {
+		return ResultBlockStyle.Render(ResultLabelStyle.Render("Roster") + "\n" +
+			DimStyle.Render(" No NPCs yet. Roll one with /npc, then /roster add NAME"))
+	}
+	var lines []string
+	for _, n := range roster {
+		lines = append(lines, "   • "+ResultLabelStyle.Render(n.Name)+"  "+DimStyle.Render(n.Attitude))
+		lines = append(lines, DimStyle.Render(fmt.Sprintf("     Identity: %s · Goal: %s", n.Identity, n.Goal)))
+		if n.Feature != "" {
+			lines = append(lines, DimStyle.Render("     "+n.Feature))
+		}
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Roster") + "\n" + strings.Join(lines, "\n"))
+}
+
+// RenderFactionsTUI lists the factions with their goals, clocks and
+// relations.
+func RenderFactionsTUI(factions []engine.Faction) string {
+	if len(factions) == 0 {
+		return ResultBlockStyle.Render(ResultLabelStyle.Render("Factions") + "\n" +
+			DimStyle.Render(" No factions yet. /faction add NAME [clock: N] [goal: GOAL]"))
+	}
+	var lines []string
+	for _, f := range factions {
+		lines = append(lines, fmt.Sprintf("   %s %s %s", factionClock(f.Progress, f.Clock),
+			ResultLabelStyle.Render(f.Name), DimStyle.Render(fmt.Sprintf("%d/%d", f.Progress, f.Clock))))
+		if f.Goal != "" {
+			lines = append(lines, DimStyle.Render("     Goal: "+f.Goal))
+		}
+		var others []string
+		for other := range f.Relations {
+			others = append(others, other)
+		}
+		slices.Sort(others)
+		for _, other := range others {
+			lines = append(lines, DimStyle.Render(fmt.Sprintf("     %s: %s", other, f.Relations[other])))
+		}
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Factions") + "\n" + strings.Join(lines, "\n"))
+}
+
+// RenderFactionTurnTUI shows what each faction did off-screen.
+func RenderFactionTurnTUI(r engine.FactionTurnResult) string {
+	var lines []string
+	for _, m := range r.Moves {
+		did := DimStyle.Render("no progress")
+		if a := m.Action; a != nil {
+			did = fmt.Sprintf("%s %s — %s%s", RenderCardForTUI(a.Draw.Card), a.Entry, cardDomain(a.Draw.Card), cardDice(a.Draw))
+			if m.Target != "" {
+				did += fmt.Sprintf(" → %s %s", m.Target, DimStyle.Render("("+m.Stance+")"))
+			}
+		}
+		lines = append(lines, fmt.Sprintf(" %s %s: %s", factionClock(m.Progress, m.Clock), m.Faction, did))
+		note := m.How.Result
+		if m.Done() {
+			note += " — goal reached"
+			if m.Goal != "" {
+				note += ": " + m.Goal
+			}
+		}
+		lines = append(lines, DimStyle.Render("   "+note))
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Faction Turn") + "\n" + strings.Join(lines, "\n"))
+}
+
+// factionClock draws a progress clock as a bar, one mark per segment.
+func factionClock(progress, clock int) string {
+	return strings.Repeat("▰", progress) + strings.Repeat("▱", max(clock-progress, 0))
+}
+
+// RenderMacrosTUI lists the saved macros and their steps.
+func RenderMacrosTUI(macros []engine.Macro) string {
+	if len(macros) == 0 {
+		return ResultBlockStyle.Render(ResultLabelStyle.Render("Macros") + "\n" +
+			DimStyle.Render(" No macros yet. Press m in the saved rolls (ctrl+r) to make one."))
+	}
+	var lines []string
+	for _, mac := range macros {
+		lines = append(lines, "   "+quoteName(mac.Name)+DimStyle.Render("  "+strings.Join(mac.Steps, "; ")))
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Macros") + "\n" + strings.Join(lines, "\n"))
+}
+
+// oddsRows is the most rows an odds histogram takes; wider spreads are
+// grouped into ranges.
+const oddsRows = 20
+
+// RenderOddsTUI shows a dice expression's distribution as a histogram,
+// one row per total or range of totals, with the chance of rolling it and
+// of rolling at least its lowest value.
+func RenderOddsTUI(expr engine.DiceExpression, d engine.Distribution, target int, hasTarget bool) string {
+	unit := ""
+	if slices.ContainsFunc(expr.Terms(), engine.DiceTerm.IsPool) {
+		unit = " successes"
+	}
+	title := "Odds: " + expr.Raw
+	if d.Samples > 0 {
+		title += fmt.Sprintf(" (approximate: estimated from %d rolls)", d.Samples)
+	}
+	lines := []string{fmt.Sprintf(" Mean %.2f%s   10%%: %d  25%%: %d  50%%: %d  75%%: %d  90%%: %d",
+		d.Mean(), unit, d.Percentile(0.1), d.Percentile(0.25), d.Percentile(0.5), d.Percentile(0.75), d.Percentile(0.9))}
+	if hasTarget {
+		lines = append(lines, " "+ResultLabelStyle.Render(fmt.Sprintf("P(≥ %d) = %.1f%%", target, 100*d.AtLeast(target))))
+	}
+
+	// Trim the long tails of exploding dice before grouping.
+	lo, hi := d.Percentile(0.0005), d.Percentile(0.9995)
+	step := (hi - lo + oddsRows) / oddsRows
+	type row struct {
+		label   string
+		p       float64
+		atLeast float64
+	}
+	var rows []row
+	var top float64
+	for v := lo; v <= hi; v += step {
+		last := min(v+step-1, hi)
+		label := strconv.Itoa(v)
+		if last > v {
+			label += "-" + strconv.Itoa(last)
+		}
+		var p float64
+		for x := v; x <= last; x++ {
+			p += d.AtLeast(x) - d.AtLeast(x+1)
+		}
+		rows = append(rows, row{label, p, d.AtLeast(v)})
+		top = max(top, p)
+	}
+	lines = append(lines, "", DimStyle.Render(fmt.Sprintf(" %9s %6s %6s", "total", "=", "≥")))
+	for _, r := range rows {
+		bar := strings.Repeat("#", int(math.Round(30*r.p/top)))
+		lines = append(lines, fmt.Sprintf(" %9s %5.1f%% %5.1f%% %s", r.label, 100*r.p, 100*r.atLeast, bar))
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render(title) + "\n" + strings.Join(lines, "\n"))
+}
+
+// RenderOracleOddsTUI shows the yes/no oracle's chances on each rung.
+func RenderOracleOddsTUI(odds []engine.OracleOdds, bonus int) string {
+	title := "Odds: Oracle (Yes/No)"
+	if bonus != 0 {
+		title += fmt.Sprintf(" %+d", bonus)
+	}
+	pct := func(p float64) string { return fmt.Sprintf("%5.1f%%", 100*p) }
+	lines := []string{DimStyle.Render(fmt.Sprintf(" %-18s %-20s %6s %6s %6s %6s %6s %6s",
+		"Likelihood", "Needs", "Yes", "and", "but", "No", "and", "but"))}
+	for _, o := range odds {
+		lines = append(lines, fmt.Sprintf(" %-18s %-20s %s %s %s %s %s %s",
+			o.Likelihood.Name, o.Likelihood.Needs(),
+			pct(o.Yes), pct(o.Yes*o.And), pct(o.Yes*o.But),
+			pct(o.No), pct(o.No*o.And), pct(o.No*o.But)))
+	}
+	lines = append(lines, DimStyle.Render(" and/but: the chance of Yes or No with that modifier."))
+	return ResultBlockStyle.Render(ResultLabelStyle.Render(title) + "\n" + strings.Join(lines, "\n"))
+}
+
 func RenderOracleYesNoTUI(r engine.OracleYesNoResult) string {
 	answer := "No"
 	if r.Answer {
@@ -41,31 +352,45 @@ func RenderOracleYesNoTUI(r engine.OracleYesNoResult) string {
 	if r.Modifier != "" {
 		modStr = r.Modifier
 	}
+	roll := fmt.Sprint(r.AnswerRoll)
+	if r.Bonus != 0 {
+		roll = fmt.Sprintf("%d %+d = %d", r.AnswerRoll, r.Bonus, r.AnswerRoll+r.Bonus)
+	}
+	for _, d := range r.Dropped {
+		roll += DimStyle.Render(fmt.Sprintf(" ~%d~", d))
+	}
 	body := fmt.Sprintf(
-		" Answer: %d → %s\n Modifier: %d → %s\n\n Result: %s",
-		r.AnswerRoll, answer, r.ModRoll, modStr,
+		" Answer: %s (needs %d+) → %s\n Modifier: %d → %s\n\n Result: %s",
+		roll, r.Threshold, answer, r.ModRoll, modStr,
 		ResultLabelStyle.Render(result),
 	)
 	title := fmt.Sprintf("Oracle: Yes/No (%s)", r.Likelihood)
+	if r.Question != "" {
+		body = " Q: " + r.Question + "\n" + body
+	}
 	return ResultBlockStyle.Render(ResultLabelStyle.Render(title) + "\n" + body)
 }
 
 func RenderOracleHowTUI(r engine.OracleHowResult) string {
-	return ResultBlockStyle.Render(ResultLabelStyle.Render("Oracle: How") + "\n " + r.Result)
+	body := " " + r.Result
+	if r.Question != "" {
+		body = " Q: " + r.Question + "\n A: " + r.Result
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render("Oracle: How") + "\n" + body)
 }
 
 func RenderCardTableTUI(r engine.CardTableResult) string {
 	card := RenderCardForTUI(r.Draw.Card)
 	domain := cardDomain(r.Draw.Card)
-	body := fmt.Sprintf(" %s %s — %s", card, r.Entry, domain)
+	body := fmt.Sprintf(" %s %s — %s%s", card, r.Entry, domain, cardDice(r.Draw))
 	return ResultBlockStyle.Render(ResultLabelStyle.Render(r.TableName) + "\n" + body)
 }
 
 func RenderRandomEventTUI(r engine.RandomEventResult) string {
 	body := fmt.Sprintf(
-		" What happens: %s %s — %s\n Involving:    %s %s — %s",
-		RenderCardForTUI(r.Action.Draw.Card), r.Action.Entry, cardDomain(r.Action.Draw.Card),
-		RenderCardForTUI(r.Topic.Draw.Card), r.Topic.Entry, cardDomain(r.Topic.Draw.Card),
+		" What happens: %s %s — %s%s\n Involving:    %s %s — %s%s",
+		RenderCardForTUI(r.Action.Draw.Card), r.Action.Entry, cardDomain(r.Action.Draw.Card), cardDice(r.Action.Draw),
+		RenderCardForTUI(r.Topic.Draw.Card), r.Topic.Entry, cardDomain(r.Topic.Draw.Card), cardDice(r.Topic.Draw),
 	)
 	return ResultBlockStyle.Render(ResultLabelStyle.Render("Random Event") + "\n" + body)
 }
@@ -75,20 +400,19 @@ func RenderSetTheSceneTUI(r engine.SetTheSceneResult) string {
 	fmt.Fprintf(&b, " Complication: %s", r.Complication.Result)
 	if r.Altered && r.AlteredScene != nil {
 		fmt.Fprintf(&b, "\n Altered Scene: %s", r.AlteredScene.Result)
-		if r.AlteredScene.Cascade != nil {
-			switch c := r.AlteredScene.Cascade.(type) {
-			case *engine.SceneComplicationResult:
-				fmt.Fprintf(&b, "\n   → %s", c.Result)
-			case *engine.PacingMoveResult:
-				fmt.Fprintf(&b, "\n   → Pacing Move: %s", c.Result)
-				if c.RandomEvent != nil {
-					fmt.Fprintf(&b, "\n     → Random Event: %s / %s",
-						c.RandomEvent.Action.Entry, c.RandomEvent.Topic.Entry)
-				}
-			case *engine.RandomEventResult:
-				fmt.Fprintf(&b, "\n   → Random Event: %s / %s",
-					c.Action.Entry, c.Topic.Entry)
+		alt := r.AlteredScene
+		switch {
+		case alt.Complication != nil:
+			fmt.Fprintf(&b, "\n   → %s", alt.Complication.Result)
+		case alt.PacingMove != nil:
+			fmt.Fprintf(&b, "\n   → Pacing Move: %s", alt.PacingMove.Result)
+			if evt := alt.PacingMove.RandomEvent; evt != nil {
+				fmt.Fprintf(&b, "\n     → Random Event: %s / %s",
+					evt.Action.Entry, evt.Topic.Entry)
 			}
+		case alt.RandomEvent != nil:
+			fmt.Fprintf(&b, "\n   → Random Event: %s / %s",
+				alt.RandomEvent.Action.Entry, alt.RandomEvent.Topic.Entry)
 		}
 	} else {
 		fmt.Fprintf(&b, "\n %s", DimStyle.Render("(not altered)"))
@@ -115,9 +439,9 @@ func RenderFailureMoveTUI(r engine.FailureMoveResult) string {
 
 func RenderGenericTUI(r engine.GenericGeneratorResult) string {
 	body := fmt.Sprintf(
-		" What it does:    %s %s — %s\n How it looks:    %s %s — %s\n How significant: %s",
-		RenderCardForTUI(r.Action.Draw.Card), r.Action.Entry, cardDomain(r.Action.Draw.Card),
-		RenderCardForTUI(r.Detail.Draw.Card), r.Detail.Entry, cardDomain(r.Detail.Draw.Card),
+		" What it does:    %s %s — %s%s\n How it looks:    %s %s — %s%s\n How significant: %s",
+		RenderCardForTUI(r.Action.Draw.Card), r.Action.Entry, cardDomain(r.Action.Draw.Card), cardDice(r.Action.Draw),
+		RenderCardForTUI(r.Detail.Draw.Card), r.Detail.Entry, cardDomain(r.Detail.Draw.Card), cardDice(r.Detail.Draw),
 		r.Significance.Result,
 	)
 	return ResultBlockStyle.Render(ResultLabelStyle.Render("Generic Generator") + "\n" + body)
@@ -133,21 +457,21 @@ func RenderPlotHookTUI(r engine.PlotHookResult) string {
 
 func RenderNPCTUI(r engine.NPCResult) string {
 	body := fmt.Sprintf(
-		" Identity: %s %s — %s\n Goal:     %s %s — %s\n Feature:  %s — %s %s — %s\n Attitude: %s\n Topic:    %s %s — %s",
-		RenderCardForTUI(r.Identity.Draw.Card), r.Identity.Entry, cardDomain(r.Identity.Draw.Card),
-		RenderCardForTUI(r.Goal.Draw.Card), r.Goal.Entry, cardDomain(r.Goal.Draw.Card),
-		r.Feature, RenderCardForTUI(r.FeatureDetail.Draw.Card), r.FeatureDetail.Entry, cardDomain(r.FeatureDetail.Draw.Card),
+		" Identity: %s %s — %s%s\n Goal:     %s %s — %s%s\n Feature:  %s — %s %s — %s%s\n Attitude: %s\n Topic:    %s %s — %s%s",
+		RenderCardForTUI(r.Identity.Draw.Card), r.Identity.Entry, cardDomain(r.Identity.Draw.Card), cardDice(r.Identity.Draw),
+		RenderCardForTUI(r.Goal.Draw.Card), r.Goal.Entry, cardDomain(r.Goal.Draw.Card), cardDice(r.Goal.Draw),
+		r.Feature, RenderCardForTUI(r.FeatureDetail.Draw.Card), r.FeatureDetail.Entry, cardDomain(r.FeatureDetail.Draw.Card), cardDice(r.FeatureDetail.Draw),
 		r.Attitude.Result,
-		RenderCardForTUI(r.Topic.Draw.Card), r.Topic.Entry, cardDomain(r.Topic.Draw.Card),
+		RenderCardForTUI(r.Topic.Draw.Card), r.Topic.Entry, cardDomain(r.Topic.Draw.Card), cardDice(r.Topic.Draw),
 	)
 	return ResultBlockStyle.Render(ResultLabelStyle.Render("NPC") + "\n" + body)
 }
 
 func RenderDungeonThemeTUI(r engine.DungeonThemeResult) string {
 	body := fmt.Sprintf(
-		" How it looks: %s %s — %s\n How it's used: %s %s — %s",
-		RenderCardForTUI(r.Looks.Draw.Card), r.Looks.Entry, cardDomain(r.Looks.Draw.Card),
-		RenderCardForTUI(r.Used.Draw.Card), r.Used.Entry, cardDomain(r.Used.Draw.Card),
+		" How it looks: %s %s — %s%s\n How it's used: %s %s — %s%s",
+		RenderCardForTUI(r.Looks.Draw.Card), r.Looks.Entry, cardDomain(r.Looks.Draw.Card), cardDice(r.Looks.Draw),
+		RenderCardForTUI(r.Used.Draw.Card), r.Used.Entry, cardDomain(r.Used.Draw.Card), cardDice(r.Used.Draw),
 	)
 	return ResultBlockStyle.Render(ResultLabelStyle.Render("Dungeon Theme") + "\n" + body)
 }
@@ -176,25 +500,43 @@ func RenderHexTUI(r engine.HexResult) string {
 }
 
 func RenderDiceRollTUI(r engine.DiceRollResult) string {
-	parts := make([]string, len(r.Rolls))
-	for i, v := range r.Rolls {
-		if r.Kept[i] {
-			parts[i] = fmt.Sprintf("[%d]", v)
-		} else {
-			parts[i] = DimStyle.Render(fmt.Sprintf(" %d ", v))
-		}
+	body := fmt.Sprintf(" Rolls: %s = %s", r.Breakdown(dieTUI), ResultLabelStyle.Render(r.Outcome()))
+	title := fmt.Sprintf("Dice: %s", r.Expression.Raw)
+	return ResultBlockStyle.Render(ResultLabelStyle.Render(title) + "\n" + body)
+}
+
+func dieTUI(face string, mark engine.DieMark) string {
+	switch mark {
+	case engine.DieDropped:
+		return DimStyle.Render(" " + face + " ")
+	case engine.DieSuccess:
+		return ResultLabelStyle.Render("[" + face + "✓]")
+	case engine.DieFailure:
+		return SuitRedStyle.Render("[" + face + "✗]")
 	}
-	mod := ""
-	if r.Expression.Modifier > 0 {
-		mod = fmt.Sprintf(" + %d", r.Expression.Modifier)
+	return "[" + face + "]"
+}
+
+func RenderCheckTUI(r engine.CheckResult) string {
+	title := "Check: " + r.Roll.Expression.Raw
+	if r.HasTarget {
+		title += fmt.Sprintf(" vs %d", r.Target)
 	}
-	if r.Expression.Modifier < 0 {
-		mod = fmt.Sprintf(" - %d", -r.Expression.Modifier)
+	outcome := ResultLabelStyle.Render(r.Outcome.Label)
+	if r.Outcome.Failure {
+		outcome = SuitRedStyle.Render(r.Outcome.Label)
+	}
+	body := fmt.Sprintf(" Rolls: %s = %s", r.Roll.Breakdown(dieTUI), ResultLabelStyle.Render(r.Roll.Outcome()))
+	if r.Challenge != nil {
+		body += "\n Challenge: " + r.Challenge.Breakdown(dieTUI)
+	}
+	body += "\n Outcome: " + outcome
+	switch {
+	case r.FailureMove != nil:
+		body += "\n Failure Move: " + r.FailureMove.Result
+	case r.Outcome.Failure:
+		body += "\n " + DimStyle.Render("Press - for a Failure Move, or Esc to go on")
 	}
-	body := fmt.Sprintf(" Rolls: %s%s = %s",
-		strings.Join(parts, " "), mod,
-		ResultLabelStyle.Render(fmt.Sprintf("%d", r.Total)))
-	title := fmt.Sprintf("Dice: %s", r.Expression.Raw)
 	return ResultBlockStyle.Render(ResultLabelStyle.Render(title) + "\n" + body)
 }
 
@@ -246,3 +588,18 @@ func RenderSoundTUI(r engine.SoundResult) string {
 	body := fmt.Sprintf(" *%s*  %s", r.Sound, DimStyle.Render("("+r.Category+")"))
 	return ResultBlockStyle.Render(ResultLabelStyle.Render("Random Sound") + "\n" + body)
 }
+
+func RenderTableTUI(r engine.TableResult) string {
+	var body string
+	if r.Draw != nil {
+		body = fmt.Sprintf(" %s %s%s", RenderCardForTUI(r.Draw.Card), r.Entry, cardDice(*r.Draw))
+		if evt := r.Draw.JokerEvent; evt != nil {
+			body += fmt.Sprintf("\n %s Random Event: %s %s / %s %s", DimStyle.Render("Joker↺"),
+				RenderCardForTUI(evt.Action.Draw.Card), evt.Action.Entry,
+				RenderCardForTUI(evt.Topic.Draw.Card), evt.Topic.Entry)
+		}
+	} else {
+		body = fmt.Sprintf(" %s  %s", r.Entry, DimStyle.Render(fmt.Sprintf("(%s: %d)", r.Roll, r.Total)))
+	}
+	return ResultBlockStyle.Render(ResultLabelStyle.Render(r.TableName) + "\n" + body)
+}
@@ -19,6 +19,8 @@ const (
 	srCreatingExpr
 	srCreatingFolder
 	srConfirmDelete
+	srCreatingMacroName
+	srCreatingMacroSteps
 )
 
 type srItem struct {
@@ -27,17 +29,31 @@ type srItem struct {
 	rollID    string
 	label     string
 	folder    string
+	system    bool // one of the game system's rolls, which can't be changed
+	macro     bool // in the macro tree; rollID is then a macro ID
+	depth     int  // folders in the macro tree sit under its root
+}
+
+// key names the folder an item is, or is in, for collapsing. The macro
+// tree's folders are kept apart from the rolls', its root being "macro:".
+func (it srItem) key() string {
+	if it.macro {
+		return "macro:" + it.folder
+	}
+	return it.folder
 }
 
 type SavedRollsModel struct {
 	state     srState
 	config    *engine.SavedRollsConfig
+	system    *engine.Preset  // the game system whose rolls are listed last
 	collapsed map[string]bool // folder name → collapsed
 	items     []srItem
 	cursor    int
 	scrollOff int
 	input     textinput.Model
 	newName   string
+	inMacros  bool // the folder being created is a macro folder
 	errMsg    string
 }
 
@@ -62,7 +78,7 @@ func (m *SavedRollsModel) rebuildItems() {
 		for _, r := range rolls {
 			m.items = append(m.items, srItem{
 				rollID: r.ID,
-				label:  fmt.Sprintf("%s  %s", r.Name, DimStyle.Render(r.Expression)),
+				label:  fmt.Sprintf("%s  %s", r.Name, DimStyle.Render(rollNotation(r))),
 			})
 		}
 	}
@@ -80,13 +96,33 @@ func (m *SavedRollsModel) rebuildItems() {
 			for _, r := range byFolder[folder.Name] {
 				m.items = append(m.items, srItem{
 					rollID: r.ID,
-					label:  fmt.Sprintf("  %s  %s", r.Name, DimStyle.Render(r.Expression)),
+					label:  fmt.Sprintf("  %s  %s", r.Name, DimStyle.Render(rollNotation(r))),
 					folder: folder.Name,
 				})
 			}
 		}
 	}
 
+	if m.system != nil && len(m.system.Rolls) > 0 {
+		folder := m.system.Name
+		isCollapsed := m.collapsed[folder]
+		m.items = append(m.items, srItem{
+			isFolder: true, collapsed: isCollapsed, label: folder + DimStyle.Render(" (system)"),
+			folder: folder, system: true,
+		})
+		if !isCollapsed {
+			for _, r := range m.system.Rolls {
+				m.items = append(m.items, srItem{
+					rollID: r.ID,
+					label:  fmt.Sprintf("  %s  %s", r.Name, DimStyle.Render(rollNotation(r))),
+					folder: folder, system: true,
+				})
+			}
+		}
+	}
+
+	m.rebuildMacros()
+
 	if m.cursor >= len(m.items) {
 		m.cursor = len(m.items) - 1
 	}
@@ -95,11 +131,67 @@ func (m *SavedRollsModel) rebuildItems() {
 	}
 }
 
+// rebuildMacros lists the macro tree after the rolls: a root holding the
+// unsorted macros, then each macro folder.
+func (m *SavedRollsModel) rebuildMacros() {
+	root := srItem{isFolder: true, macro: true, label: "Macros"}
+	root.collapsed = m.collapsed[root.key()]
+	m.items = append(m.items, root)
+	if root.collapsed {
+		return
+	}
+	byFolder := m.config.MacrosByFolder()
+	for _, mac := range byFolder[""] {
+		m.items = append(m.items, macroItem(mac))
+	}
+	for _, folder := range m.config.MacroFolders {
+		item := srItem{isFolder: true, macro: true, depth: 1, label: folder.Name, folder: folder.Name}
+		item.collapsed = m.collapsed[item.key()]
+		m.items = append(m.items, item)
+		if !item.collapsed {
+			for _, mac := range byFolder[folder.Name] {
+				m.items = append(m.items, macroItem(mac))
+			}
+		}
+	}
+}
+
+func macroItem(mac engine.Macro) srItem {
+	indent := "  "
+	if mac.Folder != "" {
+		indent = "    "
+	}
+	return srItem{
+		rollID: mac.ID, macro: true, folder: mac.Folder,
+		label: fmt.Sprintf("%s▶ %s  %s", indent, mac.Name, DimStyle.Render(strings.Join(mac.Steps, "; "))),
+	}
+}
+
 func (m *SavedRollsModel) SetConfig(cfg *engine.SavedRollsConfig) {
 	m.config = cfg
 	m.rebuildItems()
 }
 
+// SetSystem lists the game system's rolls after the saved ones, or none
+// for a nil system.
+func (m *SavedRollsModel) SetSystem(p *engine.Preset) {
+	m.system = p
+	m.rebuildItems()
+}
+
+// anyStat accepts every stat reference when a roll is saved; they are
+// looked up when it is rolled.
+func anyStat(_, _ string) (int, error) { return 1, nil }
+
+// rollNotation shows a saved roll's expression, marking the ones rolled as
+// a check.
+func rollNotation(r engine.SavedRoll) string {
+	if r.Check {
+		return "/check " + r.Expression
+	}
+	return r.Expression
+}
+
 // Update returns a rollID to execute (non-empty when user selects a roll).
 func (m *SavedRollsModel) Update(msg tea.Msg) (string, tea.Cmd) {
 	switch m.state {
@@ -113,6 +205,10 @@ func (m *SavedRollsModel) Update(msg tea.Msg) (string, tea.Cmd) {
 		return m.updateCreatingFolder(msg)
 	case srConfirmDelete:
 		return m.updateConfirmDelete(msg)
+	case srCreatingMacroName:
+		return m.updateCreatingMacroName(msg)
+	case srCreatingMacroSteps:
+		return m.updateCreatingMacroSteps(msg)
 	}
 	return "", nil
 }
@@ -133,15 +229,15 @@ func (m *SavedRollsModel) updateBrowsing(msg tea.Msg) (string, tea.Cmd) {
 		}
 	case "left", "h":
 		if len(m.items) > 0 && m.items[m.cursor].isFolder {
-			m.collapsed[m.items[m.cursor].folder] = true
+			m.collapsed[m.items[m.cursor].key()] = true
 			m.rebuildItems()
-		} else if len(m.items) > 0 && m.items[m.cursor].folder != "" {
+		} else if len(m.items) > 0 && (m.items[m.cursor].folder != "" || m.items[m.cursor].macro) {
 			// Collapse parent folder and move cursor to it
-			m.collapsed[m.items[m.cursor].folder] = true
-			folder := m.items[m.cursor].folder
+			key := m.items[m.cursor].key()
+			m.collapsed[key] = true
 			m.rebuildItems()
 			for i, item := range m.items {
-				if item.isFolder && item.folder == folder {
+				if item.isFolder && item.key() == key {
 					m.cursor = i
 					break
 				}
@@ -149,7 +245,7 @@ func (m *SavedRollsModel) updateBrowsing(msg tea.Msg) (string, tea.Cmd) {
 		}
 	case "right", "l":
 		if len(m.items) > 0 && m.items[m.cursor].isFolder {
-			m.collapsed[m.items[m.cursor].folder] = false
+			m.collapsed[m.items[m.cursor].key()] = false
 			m.rebuildItems()
 		}
 	case "enter":
@@ -157,12 +253,19 @@ func (m *SavedRollsModel) updateBrowsing(msg tea.Msg) (string, tea.Cmd) {
 			item := m.items[m.cursor]
 			if item.isFolder {
 				// Toggle collapse on enter too
-				m.collapsed[item.folder] = !m.collapsed[item.folder]
+				m.collapsed[item.key()] = !m.collapsed[item.key()]
 				m.rebuildItems()
 			} else {
 				return item.rollID, nil
 			}
 		}
+	case "m":
+		m.state = srCreatingMacroName
+		m.input.SetValue("")
+		m.input.Placeholder = "Macro name (e.g. New Scene)"
+		m.input.Focus()
+		m.errMsg = ""
+		return "", m.input.Cursor.BlinkCmd()
 	case "n":
 		m.state = srCreatingName
 		m.input.SetValue("")
@@ -172,13 +275,14 @@ func (m *SavedRollsModel) updateBrowsing(msg tea.Msg) (string, tea.Cmd) {
 		return "", m.input.Cursor.BlinkCmd()
 	case "f":
 		m.state = srCreatingFolder
+		m.inMacros = len(m.items) > 0 && m.items[m.cursor].macro
 		m.input.SetValue("")
 		m.input.Placeholder = "Folder name"
 		m.input.Focus()
 		m.errMsg = ""
 		return "", m.input.Cursor.BlinkCmd()
 	case "d":
-		if len(m.items) > 0 {
+		if len(m.items) > 0 && !m.items[m.cursor].system && m.items[m.cursor].key() != "macro:" {
 			m.state = srConfirmDelete
 		}
 	}
@@ -217,7 +321,7 @@ func (m *SavedRollsModel) updateCreatingExpr(msg tea.Msg) (string, tea.Cmd) {
 			if expr == "" {
 				return "", nil
 			}
-			if _, err := engine.ParseDice(expr); err != nil {
+			if _, err := engine.ParseDiceWith(expr, anyStat); err != nil {
 				m.errMsg = "Invalid dice expression"
 				return "", nil
 			}
@@ -225,7 +329,7 @@ func (m *SavedRollsModel) updateCreatingExpr(msg tea.Msg) (string, tea.Cmd) {
 			folder := ""
 			if len(m.items) > 0 && m.cursor < len(m.items) {
 				item := m.items[m.cursor]
-				if item.isFolder || item.folder != "" {
+				if (item.isFolder || item.folder != "") && !item.system && !item.macro {
 					folder = item.folder
 				}
 			}
@@ -251,6 +355,88 @@ func (m *SavedRollsModel) updateCreatingExpr(msg tea.Msg) (string, tea.Cmd) {
 	return "", cmd
 }
 
+func (m *SavedRollsModel) updateCreatingMacroName(msg tea.Msg) (string, tea.Cmd) {
+	if kmsg, ok := msg.(tea.KeyMsg); ok {
+		switch kmsg.String() {
+		case "enter":
+			name := strings.TrimSpace(m.input.Value())
+			if name == "" {
+				return "", nil
+			}
+			if _, ok := m.config.FindMacro(name); ok {
+				m.errMsg = "A macro with that name already exists"
+				return "", nil
+			}
+			m.newName = name
+			m.state = srCreatingMacroSteps
+			m.input.SetValue("")
+			m.input.Placeholder = "Steps separated by ; (e.g. /scene; /event; /weather)"
+			m.errMsg = ""
+			return "", nil
+		case "esc":
+			m.state = srBrowsing
+			m.errMsg = ""
+			return "", nil
+		}
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return "", cmd
+}
+
+func (m *SavedRollsModel) updateCreatingMacroSteps(msg tea.Msg) (string, tea.Cmd) {
+	if kmsg, ok := msg.(tea.KeyMsg); ok {
+		switch kmsg.String() {
+		case "enter":
+			steps := engine.ParseMacroSteps(m.input.Value())
+			if len(steps) == 0 {
+				return "", nil
+			}
+			if err := checkMacroSteps(steps); err != nil {
+				m.errMsg = err.Error()
+				return "", nil
+			}
+			folder := ""
+			if len(m.items) > 0 && m.cursor < len(m.items) && m.items[m.cursor].macro {
+				folder = m.items[m.cursor].folder
+			}
+			m.config.AddMacro(engine.Macro{
+				ID: m.config.NextMacroID(), Name: m.newName, Folder: folder, Steps: steps,
+			})
+			engine.SaveSavedRolls(m.config)
+			m.rebuildItems()
+			m.state = srBrowsing
+			m.errMsg = ""
+			return "", nil
+		case "esc":
+			m.state = srBrowsing
+			m.errMsg = ""
+			return "", nil
+		}
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return "", cmd
+}
+
+// checkMacroSteps rejects a step that names an unknown command or runs
+// another macro.
+func checkMacroSteps(steps []string) error {
+	for _, step := range steps {
+		if !strings.HasPrefix(step, "/") {
+			continue
+		}
+		cmd := strings.ToLower(strings.TrimPrefix(strings.Fields(step)[0], "/"))
+		switch {
+		case cmd == "macro":
+			return fmt.Errorf("a macro can't run another macro")
+		case !isCommand(cmd):
+			return fmt.Errorf("unknown command /%s", cmd)
+		}
+	}
+	return nil
+}
+
 func (m *SavedRollsModel) updateCreatingFolder(msg tea.Msg) (string, tea.Cmd) {
 	if kmsg, ok := msg.(tea.KeyMsg); ok {
 		switch kmsg.String() {
@@ -259,7 +445,11 @@ func (m *SavedRollsModel) updateCreatingFolder(msg tea.Msg) (string, tea.Cmd) {
 			if name == "" {
 				return "", nil
 			}
-			m.config.AddFolder(name)
+			if m.inMacros {
+				m.config.AddMacroFolder(name)
+			} else {
+				m.config.AddFolder(name)
+			}
 			engine.SaveSavedRolls(m.config)
 			m.rebuildItems()
 			m.state = srBrowsing
@@ -280,10 +470,16 @@ func (m *SavedRollsModel) updateConfirmDelete(msg tea.Msg) (string, tea.Cmd) {
 		case "y":
 			if len(m.items) > 0 {
 				item := m.items[m.cursor]
-				if item.isFolder {
+				switch {
+				case item.isFolder && item.macro:
+					m.config.DeleteMacroFolder(item.folder)
+					delete(m.collapsed, item.key())
+				case item.isFolder:
 					m.config.DeleteFolder(item.folder)
 					delete(m.collapsed, item.folder)
-				} else {
+				case item.macro:
+					m.config.DeleteMacro(item.rollID)
+				default:
 					m.config.Delete(item.rollID)
 				}
 				engine.SaveSavedRolls(m.config)
@@ -318,7 +514,11 @@ func (m *SavedRollsModel) View(width, height int) string {
 		if m.state == srConfirmDelete && len(m.items) > 0 {
 			item := m.items[m.cursor]
 			warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
-			if item.isFolder {
+			if item.isFolder && item.macro {
+				count := len(m.config.MacrosByFolder()[item.folder])
+				body += "\n" + warnStyle.Render(
+					fmt.Sprintf("Delete macro folder \"%s\" and its %d macro(s)? (y/n)", item.folder, count))
+			} else if item.isFolder {
 				count := m.config.FolderRollCount(item.folder)
 				if count > 0 {
 					body += "\n" + warnStyle.Render(
@@ -327,6 +527,10 @@ func (m *SavedRollsModel) View(width, height int) string {
 					body += "\n" + warnStyle.Render(
 						fmt.Sprintf("Delete folder \"%s\"? (y/n)", item.folder))
 				}
+			} else if item.macro {
+				mac, _ := m.config.Macro(item.rollID)
+				body += "\n" + warnStyle.Render(
+					fmt.Sprintf("Delete macro \"%s\"? (y/n)", mac.Name))
 			} else {
 				body += "\n" + warnStyle.Render(
 					fmt.Sprintf("Delete \"%s\"? (y/n)", item.label))
@@ -345,15 +549,31 @@ func (m *SavedRollsModel) View(width, height int) string {
 			body += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.errMsg)
 		}
 	case srCreatingFolder:
+		title := "New Folder — Name:"
+		if m.inMacros {
+			title = "New Macro Folder — Name:"
+		}
 		body = m.viewBrowse(contentH - 3)
-		body += "\n" + ResultLabelStyle.Render("New Folder — Name:") + "\n" + m.input.View()
+		body += "\n" + ResultLabelStyle.Render(title) + "\n" + m.input.View()
+	case srCreatingMacroName:
+		body = m.viewBrowse(contentH - 3)
+		body += "\n" + ResultLabelStyle.Render("New Macro — Name:") + "\n" + m.input.View()
+		if m.errMsg != "" {
+			body += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.errMsg)
+		}
+	case srCreatingMacroSteps:
+		body = m.viewBrowse(contentH - 3)
+		body += "\n" + ResultLabelStyle.Render(fmt.Sprintf("New Macro \"%s\" — Steps ($name for a parameter):", m.newName)) + "\n" + m.input.View()
+		if m.errMsg != "" {
+			body += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.errMsg)
+		}
 	}
 
 	var footerText string
 	switch m.state {
 	case srBrowsing:
-		footerText = "j/k: navigate | ←/→: collapse/expand | Enter: roll | n: new | f: folder | d: delete | Esc: close"
-	case srCreatingName, srCreatingExpr, srCreatingFolder:
+		footerText = "j/k: navigate | ←/→: collapse/expand | Enter: roll/run | n: new roll | m: new macro | f: folder | d: delete | Esc: close"
+	case srCreatingName, srCreatingExpr, srCreatingFolder, srCreatingMacroName, srCreatingMacroSteps:
 		footerText = "Enter: confirm | Esc: cancel"
 	case srConfirmDelete:
 		footerText = "y: confirm | n: cancel"
@@ -386,7 +606,11 @@ func (m *SavedRollsModel) viewBrowse(maxLines int) string {
 			if item.collapsed {
 				arrow = "▸"
 			}
-			prefix := arrow + " 📁 " + item.label
+			icon := " 📁 "
+			if item.key() == "macro:" {
+				icon = " 📜 "
+			}
+			prefix := strings.Repeat("  ", item.depth) + arrow + icon + item.label
 			if i == m.cursor {
 				line = SrSelectedStyle.Render(prefix)
 			} else {
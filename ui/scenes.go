@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"opse/journal"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SceneIndexModel is a full-screen modal listing the adventure's scenes,
+// to jump the log to one of them.
+type SceneIndexModel struct {
+	scenes    []journal.Scene
+	cursor    int
+	scrollOff int
+}
+
+// SetScenes lists scenes, with the cursor on the latest.
+func (m *SceneIndexModel) SetScenes(scenes []journal.Scene) {
+	m.scenes = scenes
+	m.cursor = max(len(scenes)-1, 0)
+}
+
+// Update handles a key and returns the index of the scene chosen, or -1.
+func (m *SceneIndexModel) Update(msg tea.Msg) int {
+	kmsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return -1
+	}
+	switch kmsg.String() {
+	case "j", "down":
+		if m.cursor < len(m.scenes)-1 {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "enter":
+		if len(m.scenes) > 0 {
+			return m.cursor
+		}
+	}
+	return -1
+}
+
+func (m *SceneIndexModel) View(width, height int) string {
+	boxW := width - 4
+	if boxW < 40 {
+		boxW = 40
+	}
+	boxH := height - 2
+	if boxH < 10 {
+		boxH = 10
+	}
+	contentH := boxH - 4 // padding(2) + header(1) + footer(1)
+
+	header := ResultLabelStyle.Render("Scenes")
+	footer := DimStyle.Render("j/k: navigate | Enter: jump to scene | Esc: close")
+
+	var body string
+	if len(m.scenes) == 0 {
+		body = DimStyle.Render("No scenes yet. Set the Scene [8] or /scene TITLE goal: GOAL opens one.")
+	} else {
+		var lines []string
+		for i, s := range m.scenes {
+			label := fmt.Sprintf("Scene %d", s.Number)
+			if s.Title != "" {
+				label += ": " + s.Title
+			}
+			if s.Goal != "" {
+				label += "  " + DimStyle.Render("— "+s.Goal)
+			}
+			if i == m.cursor {
+				lines = append(lines, SrSelectedStyle.Render("▸ "+label))
+			} else {
+				lines = append(lines, ItemStyle.Render("  "+label))
+			}
+		}
+		maxLines := max(contentH-2, 1)
+		if m.cursor < m.scrollOff {
+			m.scrollOff = m.cursor
+		}
+		if m.cursor >= m.scrollOff+maxLines {
+			m.scrollOff = m.cursor - maxLines + 1
+		}
+		end := min(m.scrollOff+maxLines, len(lines))
+		body = strings.Join(lines[m.scrollOff:end], "\n")
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", body, footer)
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(boxW).
+		Height(boxH).
+		Render(content)
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -3,6 +3,8 @@ package ui
 import (
 	"fmt"
 	"strings"
+
+	"opse/engine"
 )
 
 type SidebarItem struct {
@@ -18,52 +20,24 @@ type SidebarCategory struct {
 
 type SidebarModel struct {
 	Categories []SidebarCategory
+	Threads    []string         // open plot threads, listed under the generators
+	Factions   []engine.Faction // listed with their clocks after the threads
 	cursor     int
 	height     int
 	scrollOff  int
 }
 
+// NewSidebar lists every registered generator under its category.
 func NewSidebar() SidebarModel {
-	return SidebarModel{
-		Categories: []SidebarCategory{
-			{Name: "ORACLE", Items: []SidebarItem{
-				{"Yes/No (Likely)", "1", "oracle_likely"},
-				{"Yes/No (Even)", "2", "oracle_even"},
-				{"Yes/No (Unlikely)", "3", "oracle_unlikely"},
-				{"How", "4", "oracle_how"},
-			}},
-			{Name: "FOCUS", Items: []SidebarItem{
-				{"Action", "5", "focus_action"},
-				{"Detail", "6", "focus_detail"},
-				{"Topic", "7", "focus_topic"},
-			}},
-			{Name: "SCENE", Items: []SidebarItem{
-				{"Set the Scene", "8", "set_scene"},
-				{"Random Event", "9", "random_event"},
-			}},
-			{Name: "GM MOVES", Items: []SidebarItem{
-				{"Pacing Move", "0", "pacing_move"},
-				{"Failure Move", "-", "failure_move"},
-			}},
-			{Name: "GENERATORS", Items: []SidebarItem{
-				{"Generic", "=", "generic"},
-				{"Plot Hook", "", "plot_hook"},
-				{"NPC", "", "npc"},
-				{"Dungeon Theme", "", "dungeon_theme"},
-				{"Dungeon Room", "", "dungeon_room"},
-				{"Hex", "", "hex"},
-			}},
-			{Name: "TOOLS", Items: []SidebarItem{
-				{"Dice Roller", "/", "dice_roller"},
-				{"Coin Flip", "", "coin_flip"},
-				{"Card Draw", "", "card_draw"},
-				{"Direction", "", "direction"},
-				{"Weather", "", "weather"},
-				{"Color", "", "color"},
-				{"Sound", "", "sound"},
-			}},
-		},
+	var cats []SidebarCategory
+	for _, g := range engine.Generators() {
+		if len(cats) == 0 || cats[len(cats)-1].Name != g.Category {
+			cats = append(cats, SidebarCategory{Name: g.Category})
+		}
+		c := &cats[len(cats)-1]
+		c.Items = append(c.Items, SidebarItem{g.Label, g.Key, g.ID})
 	}
+	return SidebarModel{Categories: cats}
 }
 
 func (s *SidebarModel) flatItems() []SidebarItem {
@@ -125,6 +99,18 @@ func (s *SidebarModel) View(width, height int, focused bool) string {
 		}
 		lines = append(lines, "")
 	}
+	if len(s.Threads) > 0 {
+		lines = append(lines, CategoryStyle.Render("THREADS"))
+		for _, t := range s.Threads {
+			lines = append(lines, DimStyle.Render("  • "+t))
+		}
+	}
+	if len(s.Factions) > 0 {
+		lines = append(lines, CategoryStyle.Render("FACTIONS"))
+		for _, f := range s.Factions {
+			lines = append(lines, DimStyle.Render("  "+factionClock(f.Progress, f.Clock)+" "+f.Name))
+		}
+	}
 
 	// Scroll handling: height includes padding (2) but not border
 	contentHeight := height - 2